@@ -6,17 +6,29 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	addOpts           generator.AddModuleOptions
+	addContextOpts    generator.AddContextOptions
+	addDataSourceOpts generator.AddDataSourceOptions
+)
+
 var addCmd = &cobra.Command{
 	Use:   "add <module-name>",
 	Short: "添加新的业务模块",
 	Long: `在现有项目中添加一个新的 Application 业务模块。
 
 该命令会创建一个新的 application-<module-name> 模块，包括：
-  - 完整的目录结构（service, dto, assembler, executor）
+  - 完整的目录结构（service, dto, assembler, executor, listener）
   - 模块 pom.xml
   - 示例 Service 类
   - 自动更新父 pom.xml 的 modules 和 dependencyManagement
 
+可选项：
+  --wire             将新模块添加为 adapter-rest 的依赖
+  --with-controller  在 adapter-rest 中生成示例 Controller（隐含 --wire）
+  --with-listener    生成示例事件监听器
+  --with-executor    生成示例 Executor，Service 委托给它执行
+
 模块名称格式要求：
   - 只能包含小写字母、数字和连字符
   - 必须以小写字母开头
@@ -26,13 +38,89 @@ var addCmd = &cobra.Command{
   phjvgen add payment        # 创建 application-payment 模块
   phjvgen add order          # 创建 application-order 模块
   phjvgen add user-profile   # 创建 application-user-profile 模块
+  phjvgen add order --with-controller --with-listener --with-executor
+  phjvgen add context billing   # 创建 billing 限界上下文
+  phjvgen add datasource reporting --readonly  # 添加只读副本
 
 注意：必须在项目根目录（包含 pom.xml 的目录）下运行此命令。`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		moduleName := args[0]
 
-		if err := generator.AddApplicationModule(moduleName); err != nil {
+		if err := generator.AddApplicationModule(moduleName, addOpts); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+var addContextCmd = &cobra.Command{
+	Use:   "context <context-name>",
+	Short: "添加新的限界上下文",
+	Long: `在现有项目中添加一个新的限界上下文（Bounded Context）。
+
+一个限界上下文由三个模块组成：
+  - domain-<context-name>           领域模型、仓储接口、领域服务和领域事件
+  - infrastructure-<context-name>   仓储实现、Mapper 和数据对象
+  - application/application-<context-name>  应用服务
+
+依赖关系：
+  application-<context-name>    → domain-<context-name>, common
+  infrastructure-<context-name> → domain-<context-name>, common
+  domain-<context-name>         → common
+
+该命令还会：
+  - 更新父 pom.xml 的 modules 和 dependencyManagement
+  - 将 infrastructure-<context-name> 加入 starter 的依赖
+  - 将新的 Mapper 包加入 Application 类的 @MapperScan
+
+可选项：
+  --wire  将 application-<context-name> 添加为 adapter-rest 的依赖
+
+使用示例：
+  phjvgen add context billing
+  phjvgen add context inventory --wire
+
+注意：必须在项目根目录或其子目录下运行此命令。`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.AddBoundedContext(args[0], addContextOpts); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+var addDataSourceCmd = &cobra.Command{
+	Use:   "datasource <name>",
+	Short: "添加数据源或只读副本",
+	Long: `在现有项目中添加一个数据源。添加第一个数据源时会在
+infrastructure/config/datasource 下生成主数据源配置：主库连接沿用
+spring.datasource，并由读写分离路由数据源包装，只读事务
+（@Transactional(readOnly = true)）轮询访问只读副本。
+
+--readonly 添加主库的只读副本：
+  - 配置在各 profile 文件的 app.datasource.replicas.<name> 下
+  - 与主库共用 Mapper，无需修改业务代码
+
+不加 --readonly 时添加一个独立的数据源（仅支持 MyBatis-Plus）：
+  - 生成 <Name>DataSourceConfig，包含 DataSource、SqlSessionFactory 和事务管理器
+  - Mapper 放在 infrastructure.persistence.<name>.mapper 包，XML 放在 resources/mapper-<name>
+  - 配置在各 profile 文件的 app.datasource.<name> 下
+
+连接通过 <NAME>_DB_URL、<NAME>_DB_USERNAME、<NAME>_DB_PASSWORD 环境变量配置；
+主库连接有默认值的 profile 中，未配置时连接主库。
+
+使用示例：
+  phjvgen add datasource reporting --readonly
+  phjvgen add datasource archive
+
+注意：必须在项目根目录或其子目录下运行此命令。`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.AddDataSource(args[0], addDataSourceOpts); err != nil {
 			utils.PrintError(err.Error())
 			return err
 		}
@@ -41,5 +129,15 @@ var addCmd = &cobra.Command{
 }
 
 func init() {
+	addDataSourceCmd.Flags().BoolVar(&addDataSourceOpts.ReadOnly, "readonly", false, "添加主库的只读副本，只读事务路由到该副本")
+	addCmd.AddCommand(addDataSourceCmd)
+
+	addContextCmd.Flags().BoolVar(&addContextOpts.Wire, "wire", false, "将应用模块添加为 adapter-rest 的依赖")
+	addCmd.AddCommand(addContextCmd)
+
+	addCmd.Flags().BoolVar(&addOpts.Wire, "wire", false, "将新模块添加为 adapter-rest 的依赖")
+	addCmd.Flags().BoolVar(&addOpts.WithController, "with-controller", false, "生成示例 Controller（隐含 --wire）")
+	addCmd.Flags().BoolVar(&addOpts.WithListener, "with-listener", false, "生成示例事件监听器")
+	addCmd.Flags().BoolVar(&addOpts.WithExecutor, "with-executor", false, "生成示例 Executor")
 	rootCmd.AddCommand(addCmd)
 }
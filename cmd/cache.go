@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/phixia/phjvgen/internal/generator"
+	"github.com/phixia/phjvgen/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var cacheOpts generator.CacheOptions
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache <Entity>",
+	Short: "为实体仓储生成两级缓存",
+	Long: `为领域层 <Entity>Repository 生成缓存装饰器，包括：
+  - infrastructure/cache/Caching<Entity>Repository：包装 <Entity>RepositoryImpl
+    并标注 @Primary，注入仓储接口的地方都会经过缓存
+  - infrastructure/cache/TwoLevelCache：本地 Caffeine 缓存在前、Redis 在后的两级
+    缓存，各实体的装饰器共用（首次生成时创建）
+
+Optional<Entity> findByXxx 形式的单条查询会被缓存，键的格式为
+<应用名>:<缓存名>:<字段>:<值>，例如 demo-app:user:id:1。save、update、
+deleteById 清除该实体修改前后的全部缓存键，并通过 Redis 主题通知其他实例
+清除本地缓存；其余写操作清除整个缓存。缓存的命中情况以 cache.gets 等指标
+暴露给 Micrometer。
+
+同时会：
+  - 为 infrastructure 添加 Redisson、Caffeine 和 Micrometer 依赖
+  - 在各环境的 application-<profile>.yml 中添加 spring.data.redis 连接配置
+  - 在 application.yml 中添加 app.cache.<缓存名>.ttl
+
+使用示例：
+  phjvgen cache User
+  phjvgen cache User --ttl 30m`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.GenerateCache(args[0], cacheOpts); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.Flags().StringVar(&cacheOpts.TTL, "ttl", "10m", "Redis 中缓存的有效期，例如 30s、10m、1h")
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"github.com/phixia/phjvgen/internal/generator"
+	"github.com/phixia/phjvgen/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	depAddOpts    generator.DependencyOptions
+	depRemoveOpts generator.DependencyOptions
+)
+
+var depCmd = &cobra.Command{
+	Use:   "dep",
+	Short: "管理第三方依赖",
+	Long: `从内置的依赖目录中添加、移除和查看第三方依赖。
+
+依赖目录随 phjvgen 一起发布，无需联网。每个依赖都定义了坐标、版本属性
+以及允许添加到的模块，例如 Spring Starter 不能添加到 domain 模块。
+
+使用示例：
+  phjvgen dep list                               # 查看可用依赖
+  phjvgen dep add redisson --to infrastructure   # 添加依赖
+  phjvgen dep remove redisson                    # 从所有模块移除依赖
+
+注意：必须在项目根目录或其子目录下运行 add 和 remove 命令。`,
+}
+
+var depAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "向模块添加依赖",
+	Long: `向指定模块添加依赖目录中的依赖。
+
+该命令会：
+  - 检查依赖是否允许添加到目标模块
+  - Maven 项目：在父 pom.xml 中添加版本属性和 dependencyManagement 声明
+  - Gradle 项目：在 gradle/libs.versions.toml 中添加版本和库声明
+  - 在目标模块的构建文件中添加依赖（不带版本号）
+
+由 Spring Boot BOM 管理版本的依赖不会添加版本声明。
+
+使用示例：
+  phjvgen dep add redisson --to infrastructure
+  phjvgen dep add security --to adapter-rest
+  phjvgen dep add spring-test --to application-user`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.AddDependency(args[0], depAddOpts); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+var depRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "从模块移除依赖",
+	Long: `从模块中移除依赖目录中的依赖，是 dep add 的逆操作。
+
+不指定 --from 时从所有模块移除。当没有模块再使用该依赖时，
+父 pom.xml 或版本目录中的版本声明也会一并移除。
+
+使用示例：
+  phjvgen dep remove redisson
+  phjvgen dep remove redisson --from infrastructure`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.RemoveDependency(args[0], depRemoveOpts); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+var depListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "查看可用依赖",
+	Long:    `列出依赖目录中的所有依赖。在项目目录中运行时，还会显示每个依赖已添加到哪些模块。`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.ListDependencies(); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	depAddCmd.Flags().StringVar(&depAddOpts.Module, "to", "", "目标模块，例如 infrastructure、adapter-rest、application-user")
+	_ = depAddCmd.MarkFlagRequired("to")
+	depRemoveCmd.Flags().StringVar(&depRemoveOpts.Module, "from", "", "只从该模块移除（默认从所有模块移除）")
+
+	depCmd.AddCommand(depAddCmd, depRemoveCmd, depListCmd)
+	rootCmd.AddCommand(depCmd)
+}
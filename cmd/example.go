@@ -8,6 +8,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var exampleFlags projectFlags
+
 var exampleCmd = &cobra.Command{
 	Use:   "example",
 	Short: "快速生成示例项目",
@@ -23,6 +25,8 @@ var exampleCmd = &cobra.Command{
 
 生成后可以直接进入目录构建和运行项目。
 
+使用 --build gradle 可以生成 Gradle（Kotlin DSL）构建的示例项目。
+
 适用场景：
   - 快速测试和学习项目结构
   - CI/CD 集成测试
@@ -44,6 +48,9 @@ var exampleCmd = &cobra.Command{
 			PackagePath:        "com/example/demo",
 			OutputDir:          "./demo-app",
 		}
+		if err := exampleFlags.apply(config); err != nil {
+			return err
+		}
 
 		// Display configuration
 		utils.PrintInfo("项目配置信息：")
@@ -53,6 +60,7 @@ var exampleCmd = &cobra.Command{
 		fmt.Printf("  Project Name: %s\n", config.ProjectName)
 		fmt.Printf("  Package Name: %s\n", config.PackageName)
 		fmt.Printf("  Output Directory: %s\n", config.OutputDir)
+		fmt.Printf("  Build Tool: %s\n", config.BuildTool)
 		fmt.Println()
 
 		// Auto-confirm
@@ -72,8 +80,8 @@ var exampleCmd = &cobra.Command{
 		fmt.Println()
 		utils.PrintInfo("示例项目已生成！可以使用以下命令快速开始：")
 		fmt.Println("  cd demo-app")
-		fmt.Println("  mvn clean install")
-		fmt.Println("  java --enable-preview -jar starter/target/starter-1.0.0.jar")
+		fmt.Printf("  %s\n", config.BuildCommand())
+		fmt.Printf("  java --enable-preview -jar %s\n", config.StarterJar())
 		fmt.Println()
 
 		return nil
@@ -81,5 +89,6 @@ var exampleCmd = &cobra.Command{
 }
 
 func init() {
+	exampleFlags.register(exampleCmd)
 	rootCmd.AddCommand(exampleCmd)
 }
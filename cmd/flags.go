@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/phixia/phjvgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// projectFlags holds the generation options shared by generate and example
+type projectFlags struct {
+	build        string
+	mavenVersion string
+	enforcer     bool
+	profiles     []string
+	database     string
+	persistence  string
+	migration    string
+	multiTenant  bool
+	idStrategy   string
+}
+
+// register adds the flags to a command
+func (f *projectFlags) register(c *cobra.Command) {
+	c.Flags().StringVar(&f.build, "build", generator.BuildToolMaven, "构建工具: maven 或 gradle")
+	c.Flags().StringVar(&f.mavenVersion, "maven-version", generator.DefaultMavenVersion, "Maven Wrapper 使用的 Maven 版本")
+	c.Flags().BoolVar(&f.enforcer, "enforcer", false, "生成 maven-enforcer-plugin 分层依赖规则（仅 Maven）")
+	c.Flags().StringVar(&f.database, "db", generator.DefaultDatabase, "数据库: mysql、postgres、mariadb 或 h2")
+	c.Flags().StringVar(&f.persistence, "persistence", generator.DefaultPersistence, "持久层: mybatis-plus、jpa 或 jdbc")
+	c.Flags().StringVar(&f.migration, "migration", generator.DefaultMigration, "数据库迁移工具: flyway、liquibase 或 none")
+	c.Flags().BoolVar(&f.multiTenant, "multi-tenant", false, "启用多租户：按 tenant_id 行级隔离数据（仅 MyBatis-Plus）")
+	c.Flags().StringVar(&f.idStrategy, "id-strategy", generator.DefaultIDStrategy, "主键策略: auto（数据库自增）、snowflake 或 uuidv7")
+	c.Flags().StringSliceVar(&f.profiles, "profiles", generator.DefaultProfiles, "Spring profile 列表，第一个为默认 profile")
+}
+
+// validate checks the flag values, so generate can fail before prompting
+func (f *projectFlags) validate() error {
+	if err := generator.ValidateBuildTool(f.build); err != nil {
+		return err
+	}
+	if err := generator.ValidateProfiles(f.profiles); err != nil {
+		return err
+	}
+	if err := generator.ValidateDatabase(f.database); err != nil {
+		return err
+	}
+	if err := generator.ValidatePersistence(f.persistence); err != nil {
+		return err
+	}
+	if err := generator.ValidateMigration(f.migration); err != nil {
+		return err
+	}
+	if f.multiTenant {
+		if err := generator.ValidateMultiTenant(f.persistence); err != nil {
+			return err
+		}
+	}
+	if err := generator.ValidateIDStrategy(f.idStrategy); err != nil {
+		return err
+	}
+	if f.build != generator.BuildToolMaven {
+		if f.enforcer {
+			return fmt.Errorf("--enforcer 仅支持 Maven 项目")
+		}
+		return nil
+	}
+	return generator.ValidateMavenVersion(f.mavenVersion)
+}
+
+// apply validates the flags and copies them into the project configuration
+func (f *projectFlags) apply(config *generator.ProjectConfig) error {
+	if err := f.validate(); err != nil {
+		return err
+	}
+	config.BuildTool = f.build
+	config.Profiles = f.profiles
+	config.Database = f.database
+	config.Persistence = f.persistence
+	config.Migration = f.migration
+	config.MultiTenant = f.multiTenant
+	config.IDStrategy = f.idStrategy
+	if f.build == generator.BuildToolMaven {
+		config.MavenVersion = f.mavenVersion
+		config.Enforcer = f.enforcer
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var generateFlags projectFlags
+
 var generateCmd = &cobra.Command{
 	Use:     "generate",
 	Aliases: []string{"gen", "g"},
@@ -13,19 +15,39 @@ var generateCmd = &cobra.Command{
 	Long: `生成一个新的基于 Java 25 LTS 的分层架构项目。
 
 该命令会交互式地询问项目配置信息，然后生成完整的项目结构，包括：
-  - Maven 多模块结构
+  - Maven 多模块结构（使用 --build gradle 生成 Gradle Kotlin DSL 构建）
   - 分层架构（common, domain, infrastructure, adapter, application, starter）
   - 基础代码（Application启动类、Result响应封装、异常处理等）
-  - 配置文件（application.yml）
+  - 配置文件（application.yml 及各环境的 application-<profile>.yml）
+  - Maven Wrapper（mvnw、mvnw.cmd），无需预先安装 Maven
   - README 和 .gitignore
 
-生成后的项目可以直接使用 Maven 构建和运行。`,
+生成后的项目可以直接使用 Maven Wrapper 或 Gradle 构建和运行。
+
+使用示例：
+  phjvgen generate                            # 生成 Maven 项目
+  phjvgen generate --maven-version 3.9.9      # 指定 Maven Wrapper 使用的 Maven 版本
+  phjvgen generate --enforcer                 # 生成 maven-enforcer-plugin 分层依赖规则
+  phjvgen generate --profiles dev,staging,prod # 自定义 Spring profile，第一个为默认
+  phjvgen generate --db postgres              # 使用 PostgreSQL（可选 mysql、postgres、mariadb、h2）
+  phjvgen generate --persistence jpa          # 使用 Spring Data JPA（可选 mybatis-plus、jpa、jdbc）
+  phjvgen generate --migration liquibase      # 使用 Liquibase 执行迁移脚本（可选 flyway、liquibase、none）
+  phjvgen generate --multi-tenant             # 按 tenant_id 行级隔离各租户的数据
+  phjvgen generate --id-strategy snowflake    # 主键由雪花算法在插入前分配（可选 auto、snowflake、uuidv7）
+  phjvgen generate --build gradle             # 生成 Gradle 项目`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generateFlags.validate(); err != nil {
+			return err
+		}
+
 		// Get project configuration
 		config, err := generator.GetProjectConfig()
 		if err != nil {
 			return err
 		}
+		if err := generateFlags.apply(config); err != nil {
+			return err
+		}
 
 		// Generate project
 		if err := generator.GenerateProject(config); err != nil {
@@ -40,5 +62,6 @@ var generateCmd = &cobra.Command{
 }
 
 func init() {
+	generateFlags.register(generateCmd)
 	rootCmd.AddCommand(generateCmd)
 }
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/phixia/phjvgen/internal/generator"
+	"github.com/phixia/phjvgen/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var graphOpts generator.GraphOptions
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "导出模块依赖图并检查分层方向",
+	Long: `解析所有模块的 pom.xml（Gradle 项目为 build.gradle.kts），导出模块间的依赖图。
+
+模块按 phjvgen 创建的分层角色分组（common、domain、infrastructure、application、
+adapter、starter），违反分层方向的依赖会以红色标出，例如 domain 依赖 infrastructure、
+application 依赖 adapter。分层规则与 --enforcer 生成的 maven-enforcer-plugin 规则一致。
+
+使用示例：
+  phjvgen graph                          # 输出 DOT 格式
+  phjvgen graph --format mermaid         # 输出 Mermaid 格式
+  phjvgen graph -o deps.dot              # 写入文件
+  phjvgen graph --check                  # 存在违反分层方向的依赖时以非零状态码退出
+
+注意：必须在项目根目录或其子目录下运行此命令。`,
+	Args: cobra.NoArgs,
+	// A failed check is a result, not a usage error
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.Graph(graphOpts); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	graphCmd.Flags().StringVar(&graphOpts.Format, "format", generator.GraphFormatDOT, "输出格式: dot 或 mermaid")
+	graphCmd.Flags().StringVarP(&graphOpts.Output, "output", "o", "", "输出文件，默认输出到标准输出")
+	graphCmd.Flags().BoolVar(&graphOpts.Check, "check", false, "只检查分层方向，存在违规依赖时返回非零状态码")
+	rootCmd.AddCommand(graphCmd)
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"github.com/phixia/phjvgen/internal/generator"
+	"github.com/phixia/phjvgen/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var migrationCmd = &cobra.Command{
+	Use:   "migration",
+	Short: "管理数据库迁移脚本",
+	Long: `管理 infrastructure/src/main/resources/db/migration 下的数据库迁移脚本。
+
+使用示例：
+  phjvgen migration new "add order table"
+  phjvgen migration diff
+  phjvgen migration mark-applied
+
+注意：必须在项目根目录或其子目录下运行此命令。`,
+}
+
+var migrationNewCmd = &cobra.Command{
+	Use:   "new <description>",
+	Short: "新建下一个版本的迁移脚本",
+	Long: `按现有脚本的最大版本号新建下一个迁移脚本，例如 V2__add_order_table.sql，
+文件头记录描述和创建时间。
+
+新建前会检查：
+  - 是否有多个脚本使用同一版本号（例如不同分支各自新建了 V3）
+  - infrastructure/migration.sum 中记录的脚本是否被修改或删除。修改已执行过的
+    脚本会导致 Flyway 或 Liquibase 启动时校验和不一致
+
+新建脚本不会修改 migration.sum，脚本在共享环境执行后，使用
+phjvgen migration mark-applied 记录。Liquibase 项目还会把新脚本加入
+db/changelog/db.changelog-master.yaml。
+
+使用示例：
+  phjvgen migration new "add order table"
+  phjvgen migration new add_user_avatar`,
+	Args: cobra.ExactArgs(1),
+	// A collision or an edited script is a result, not a usage error
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.NewMigration(args[0]); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+var migrationMarkAppliedCmd = &cobra.Command{
+	Use:   "mark-applied [script...]",
+	Short: "将迁移脚本记录为已应用",
+	Long: `把迁移脚本的校验和记录到 infrastructure/migration.sum。之后 migration new
+和 migration diff 会拒绝在这些脚本被修改或删除时继续，避免 Flyway 或
+Liquibase 启动时校验和不一致。
+
+在脚本已经在测试、生产等共享环境执行后运行，并将 migration.sum 提交到版本库。
+不指定脚本时记录全部脚本；脚本可以用文件名或版本号指定。只有生成的文件头、
+还没有 SQL 语句的脚本会被跳过。
+
+使用示例：
+  phjvgen migration mark-applied
+  phjvgen migration mark-applied V2 V3
+  phjvgen migration mark-applied V2__add_order_table.sql`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.MarkMigrationsApplied(args); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+var allowDestructive bool
+
+var migrationDiffCmd = &cobra.Command{
+	Use:   "diff [description]",
+	Short: "根据实体定义的变更生成迁移脚本",
+	Long: `比较项目清单 phjvgen.json 中的实体定义（entities）与表结构快照
+（schemaSnapshot），把差异写成下一个版本的迁移脚本，并更新快照。
+
+支持的变更：
+  - 新建或删除表
+  - 新增、删除列，修改列的类型、非空、默认值和注释
+  - 新增或删除索引、唯一键
+
+删除表或列、把列改为更窄的类型（例如缩短 VARCHAR）可能丢失数据，
+需要确认后加 --allow-destructive 才会生成。列和索引按名称匹配，
+重命名会被视为先删除再新增。
+
+不指定描述时按变更的表命名，例如 V2__alter_t_user.sql。脚本的编号和
+校验规则与 migration new 相同。
+
+使用示例：
+  phjvgen migration diff
+  phjvgen migration diff "add user avatar"
+  phjvgen migration diff --allow-destructive`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		description := ""
+		if len(args) > 0 {
+			description = args[0]
+		}
+		if err := generator.DiffMigration(description, allowDestructive); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	migrationDiffCmd.Flags().BoolVar(&allowDestructive, "allow-destructive", false, "允许生成删除表、列或收窄列类型的迁移")
+	migrationCmd.AddCommand(migrationNewCmd)
+	migrationCmd.AddCommand(migrationDiffCmd)
+	migrationCmd.AddCommand(migrationMarkAppliedCmd)
+	rootCmd.AddCommand(migrationCmd)
+}
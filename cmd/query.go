@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"github.com/phixia/phjvgen/internal/generator"
+	"github.com/phixia/phjvgen/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var queryOpts generator.QueryOptions
+
+var queryCmd = &cobra.Command{
+	Use:   "query <Entity> <method>",
+	Short: "为实体生成自定义查询",
+	Long: `为实体生成写在 MyBatis Mapper XML 中的自定义查询，包括：
+  - resources/mapper/<Entity>Mapper.xml 中的 <select>（首次生成时创建该文件，
+    并按 phjvgen.json 中的表定义生成 BaseResultMap 和 Base_Column_List）
+  - <Entity>Mapper 中的方法
+  - 领域层 <Entity>Repository 中的方法，以及 <Entity>RepositoryImpl 中委托给
+    Mapper 的实现
+
+方法名的前缀决定返回值：find、list、query、search 返回实体列表，count 返回
+数量，exists 返回是否存在。
+
+--where 中没有右侧取值的比较会成为方法参数，参数按列名命名：
+  status = 1             固定条件
+  email LIKE             参数 emailPattern（匹配模式，调用时自行加上 %）
+  status IN              参数 statusList
+  create_time BETWEEN    参数 createTimeFrom、createTimeTo
+  user_id =              参数 userId
+
+表中有 deleted 列时会自动加上 deleted = 0，与 @TableLogic 的行为一致。
+
+使用示例：
+  phjvgen query User findActiveByEmailDomain --where "status = 1 AND email LIKE"
+  phjvgen query User countByStatus --where "status ="
+  phjvgen query User existsByPhone --where "phone ="`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.GenerateQuery(args[0], args[1], queryOpts); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	queryCmd.Flags().StringVar(&queryOpts.Where, "where", "", "查询条件，没有右侧取值的比较会成为方法参数")
+	rootCmd.AddCommand(queryCmd)
+}
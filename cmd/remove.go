@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"github.com/phixia/phjvgen/internal/generator"
+	"github.com/phixia/phjvgen/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var removeOpts generator.RemoveModuleOptions
+
+var removeCmd = &cobra.Command{
+	Use:     "remove <module-name>",
+	Aliases: []string{"rm"},
+	Short:   "删除业务模块",
+	Long: `删除一个 Application 业务模块，是 add 命令的逆操作。
+
+该命令会：
+  - 检查其他模块的 Java 源码是否仍在引用该模块的包，如有引用则列出文件并停止
+  - 删除 application/application-<module-name> 目录
+  - 移除父 pom.xml 中的 modules 和 dependencyManagement 声明
+  - 移除所有模块 pom.xml 中对该模块的依赖
+
+目录删除和 POM 修改作为一个整体执行，任一步骤失败都会回滚。
+
+使用示例：
+  phjvgen remove payment             # 删除 application-payment 模块
+  phjvgen remove payment --dry-run   # 只显示将要执行的变更
+
+注意：必须在项目根目录或其子目录下运行此命令。`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.RemoveApplicationModule(args[0], removeOpts); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	removeCmd.Flags().BoolVar(&removeOpts.DryRun, "dry-run", false, "只显示将要执行的变更，不做任何修改")
+	rootCmd.AddCommand(removeCmd)
+}
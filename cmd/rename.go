@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"github.com/phixia/phjvgen/internal/generator"
+	"github.com/phixia/phjvgen/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var renameOpts generator.RenameOptions
+
+var renameCmd = &cobra.Command{
+	Use:   "rename",
+	Short: "重命名模块或移动基础包",
+	Long: `重命名 Application 模块，或将项目的基础包移动到新的包名下。
+
+两个子命令都会：
+  - 移动目录
+  - 改写 package 和 import 语句
+  - 更新 @MapperScan、Mapper XML 和 YAML 中 logging.level 的包名
+  - 更新 POM 中的 artifactId、groupId 和 module 路径
+  - 列出所有被修改的文件
+
+使用示例：
+  phjvgen rename module user account                     # application-user → application-account
+  phjvgen rename package com.example.demo com.acme.billing
+  phjvgen rename package com.example.demo com.acme.billing --dry-run
+
+注意：必须在项目根目录或其子目录下运行此命令。`,
+}
+
+var renameModuleCmd = &cobra.Command{
+	Use:   "module <old-name> <new-name>",
+	Short: "重命名 Application 模块",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.RenameModule(args[0], args[1], renameOpts); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+var renamePackageCmd = &cobra.Command{
+	Use:   "package <old-package> <new-package>",
+	Short: "移动基础包",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.RenamePackage(args[0], args[1], renameOpts); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	renameCmd.PersistentFlags().BoolVar(&renameOpts.DryRun, "dry-run", false, "只显示将要执行的变更，不做任何修改")
+	renameCmd.AddCommand(renameModuleCmd)
+	renameCmd.AddCommand(renamePackageCmd)
+	rootCmd.AddCommand(renameCmd)
+}
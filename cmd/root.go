@@ -20,7 +20,17 @@ var rootCmd = &cobra.Command{
 使用示例:
   phjvgen generate         # 生成新项目（包含完整示例代码）
   phjvgen example          # 快速生成示例项目（包含完整示例代码）
-  phjvgen add payment      # 添加新业务模块`,
+  phjvgen add payment      # 添加新业务模块
+  phjvgen usecase cancel-order --module order  # 生成复杂业务用例
+  phjvgen query User findActiveByEmailDomain --where "status = 1 AND email LIKE"  # 生成自定义查询
+  phjvgen cache User --ttl 10m  # 为仓储生成 Caffeine + Redis 两级缓存
+  phjvgen add datasource reporting --readonly # 添加只读副本
+  phjvgen dep add redisson --to infrastructure # 添加第三方依赖
+  phjvgen verify           # 检查项目结构的一致性
+  phjvgen graph --check    # 检查模块依赖的分层方向
+  phjvgen migration new "add order table"  # 新建数据库迁移脚本
+  phjvgen migration diff   # 根据实体定义的变更生成迁移脚本
+  phjvgen seed User --count 200 --seed 42  # 为实体生成测试数据`,
 }
 
 // Execute runs the root command
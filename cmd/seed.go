@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"github.com/phixia/phjvgen/internal/generator"
+	"github.com/phixia/phjvgen/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var seedOpts generator.SeedOptions
+
+var seedCmd = &cobra.Command{
+	Use:   "seed <Entity>",
+	Short: "为实体生成测试数据",
+	Long: `根据项目清单 phjvgen.json 中实体的表定义生成测试数据的 INSERT 脚本。
+
+数据按字段名称和类型生成：邮箱、手机号、用户名、姓名、地址、金额、时间等
+字段生成贴近真实的值，注释中列出取值的字段（例如 "状态：0-禁用，1-启用"）
+从中选取。生成时会：
+  - 保证主键和唯一键不重复
+  - 为引用的表（列的 references，或按 <表名>_id 约定）一并生成数据，
+    引用列只取这些行中存在的值
+  - 相同的 --seed 和 --count 总是生成相同的脚本
+
+脚本先删除将要插入的行再插入，可以重复执行：整数主键删除 1..count 之间的行，
+UUID 等其他主键按生成的值删除。默认写入
+infrastructure/src/main/resources/db/seed/seed_<表名>.sql，需要手动执行；
+使用 --flyway 时写成 Flyway 可重复迁移 R__seed_<表名>.sql，并把 db/seed
+加入 --profile 的 spring.flyway.locations，只有该环境会导入数据。
+
+使用示例：
+  phjvgen seed User
+  phjvgen seed User --count 200 --seed 42
+  phjvgen seed Order --flyway --profile dev`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.GenerateSeed(args[0], seedOpts); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	seedCmd.Flags().IntVar(&seedOpts.Count, "count", 100, "每张表生成的行数")
+	seedCmd.Flags().Int64Var(&seedOpts.Seed, "seed", 1, "随机种子，相同的种子生成相同的数据")
+	seedCmd.Flags().BoolVar(&seedOpts.Flyway, "flyway", false, "生成 Flyway 可重复迁移，由指定 profile 启动时导入")
+	seedCmd.Flags().StringVar(&seedOpts.Profile, "profile", "dev", "--flyway 导入数据的 Spring profile")
+	seedCmd.Flags().StringVarP(&seedOpts.Output, "output", "o", "", "输出文件，默认 db/seed/seed_<表名>.sql")
+	rootCmd.AddCommand(seedCmd)
+}
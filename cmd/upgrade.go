@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"github.com/phixia/phjvgen/internal/generator"
+	"github.com/phixia/phjvgen/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var upgradeOpts generator.UpgradeOptions
+
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "升级已生成的项目",
+	Long:  `将已生成的项目升级到当前 phjvgen 版本附带的配置。`,
+}
+
+var upgradeVersionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "升级依赖版本",
+	Long: `将项目的依赖版本与当前 phjvgen 附带的推荐版本进行比较并升级，无需联网。
+
+该命令会：
+  - 读取父 pom.xml 的 <properties>（Gradle 项目读取 gradle/libs.versions.toml 的 [versions]）
+  - 列出当前版本、推荐版本和兼容性说明
+  - 按选择的条目修改版本号，只改动对应的行，并在写入前显示 diff
+
+Java 版本不在升级范围内，如需升级请同时更换 JDK 并手动修改。
+
+使用示例：
+  phjvgen upgrade versions                             # 交互式选择要升级的版本
+  phjvgen upgrade versions --dry-run                   # 只显示版本对比和 diff
+  phjvgen upgrade versions --only lombok,mapstruct     # 只升级指定版本
+  phjvgen upgrade versions --yes                       # 升级全部，不再确认
+
+注意：必须在项目根目录或其子目录下运行此命令。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.UpgradeVersions(upgradeOpts); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	upgradeVersionsCmd.Flags().StringSliceVar(&upgradeOpts.Only, "only", nil, "只升级这些版本属性，例如 lombok 或 lombok.version")
+	upgradeVersionsCmd.Flags().BoolVar(&upgradeOpts.DryRun, "dry-run", false, "只显示版本对比和将要执行的变更，不做任何修改")
+	upgradeVersionsCmd.Flags().BoolVarP(&upgradeOpts.Yes, "yes", "y", false, "升级所有可升级的版本，不再确认")
+
+	upgradeCmd.AddCommand(upgradeVersionsCmd)
+	rootCmd.AddCommand(upgradeCmd)
+}
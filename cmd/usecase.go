@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"github.com/phixia/phjvgen/internal/generator"
+	"github.com/phixia/phjvgen/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var useCaseOpts generator.UseCaseOptions
+
+var useCaseCmd = &cobra.Command{
+	Use:   "usecase <usecase-name>",
+	Short: "生成复杂业务用例（Command + Executor）",
+	Long: `为 Application 模块生成一个复杂业务用例，以 RegisterUserExecutor 为范本。
+
+该命令会生成：
+  - <UseCase>Command（application-<module>/dto）
+  - <UseCase>Executor，带 @Transactional（application-<module>/executor）
+  - 模块 Service 中委托给 Executor 的方法
+  - Controller 中的接口方法和对应的 <UseCase>Request 请求VO
+  - <UseCase>ExecutorTest 单元测试骨架
+
+已存在的 Service 和 Controller 只会追加字段、方法和 import，不会覆盖已有代码。
+Controller 类上有 @RequestMapping 时，--http 的路径必须在该前缀下。
+Command 或 Executor 已存在时命令会直接停止。
+
+用例名称格式要求：
+  - 只能包含小写字母、数字和连字符
+  - 例如：cancel-order, register-user
+
+使用示例：
+  phjvgen usecase cancel-order --module order --http POST:/api/orders/{id}/cancel
+  phjvgen usecase disable-user --module user --http PUT:/api/users/{id}/disable
+
+注意：必须在项目根目录或其子目录下运行此命令。`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useCaseOpts.Name = args[0]
+
+		if err := generator.GenerateUseCase(useCaseOpts); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	useCaseCmd.Flags().StringVarP(&useCaseOpts.Module, "module", "m", "", "所属的 Application 模块名称（不含 application- 前缀）")
+	useCaseCmd.Flags().StringVar(&useCaseOpts.HTTP, "http", "", "HTTP 端点，格式 METHOD:/path（默认 POST:/api/<module>/<usecase-name>）")
+	_ = useCaseCmd.MarkFlagRequired("module")
+	rootCmd.AddCommand(useCaseCmd)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/phixia/phjvgen/internal/generator"
+	"github.com/phixia/phjvgen/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "检查项目结构的一致性",
+	Long: `在不运行 JDK、Maven 或 Gradle 的情况下检查项目结构是否一致，适合在 CI 中运行。
+
+检查项：
+  - 每个 <module>（Gradle 为 settings.gradle.kts 中的 module）的目录和构建文件存在
+  - 模块间依赖已在父 POM 的 dependencyManagement 中声明（Gradle 为依赖的项目已在 settings 中声明）
+  - 每个 .java 文件的 package 与所在目录一致
+  - @MapperScan 中的包存在
+  - 每个 Mapper 接口都有对应的 DO 类
+
+发现问题时列出对应文件，并以非零状态码退出。
+
+使用示例：
+  phjvgen verify
+
+注意：必须在项目根目录或其子目录下运行此命令。`,
+	Args: cobra.NoArgs,
+	// A failed check is a result, not a usage error
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := generator.Verify(); err != nil {
+			utils.PrintError(err.Error())
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
@@ -0,0 +1,86 @@
+// Package catalog holds the curated dependencies that can be added with
+// "phjvgen dep add". The catalog is embedded in the binary so it works
+// offline.
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Module roles a dependency can be allowed in
+const (
+	RoleCommon         = "common"
+	RoleDomain         = "domain"
+	RoleInfrastructure = "infrastructure"
+	RoleApplication    = "application"
+	RoleAdapter        = "adapter"
+	RoleStarter        = "starter"
+)
+
+//go:embed catalog.json
+var catalogJSON []byte
+
+// Entry is a dependency of the catalog
+type Entry struct {
+	// Name is the short name used on the command line, e.g. redisson
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	GroupID     string `json:"groupId"`
+	ArtifactID  string `json:"artifactId"`
+	// Version is empty when the version is managed by the Spring Boot BOM
+	Version string `json:"version,omitempty"`
+	// VersionProperty is the parent POM property holding Version
+	VersionProperty string `json:"versionProperty,omitempty"`
+	Scope           string `json:"scope,omitempty"`
+	// Modules lists the module roles the dependency may be added to
+	Modules []string `json:"modules"`
+}
+
+// Coordinates returns groupId:artifactId
+func (e Entry) Coordinates() string {
+	return e.GroupID + ":" + e.ArtifactID
+}
+
+// Allows reports whether the dependency may be added to a module with the role
+func (e Entry) Allows(role string) bool {
+	for _, m := range e.Modules {
+		if m == role {
+			return true
+		}
+	}
+	return false
+}
+
+// All returns the catalog entries in catalog order
+func All() []Entry {
+	var entries []Entry
+	if err := json.Unmarshal(catalogJSON, &entries); err != nil {
+		panic(fmt.Sprintf("invalid embedded dependency catalog: %v", err))
+	}
+	return entries
+}
+
+// Lookup finds a catalog entry by name or by artifactId
+func Lookup(name string) (Entry, bool) {
+	for _, e := range All() {
+		if e.Name == name || e.ArtifactID == name {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// ModuleRole derives the role of a module from its artifactId, following the
+// layout generated by phjvgen: bounded context modules such as domain-billing
+// share the role of their layer. It returns "" for unknown modules.
+func ModuleRole(artifactID string) string {
+	for _, role := range []string{RoleCommon, RoleDomain, RoleInfrastructure, RoleApplication, RoleAdapter, RoleStarter} {
+		if artifactID == role || strings.HasPrefix(artifactID, role+"-") {
+			return role
+		}
+	}
+	return ""
+}
@@ -0,0 +1,281 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/gradle"
+	"github.com/phixia/phjvgen/internal/pom"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// Supported build tools
+const (
+	BuildToolMaven  = "maven"
+	BuildToolGradle = "gradle"
+)
+
+// DefaultMavenVersion is the Maven version run by the generated Maven Wrapper
+const DefaultMavenVersion = "3.9.11"
+
+var (
+	mavenVersionRe        = regexp.MustCompile(`^\d+\.\d+\.\d+(-[\w.]+)?$`)
+	wrapperDistributionRe = regexp.MustCompile(`(?m)^distributionUrl=.*/apache-maven-([^/]+)-bin\.zip\s*$`)
+)
+
+// ValidateBuildTool checks the value of the --build flag
+func ValidateBuildTool(buildTool string) error {
+	if buildTool != BuildToolMaven && buildTool != BuildToolGradle {
+		return fmt.Errorf("不支持的构建工具: %s（可选: maven, gradle）", buildTool)
+	}
+	return nil
+}
+
+// ValidateMavenVersion checks the value of the --maven-version flag
+func ValidateMavenVersion(version string) error {
+	if !mavenVersionRe.MatchString(version) {
+		return fmt.Errorf("Maven版本格式不正确: %s（例如: %s）", version, DefaultMavenVersion)
+	}
+	return nil
+}
+
+// detectBuildTool tells a Gradle project from a Maven one by its settings script
+func detectBuildTool(projectRoot string) string {
+	if utils.FileExists(filepath.Join(projectRoot, gradle.SettingsFile)) {
+		return BuildToolGradle
+	}
+	return BuildToolMaven
+}
+
+// isGradle reports whether the project is built with Gradle
+func (c *ProjectConfig) isGradle() bool {
+	return c.BuildTool == BuildToolGradle
+}
+
+// BuildCommand returns the command that builds the whole project
+func (c *ProjectConfig) BuildCommand() string {
+	if c.isGradle() {
+		return "gradle build"
+	}
+	if c.MavenVersion != "" {
+		return "./mvnw clean install"
+	}
+	return "mvn clean install"
+}
+
+// StarterJar returns the path of the executable jar built by the starter module
+func (c *ProjectConfig) StarterJar() string {
+	if c.isGradle() {
+		return fmt.Sprintf("starter/build/libs/starter-%s.jar", c.Version)
+	}
+	return fmt.Sprintf("starter/target/starter-%s.jar", c.Version)
+}
+
+// buildFileName returns the name of the per-module build file
+func (c *ProjectConfig) buildFileName() string {
+	if c.isGradle() {
+		return gradle.BuildFile
+	}
+	return "pom.xml"
+}
+
+// extractProjectInfo reads the project coordinates from the root build files
+func extractProjectInfo(projectRoot string) (*ProjectConfig, error) {
+	if detectBuildTool(projectRoot) == BuildToolMaven {
+		config, err := extractProjectInfoFromPOM(projectRoot)
+		if err != nil {
+			return nil, err
+		}
+		config.BuildTool = BuildToolMaven
+		config.MavenVersion = readMavenWrapperVersion(projectRoot)
+		config.Database = detectDatabase(projectRoot, BuildToolMaven)
+		config.Persistence = detectPersistence(config)
+		config.Migration = detectMigration(config)
+		config.MultiTenant = detectMultiTenant(config)
+		config.IDStrategy = detectIDStrategy(config)
+		return config, nil
+	}
+
+	settings, err := gradle.Load(filepath.Join(projectRoot, gradle.SettingsFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", gradle.SettingsFile, err)
+	}
+	build, err := gradle.Load(filepath.Join(projectRoot, gradle.BuildFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", gradle.BuildFile, err)
+	}
+
+	groupID := build.Group()
+	if groupID == "" {
+		return nil, fmt.Errorf("could not extract group from %s", gradle.BuildFile)
+	}
+	version := build.Version()
+	if version == "" {
+		version = "1.0.0"
+	}
+	artifactID := settings.RootProjectName()
+	if artifactID == "" {
+		artifactID = filepath.Base(projectRoot)
+	}
+
+	config := &ProjectConfig{
+		GroupID:     groupID,
+		ArtifactID:  artifactID,
+		Version:     version,
+		ProjectName: artifactID,
+		PackageName: groupID,
+		PackagePath: strings.ReplaceAll(groupID, ".", "/"),
+		OutputDir:   projectRoot,
+		BuildTool:   BuildToolGradle,
+		Database:    detectDatabase(projectRoot, BuildToolGradle),
+	}
+	config.Persistence = detectPersistence(config)
+	config.Migration = detectMigration(config)
+	config.MultiTenant = detectMultiTenant(config)
+	config.IDStrategy = detectIDStrategy(config)
+	return config, nil
+}
+
+// readMavenWrapperVersion returns the Maven version of the project's Maven
+// Wrapper, or "" when the project has none
+func readMavenWrapperVersion(projectRoot string) string {
+	if !utils.FileExists(filepath.Join(projectRoot, "mvnw")) {
+		return ""
+	}
+	content, err := os.ReadFile(filepath.Join(projectRoot, ".mvn", "wrapper", "maven-wrapper.properties"))
+	if err != nil {
+		return ""
+	}
+	if m := wrapperDistributionRe.FindSubmatch(content); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// registerModule declares a new module in the root build: <modules> and
+// dependencyManagement for Maven, settings.gradle.kts for Gradle. Maven
+// modules also get the enforcer rules of their layer when the project has them.
+func registerModule(config *ProjectConfig, modulePath, artifactID string) error {
+	if !config.isGradle() {
+		if err := updateParentPOMModules(config.OutputDir, modulePath); err != nil {
+			return err
+		}
+		if err := updateParentPOMDependencyManagement(config.OutputDir, config, artifactID); err != nil {
+			return err
+		}
+		return bindEnforcerLayer(config, modulePath, artifactID)
+	}
+
+	settingsPath := filepath.Join(config.OutputDir, gradle.SettingsFile)
+	settings, err := gradle.Load(settingsPath)
+	if err != nil {
+		return err
+	}
+	if !settings.AddModule(artifactID, modulePath) {
+		utils.PrintWarning(fmt.Sprintf("模块已在 %s 中声明", gradle.SettingsFile))
+		return nil
+	}
+	return settings.Save(settingsPath)
+}
+
+// writeModuleBuildFile renders the build file of a module from the Maven or
+// Gradle template matching the project
+func writeModuleBuildFile(config *ProjectConfig, modulePath, mavenTemplate, gradleTemplate string, replacements map[string]string) error {
+	template := mavenTemplate
+	if config.isGradle() {
+		template = gradleTemplate
+	}
+	content := utils.ReplacePlaceholders(template, replacements)
+	return utils.WriteFile(filepath.Join(config.OutputDir, modulePath, config.buildFileName()), content)
+}
+
+// hasModuleDependency reports whether the module in modulePath depends on
+// another module of the project
+func hasModuleDependency(config *ProjectConfig, modulePath, artifactID string) bool {
+	buildPath := filepath.Join(config.OutputDir, modulePath, config.buildFileName())
+	if config.isGradle() {
+		build, err := gradle.Load(buildPath)
+		return err == nil && build.HasProjectDependency(artifactID)
+	}
+	doc, err := pom.Load(buildPath)
+	return err == nil && doc.HasDependency("", artifactID)
+}
+
+// addModuleDependency adds a dependency to the build file of the module in
+// modulePath. Dependencies in the project's own group are module
+// dependencies. It returns false when the dependency is already declared.
+func addModuleDependency(config *ProjectConfig, modulePath, groupID, artifactID, scope string) (bool, error) {
+	buildPath := filepath.Join(config.OutputDir, modulePath, config.buildFileName())
+
+	if !config.isGradle() {
+		doc, err := pom.Load(buildPath)
+		if err != nil {
+			return false, err
+		}
+		added, err := doc.AddDependency(pom.Dependency{GroupID: groupID, ArtifactID: artifactID, Scope: scope})
+		if err != nil || !added {
+			return false, err
+		}
+		return true, doc.Save(buildPath)
+	}
+
+	build, err := gradle.Load(buildPath)
+	if err != nil {
+		return false, err
+	}
+
+	var notation string
+	internal := groupID == config.GroupID
+	if internal {
+		if build.HasProjectDependency(artifactID) {
+			return false, nil
+		}
+		notation = fmt.Sprintf(`project(":%s")`, artifactID)
+	} else {
+		notation = fmt.Sprintf(`"%s:%s"`, groupID, artifactID)
+		if lib, ok := loadCatalogLibrary(config.OutputDir, groupID, artifactID); ok {
+			notation = lib.Accessor()
+		}
+		if build.HasDependency(strings.Trim(notation, `"`)) {
+			return false, nil
+		}
+	}
+
+	configuration := gradleConfiguration(scope, internal)
+	if internal && scope == "" {
+		// Follow the module's own convention, e.g. implementation in starter
+		if existing := build.ProjectDependencyConfiguration(); existing != "" {
+			configuration = existing
+		}
+	}
+	build.AddDependency(configuration, notation)
+	return true, build.Save(buildPath)
+}
+
+// gradleConfiguration maps a Maven scope to a Gradle configuration
+func gradleConfiguration(scope string, internal bool) string {
+	switch scope {
+	case "test":
+		return "testImplementation"
+	case "provided":
+		return "compileOnly"
+	case "runtime":
+		return "runtimeOnly"
+	}
+	if internal {
+		return "api"
+	}
+	return "implementation"
+}
+
+// loadCatalogLibrary looks up a library declared in the version catalog
+func loadCatalogLibrary(projectRoot, groupID, artifactID string) (gradle.Library, bool) {
+	catalog, err := gradle.Load(filepath.Join(projectRoot, gradle.CatalogFile))
+	if err != nil {
+		return gradle.Library{}, false
+	}
+	return catalog.Library(groupID + ":" + artifactID)
+}
@@ -0,0 +1,544 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/catalog"
+	"github.com/phixia/phjvgen/internal/pom"
+	"github.com/phixia/phjvgen/internal/templates"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// CacheOptions holds the options for caching a repository
+type CacheOptions struct {
+	// TTL is how long entries stay in Redis, in the Spring Boot duration
+	// format, e.g. 10m
+	TTL string
+}
+
+var (
+	cacheTTLRe         = regexp.MustCompile(`^[1-9][0-9]*(ms|s|m|h|d)$`)
+	javaCommentRe      = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*`)
+	javaInterfaceRe    = regexp.MustCompile(`(?m)^public\s+interface\s+(\w+)[^{]*\{`)
+	javaAnnotationRe   = regexp.MustCompile(`@[\w.]+(\([^)]*\))?\s*`)
+	repositoryMethodRe = regexp.MustCompile(`(?s)^(?:(?:public|abstract)\s+)*(.+?)\s+(\w+)\s*\((.*)\)\s*(throws\s+[\w.,\s]+)?$`)
+	repositoryDocRe    = regexp.MustCompile(`/\*\*\s*\n\s*\*\s*([^\n@]+?)\s*\n[^/]*\*/\s*\n(?:@\w+\s*\n)*public\s+interface`)
+)
+
+// cacheReadPrefixes are the name prefixes of repository methods that only
+// read. Any other method may change rows and clears the whole cache.
+var cacheReadPrefixes = []string{"find", "get", "list", "query", "search", "count", "exists", "load", "select", "page", "check", "is", "has"}
+
+// repositoryMethod is an abstract method of a repository interface
+type repositoryMethod struct {
+	returnType string
+	name       string
+	params     []repositoryParam
+	throws     string
+}
+
+type repositoryParam struct {
+	javaType string
+	name     string
+}
+
+// declaration renders the method's signature as implemented by a class
+func (m repositoryMethod) declaration() string {
+	params := make([]string, len(m.params))
+	for i, p := range m.params {
+		params[i] = p.javaType + " " + p.name
+	}
+	decl := fmt.Sprintf("public %s %s(%s)", m.returnType, m.name, strings.Join(params, ", "))
+	if m.throws != "" {
+		decl += " " + m.throws
+	}
+	return decl
+}
+
+// call renders the call passing the parameters on to the delegate
+func (m repositoryMethod) call() string {
+	names := make([]string, len(m.params))
+	for i, p := range m.params {
+		names[i] = p.name
+	}
+	return fmt.Sprintf("delegate.%s(%s)", m.name, strings.Join(names, ", "))
+}
+
+// GenerateCache generates a caching decorator of an entity's repository:
+// lookups go through a local Caffeine cache and Redis, writes evict the
+// entity's keys. It also adds the dependencies and the Redis settings of
+// every profile.
+func GenerateCache(entity string, opts CacheOptions) error {
+	if !cacheTTLRe.MatchString(opts.TTL) {
+		return fmt.Errorf("--ttl 格式不正确，请使用数字加单位 ms、s、m、h 或 d，例如: 10m")
+	}
+
+	projectRoot, config, err := migrationProject()
+	if err != nil {
+		return err
+	}
+
+	entityClass := toCamelCase(entity)
+	entityClass = strings.ToUpper(entityClass[:1]) + entityClass[1:]
+	cacheName := cacheNameOf(entityClass)
+	repositoryPath := filepath.Join(projectRoot, "domain/src/main/java", config.PackagePath, "domain/repository", entityClass+"Repository.java")
+	entityPath := filepath.Join(projectRoot, "domain/src/main/java", config.PackagePath, "domain/model", entityClass+".java")
+	implPath := filepath.Join(projectRoot, "infrastructure/src/main/java", config.PackagePath, "infrastructure/persistence/impl", entityClass+"RepositoryImpl.java")
+	cacheDir := filepath.Join(projectRoot, "infrastructure/src/main/java", config.PackagePath, "infrastructure/cache")
+	decoratorPath := filepath.Join(cacheDir, "Caching"+entityClass+"Repository.java")
+
+	sources := map[string]string{}
+	for _, path := range []string{repositoryPath, entityPath, implPath} {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("未找到 %s", relPath(projectRoot, path))
+		}
+		sources[path] = string(content)
+	}
+	if utils.FileExists(decoratorPath) {
+		return fmt.Errorf("%s 已存在，如需重新生成请先删除", relPath(projectRoot, decoratorPath))
+	}
+
+	methods, err := parseRepositoryInterface(sources[repositoryPath])
+	if err != nil {
+		return fmt.Errorf("无法解析 %s: %w", filepath.Base(repositoryPath), err)
+	}
+	lookups := cacheLookups(methods, entityClass, sources[entityPath])
+	idType := ""
+	for _, m := range methods {
+		if m.name == "findById" && lookups[m.name] == "id" {
+			idType = m.params[0].javaType
+		}
+	}
+	if idType == "" {
+		return fmt.Errorf("缓存需要 %sRepository 声明 Optional<%s> findById 方法", entityClass, entityClass)
+	}
+
+	utils.PrintInfo(fmt.Sprintf("生成 %sRepository 的缓存装饰器...", entityClass))
+	twoLevelCachePath := filepath.Join(cacheDir, "TwoLevelCache.java")
+	if !utils.FileExists(twoLevelCachePath) {
+		if err := utils.WriteFile(twoLevelCachePath, utils.ReplacePlaceholders(templates.TwoLevelCache, config.GetReplacements())); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("已生成 %s", relPath(projectRoot, twoLevelCachePath)))
+	}
+
+	replacements := cachingRepositoryReplacements(config, sources[repositoryPath], entityClass, cacheName, idType, methods, lookups)
+	if err := utils.WriteFile(decoratorPath, utils.ReplacePlaceholders(templates.CachingRepository, replacements)); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("已生成 %s", relPath(projectRoot, decoratorPath)))
+
+	if err := addCacheDependencies(config); err != nil {
+		return err
+	}
+	if err := addRedisProfiles(projectRoot); err != nil {
+		return err
+	}
+	if err := addCacheTTL(projectRoot, cacheName, opts.TTL); err != nil {
+		return err
+	}
+
+	printCacheSummary(config, entityClass, cacheName, methods, lookups)
+	return nil
+}
+
+// cacheNameOf returns the cache name of an entity class, e.g. order-item
+// for OrderItem
+func cacheNameOf(entityClass string) string {
+	var b strings.Builder
+	for i, r := range entityClass {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// parseRepositoryInterface returns the abstract methods of a repository
+// interface, in declaration order
+func parseRepositoryInterface(src string) ([]repositoryMethod, error) {
+	code := javaCommentRe.ReplaceAllString(src, "")
+	loc := javaInterfaceRe.FindStringIndex(code)
+	if loc == nil {
+		return nil, fmt.Errorf("未找到 public interface 声明")
+	}
+	body := code[loc[1]:]
+	if end := strings.LastIndex(body, "}"); end != -1 {
+		body = body[:end]
+	}
+	if strings.Contains(body, "{") {
+		return nil, fmt.Errorf("暂不支持包含 default 或 static 方法的仓储接口")
+	}
+
+	var methods []repositoryMethod
+	for _, decl := range strings.Split(body, ";") {
+		decl = strings.TrimSpace(javaAnnotationRe.ReplaceAllString(decl, ""))
+		if !strings.Contains(decl, "(") {
+			// Blank, or a constant
+			continue
+		}
+		m := repositoryMethodRe.FindStringSubmatch(decl)
+		if m == nil {
+			return nil, fmt.Errorf("无法识别方法声明: %s", strings.Join(strings.Fields(decl), " "))
+		}
+		method := repositoryMethod{
+			returnType: strings.Join(strings.Fields(m[1]), " "),
+			name:       m[2],
+			throws:     strings.Join(strings.Fields(m[4]), " "),
+		}
+		for _, param := range splitJavaParams(m[3]) {
+			fields := strings.Fields(strings.TrimPrefix(param, "final "))
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("无法识别 %s 的参数: %s", method.name, param)
+			}
+			method.params = append(method.params, repositoryParam{
+				javaType: strings.Join(fields[:len(fields)-1], " "),
+				name:     fields[len(fields)-1],
+			})
+		}
+		methods = append(methods, method)
+	}
+	return methods, nil
+}
+
+// splitJavaParams splits a parameter list at the commas outside of type
+// arguments
+func splitJavaParams(list string) []string {
+	var params []string
+	depth, start := 0, 0
+	for i, r := range list {
+		switch r {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				params = append(params, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(list[start:]); last != "" {
+		params = append(params, last)
+	}
+	return params
+}
+
+// cacheLookups maps the cacheable methods to the entity field they look up:
+// Optional<Entity> findByXxx(value) with a single parameter, where the
+// entity declares the field xxx
+func cacheLookups(methods []repositoryMethod, entityClass, entitySrc string) map[string]string {
+	lookups := map[string]string{}
+	for _, m := range methods {
+		if m.returnType != "Optional<"+entityClass+">" || len(m.params) != 1 || !strings.HasPrefix(m.name, "findBy") {
+			continue
+		}
+		field := lowerFirst(strings.TrimPrefix(m.name, "findBy"))
+		if field != "" && hasJavaField(entitySrc, field) {
+			lookups[m.name] = field
+		}
+	}
+	return lookups
+}
+
+// isCacheRead reports whether a repository method only reads
+func isCacheRead(name string) bool {
+	for _, prefix := range cacheReadPrefixes {
+		if strings.HasPrefix(name, prefix) && (len(name) == len(prefix) || name[len(prefix)] >= 'A' && name[len(prefix)] <= 'Z') {
+			return true
+		}
+	}
+	return false
+}
+
+// isEntityWrite reports whether a method saves or updates a single entity,
+// so that only that entity's keys are evicted
+func isEntityWrite(m repositoryMethod, entityClass string) bool {
+	return (m.name == "save" || m.name == "update") && len(m.params) == 1 && m.params[0].javaType == entityClass
+}
+
+func isDeleteByID(m repositoryMethod, idType string) bool {
+	return m.name == "deleteById" && len(m.params) == 1 && m.params[0].javaType == idType
+}
+
+func cachingRepositoryReplacements(config *ProjectConfig, repositorySrc, entityClass, cacheName, idType string, methods []repositoryMethod, lookups map[string]string) map[string]string {
+	entityVar := lowerFirst(entityClass)
+	description := entityClass
+	if m := repositoryDocRe.FindStringSubmatch(repositorySrc); m != nil {
+		if d := strings.TrimSuffix(strings.TrimSuffix(m[1], "接口"), "仓储"); d != "" {
+			description = d
+		}
+	}
+
+	var lookupNames, keyNames, evictions []string
+	var keysOf strings.Builder
+	for _, m := range methods {
+		if field, ok := lookups[m.name]; ok {
+			lookupNames = append(lookupNames, m.name)
+			keyNames = append(keyNames, fmt.Sprintf("%s:<%s>", field, field))
+			getter := fmt.Sprintf("%s.get%s()", entityVar, strings.ToUpper(field[:1])+field[1:])
+			fmt.Fprintf(&keysOf, "        if (%s != null) {\n            keys.add(cache.key(%q, %s));\n        }\n", getter, field, getter)
+		}
+		if isEntityWrite(m, entityClass) || isDeleteByID(m, idType) {
+			evictions = append(evictions, m.name)
+		}
+	}
+	if len(evictions) == 0 {
+		evictions = append(evictions, "写操作")
+	}
+
+	var body strings.Builder
+	for _, m := range methods {
+		body.WriteString("\n" + cachingMethod(m, entityClass, idType, lookups))
+	}
+
+	replacements := config.GetReplacements()
+	replacements["{{CACHE_IMPORTS}}"] = cachingRepositoryImports(config, repositorySrc, entityClass)
+	replacements["{{ENTITY_CLASS}}"] = entityClass
+	replacements["{{ENTITY_VAR}}"] = entityVar
+	replacements["{{ENTITY_DESCRIPTION}}"] = description
+	replacements["{{CACHE_NAME}}"] = cacheName
+	replacements["{{DELEGATE_BEAN}}"] = entityVar + "RepositoryImpl"
+	replacements["{{ID_TYPE}}"] = idType
+	replacements["{{CACHE_LOOKUPS}}"] = strings.Join(lookupNames, "、")
+	replacements["{{CACHE_KEYS}}"] = strings.Join(keyNames, "、")
+	replacements["{{CACHE_EVICTIONS}}"] = strings.Join(evictions, "、")
+	replacements["{{CACHE_KEYS_OF}}"] = keysOf.String()
+	replacements["{{CACHE_METHODS}}"] = body.String()
+	return replacements
+}
+
+// cachingMethod renders the decorator's implementation of a repository method
+func cachingMethod(m repositoryMethod, entityClass, idType string, lookups map[string]string) string {
+	var b strings.Builder
+	returns := m.returnType != "void"
+	fmt.Fprintf(&b, "    @Override\n    %s {\n", m.declaration())
+
+	switch field, lookup := lookups[m.name]; {
+	case lookup:
+		arg := m.params[0].name
+		fmt.Fprintf(&b, "        return cache.get(%q, %s, () -> delegate.%s(%s));\n", field, arg, m.name, arg)
+
+	case isEntityWrite(m, entityClass):
+		arg := m.params[0].name
+		fmt.Fprintf(&b, "        List<String> keys = storedKeys(%s.getId());\n", arg)
+		switch m.returnType {
+		case entityClass:
+			result := m.name + "d"
+			if m.name == "save" {
+				result = "saved"
+			}
+			fmt.Fprintf(&b, "        %s %s = %s;\n", entityClass, result, m.call())
+			fmt.Fprintf(&b, "        keys.addAll(keysOf(%s));\n", result)
+			b.WriteString("        cache.evict(keys);\n")
+			fmt.Fprintf(&b, "        return %s;\n", result)
+		case "void":
+			fmt.Fprintf(&b, "        %s;\n", m.call())
+			fmt.Fprintf(&b, "        keys.addAll(keysOf(%s));\n", arg)
+			b.WriteString("        cache.evict(keys);\n")
+		default:
+			fmt.Fprintf(&b, "        %s result = %s;\n", m.returnType, m.call())
+			fmt.Fprintf(&b, "        keys.addAll(keysOf(%s));\n", arg)
+			b.WriteString("        cache.evict(keys);\n        return result;\n")
+		}
+
+	case isDeleteByID(m, idType):
+		arg := m.params[0].name
+		fmt.Fprintf(&b, "        List<String> keys = storedKeys(%s);\n", arg)
+		fmt.Fprintf(&b, "        keys.add(cache.key(\"id\", %s));\n", arg)
+		writeCachingCall(&b, m, returns, "cache.evict(keys);")
+
+	case isCacheRead(m.name):
+		if returns {
+			fmt.Fprintf(&b, "        return %s;\n", m.call())
+		} else {
+			fmt.Fprintf(&b, "        %s;\n", m.call())
+		}
+
+	default:
+		writeCachingCall(&b, m, returns, "cache.clear();")
+	}
+	b.WriteString("    }\n")
+	return b.String()
+}
+
+// writeCachingCall calls the delegate, then runs the eviction before
+// returning its result
+func writeCachingCall(b *strings.Builder, m repositoryMethod, returns bool, eviction string) {
+	if !returns {
+		fmt.Fprintf(b, "        %s;\n        %s\n", m.call(), eviction)
+		return
+	}
+	fmt.Fprintf(b, "        %s result = %s;\n        %s\n        return result;\n", m.returnType, m.call(), eviction)
+}
+
+// cachingRepositoryImports returns the decorator's imports: those of the
+// repository interface, which cover the types of its methods, and the ones
+// of the cache itself. Project imports come first and java.* last.
+func cachingRepositoryImports(config *ProjectConfig, repositorySrc, entityClass string) string {
+	imports := map[string]bool{
+		config.PackageName + ".domain.repository." + entityClass + "Repository": true,
+		"io.micrometer.core.instrument.MeterRegistry":                           true,
+		"org.redisson.api.RedissonClient":                                       true,
+		"org.springframework.beans.factory.annotation.Qualifier":                true,
+		"org.springframework.beans.factory.annotation.Value":                    true,
+		"org.springframework.context.annotation.Primary":                        true,
+		"org.springframework.stereotype.Repository":                             true,
+		"java.time.Duration":                                                    true,
+		"java.util.ArrayList":                                                   true,
+		"java.util.List":                                                        true,
+	}
+	for _, m := range javaImportRe.FindAllString(repositorySrc, -1) {
+		fqcn := strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(m), "import")), ";")
+		imports[strings.TrimSpace(fqcn)] = true
+	}
+
+	var project, others, java []string
+	for fqcn := range imports {
+		switch {
+		case strings.HasPrefix(fqcn, config.PackageName+"."):
+			project = append(project, fqcn)
+		case strings.HasPrefix(fqcn, "java.") || strings.HasPrefix(fqcn, "javax."):
+			java = append(java, fqcn)
+		default:
+			others = append(others, fqcn)
+		}
+	}
+	sort.Strings(project)
+	sort.Strings(others)
+	sort.Strings(java)
+
+	var b strings.Builder
+	for _, fqcn := range append(project, others...) {
+		fmt.Fprintf(&b, "import %s;\n", fqcn)
+	}
+	b.WriteString("\n")
+	for _, fqcn := range java {
+		fmt.Fprintf(&b, "import %s;\n", fqcn)
+	}
+	return b.String()
+}
+
+// addCacheDependencies makes infrastructure depend on what the caching
+// repositories use: Redisson, also at runtime in the starter, Caffeine and
+// the Micrometer API
+func addCacheDependencies(config *ProjectConfig) error {
+	for _, name := range []string{"redisson", "caffeine", "micrometer"} {
+		entry, _ := catalog.Lookup(name)
+		if err := declareDependencyVersion(config, entry); err != nil {
+			return err
+		}
+		added, err := addModuleDependency(config, "infrastructure", entry.GroupID, entry.ArtifactID, entry.Scope)
+		if err != nil {
+			return err
+		}
+		if added {
+			utils.PrintSuccess(fmt.Sprintf("已将 %s 添加到 infrastructure", entry.Coordinates()))
+		}
+	}
+	if config.isGradle() {
+		// implementation dependencies are on the starter's runtime classpath
+		return nil
+	}
+
+	// The generated POM declares Redisson optional, which keeps it out of
+	// the starter until a module uses Redis
+	redisson, _ := catalog.Lookup("redisson")
+	pomPath := filepath.Join(config.OutputDir, "infrastructure", "pom.xml")
+	doc, err := pom.Load(pomPath)
+	if err != nil {
+		return err
+	}
+	changed, err := doc.MakeDependencyRequired(redisson.GroupID, redisson.ArtifactID)
+	if err != nil || !changed {
+		return err
+	}
+	if err := doc.Save(pomPath); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("infrastructure 的 %s 不再是 optional，starter 运行时会连接 Redis", redisson.ArtifactID))
+	return nil
+}
+
+// addRedisProfiles adds the Redis connection to every profile file that has
+// none. Profiles taking the database from the environment without a
+// default take Redis from it as well.
+func addRedisProfiles(projectRoot string) error {
+	profileFiles, err := listProfileFiles(projectRoot)
+	if err != nil {
+		return err
+	}
+	for _, file := range profileFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		profile := string(content)
+		if yamlHasKey(profile, []string{"spring", "data", "redis"}) {
+			continue
+		}
+		host, password := "${REDIS_HOST:localhost}", "${REDIS_PASSWORD:}"
+		if envOnlyValueRe.MatchString(yamlValue(profile, []string{"spring", "datasource", "url"})) {
+			host, password = "${REDIS_HOST}", "${REDIS_PASSWORD}"
+		}
+		entry := fmt.Sprintf("    redis:\n      host: %s\n      port: ${REDIS_PORT:6379}\n      password: %s\n", host, password)
+		if err := utils.WriteFile(file, insertYAMLEntry(profile, []string{"spring", "data"}, entry)); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("%s 已添加 spring.data.redis", filepath.Base(file)))
+	}
+	return nil
+}
+
+// addCacheTTL sets app.cache.<name>.ttl in application.yml, keeping a
+// value that is already configured
+func addCacheTTL(projectRoot, cacheName, ttl string) error {
+	file := filepath.Join(projectRoot, "starter/src/main/resources/application.yml")
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	path := []string{"app", "cache", cacheName, "ttl"}
+	if yamlHasKey(string(content), path) {
+		if current := yamlValue(string(content), path); current != ttl {
+			utils.PrintWarning(fmt.Sprintf("application.yml 已配置 %s: %s，保留原值", strings.Join(path, "."), current))
+		}
+		return nil
+	}
+	entry := fmt.Sprintf("      # Redis 中缓存的有效期，本地缓存最多保留 1 分钟\n      ttl: %s\n", ttl)
+	if err := utils.WriteFile(file, insertYAMLEntry(string(content), path[:3], entry)); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("application.yml 已添加 %s: %s", strings.Join(path, "."), ttl))
+	return nil
+}
+
+func printCacheSummary(config *ProjectConfig, entityClass, cacheName string, methods []repositoryMethod, lookups map[string]string) {
+	fmt.Println()
+	utils.PrintSuccess("==========================================")
+	utils.PrintSuccess(fmt.Sprintf("%sRepository 缓存生成完成！", entityClass))
+	utils.PrintSuccess("==========================================")
+	fmt.Println()
+	for _, m := range methods {
+		if field, ok := lookups[m.name]; ok {
+			fmt.Printf("  - %s: 缓存键 <应用名>:%s:%s:<%s>\n", m.name, cacheName, field, field)
+		}
+	}
+	fmt.Println()
+	utils.PrintInfo("下一步：")
+	fmt.Println("  1. 通过 REDIS_HOST、REDIS_PORT、REDIS_PASSWORD 配置 Redis 连接")
+	fmt.Printf("  2. 重新构建项目: %s\n", config.BuildCommand())
+	fmt.Println("  3. 在 /actuator/prometheus 查看 cache_gets_total 等缓存指标")
+	fmt.Println()
+}
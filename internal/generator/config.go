@@ -17,6 +17,32 @@ type ProjectConfig struct {
 	PackageName        string
 	PackagePath        string
 	OutputDir          string
+	// BuildTool is BuildToolMaven or BuildToolGradle
+	BuildTool string
+	// MavenVersion is the Maven version run by the Maven Wrapper, empty
+	// when the project has no wrapper
+	MavenVersion string
+	// Enforcer adds maven-enforcer-plugin rules for the layer dependencies
+	Enforcer bool
+	// Profiles are the Spring profiles, the first one active by default.
+	// Empty means DefaultProfiles.
+	Profiles []string
+	// Database is the database vendor, one of schema.Names(). Empty means
+	// DefaultDatabase.
+	Database string
+	// Persistence is the persistence stack of the infrastructure layer.
+	// Empty means DefaultPersistence.
+	Persistence string
+	// Migration is the tool running the scripts under db/migration:
+	// MigrationFlyway, MigrationLiquibase or MigrationNone. Empty means
+	// DefaultMigration.
+	Migration string
+	// MultiTenant isolates the rows of each tenant by a tenant_id column
+	MultiTenant bool
+	// IDStrategy assigns the primary keys of the scaffolded tables:
+	// IDStrategyAuto, IDStrategySnowflake or IDStrategyUUIDv7. Empty means
+	// DefaultIDStrategy.
+	IDStrategy string
 }
 
 // GetProjectConfig collects project configuration from user input
@@ -99,7 +125,7 @@ func GetProjectConfig() (*ProjectConfig, error) {
 
 // GetReplacements returns a map for template placeholder replacement
 func (c *ProjectConfig) GetReplacements() map[string]string {
-	return map[string]string{
+	replacements := map[string]string{
 		"{{GROUP_ID}}":            c.GroupID,
 		"{{ARTIFACT_ID}}":         c.ArtifactID,
 		"{{VERSION}}":             c.Version,
@@ -107,5 +133,25 @@ func (c *ProjectConfig) GetReplacements() map[string]string {
 		"{{PROJECT_DESCRIPTION}}": c.ProjectDescription,
 		"{{PACKAGE_NAME}}":        c.PackageName,
 		"{{PACKAGE_PATH}}":        c.PackagePath,
+		"{{BUILD_COMMAND}}":       c.BuildCommand(),
+		"{{STARTER_JAR}}":         c.StarterJar(),
+		"{{MAVEN_VERSION}}":       c.MavenVersion,
+		"{{DEFAULT_PROFILE}}":     c.DefaultProfile(),
 	}
+	for k, v := range c.databaseReplacements() {
+		replacements[k] = v
+	}
+	for k, v := range c.persistenceReplacements() {
+		replacements[k] = v
+	}
+	for k, v := range c.migrationReplacements() {
+		replacements[k] = v
+	}
+	for k, v := range c.tenantReplacements() {
+		replacements[k] = v
+	}
+	for k, v := range c.idReplacements() {
+		replacements[k] = v
+	}
+	return replacements
 }
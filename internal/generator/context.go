@@ -0,0 +1,226 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/templates"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// AddContextOptions holds the options for adding a bounded context
+type AddContextOptions struct {
+	// Wire adds the context's application module as a dependency of adapter-rest
+	Wire bool
+}
+
+// AddBoundedContext adds a bounded context made of its own domain-<name>,
+// infrastructure-<name> and application-<name> modules
+func AddBoundedContext(contextName string, opts AddContextOptions) error {
+	if !validateModuleName(contextName) {
+		return fmt.Errorf("上下文名称格式不正确，请使用小写字母和连字符")
+	}
+
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml或settings.gradle.kts的项目目录或其子目录中运行此命令", err)
+	}
+
+	utils.PrintInfo(fmt.Sprintf("项目根目录: %s", projectRoot))
+
+	config, err := extractProjectInfo(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	modules := contextModules(contextName)
+	for _, m := range modules {
+		if utils.DirExists(filepath.Join(projectRoot, m.path)) {
+			return fmt.Errorf("模块 %s 已存在", m.artifactID)
+		}
+	}
+
+	fmt.Println()
+	utils.PrintInfo(fmt.Sprintf("准备创建限界上下文: %s", contextName))
+	for _, m := range modules {
+		fmt.Printf("  - %s\n", m.path)
+	}
+	confirm, err := utils.ReadInput("确认继续？(y/n): ")
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(confirm, "y") && !strings.EqualFold(confirm, "yes") {
+		utils.PrintWarning("已取消操作")
+		return nil
+	}
+
+	utils.PrintInfo("创建模块目录结构...")
+	if err := createContextStructure(config, contextName); err != nil {
+		return err
+	}
+	utils.PrintSuccess("目录结构创建完成")
+
+	utils.PrintInfo("生成模块构建文件...")
+	if err := generateContextBuildFiles(config, contextName); err != nil {
+		return err
+	}
+	utils.PrintSuccess("模块构建文件生成完成")
+
+	utils.PrintInfo("在根构建文件中注册模块...")
+	for _, m := range modules {
+		if err := registerModule(config, m.path, m.artifactID); err != nil {
+			return err
+		}
+	}
+	utils.PrintSuccess("根构建文件更新完成")
+
+	utils.PrintInfo("将基础设施模块加入starter...")
+	if _, err := addModuleDependency(config, "starter", config.GroupID, "infrastructure-"+contextName, ""); err != nil {
+		return err
+	}
+	utils.PrintSuccess("starter依赖更新完成")
+
+	if opts.Wire {
+		utils.PrintInfo("将应用模块接入adapter-rest...")
+		if _, err := addModuleDependency(config, "adapter/adapter-rest", config.GroupID, "application-"+contextName, ""); err != nil {
+			return err
+		}
+		utils.PrintSuccess("adapter-rest依赖更新完成")
+	}
+
+	if config.usesMybatisPlus() {
+		utils.PrintInfo("更新@MapperScan...")
+		mapperPackage := fmt.Sprintf("%s.infrastructure.%s.persistence.mapper", config.PackageName, contextPackage(contextName))
+		if err := addMapperScan(config, mapperPackage); err != nil {
+			return err
+		}
+	}
+
+	utils.PrintInfo("生成示例Service类...")
+	if err := generateSampleService(config, contextName, false); err != nil {
+		return err
+	}
+	utils.PrintSuccess("示例Service类生成完成")
+
+	printContextSummary(config, contextName, opts)
+	return nil
+}
+
+type contextModule struct {
+	path       string
+	artifactID string
+}
+
+func contextModules(contextName string) []contextModule {
+	return []contextModule{
+		{"domain-" + contextName, "domain-" + contextName},
+		{"infrastructure-" + contextName, "infrastructure-" + contextName},
+		{"application/application-" + contextName, "application-" + contextName},
+	}
+}
+
+// contextPackage converts a context name to its Java package segment
+func contextPackage(contextName string) string {
+	return strings.ReplaceAll(contextName, "-", "")
+}
+
+func createContextStructure(config *ProjectConfig, contextName string) error {
+	baseDir := config.OutputDir
+	pkgPath := config.PackagePath
+	ctxPkg := contextPackage(contextName)
+
+	domainDir := filepath.Join(baseDir, "domain-"+contextName)
+	infraDir := filepath.Join(baseDir, "infrastructure-"+contextName)
+
+	dirs := []string{
+		filepath.Join(domainDir, "src/main/java", pkgPath, "domain", ctxPkg, "model"),
+		filepath.Join(domainDir, "src/main/java", pkgPath, "domain", ctxPkg, "event"),
+		filepath.Join(domainDir, "src/main/java", pkgPath, "domain", ctxPkg, "repository"),
+		filepath.Join(domainDir, "src/main/java", pkgPath, "domain", ctxPkg, "service"),
+		filepath.Join(domainDir, "src/main/resources"),
+		filepath.Join(domainDir, "src/test/java", pkgPath, "domain", ctxPkg),
+
+		filepath.Join(infraDir, "src/main/java", pkgPath, "infrastructure", ctxPkg, "persistence/dataobject"),
+		filepath.Join(infraDir, "src/main/java", pkgPath, "infrastructure", ctxPkg, "persistence", config.persistence().accessPackage),
+		filepath.Join(infraDir, "src/main/java", pkgPath, "infrastructure", ctxPkg, "persistence/impl"),
+		filepath.Join(infraDir, "src/main/resources/mapper", ctxPkg),
+		filepath.Join(infraDir, "src/test/java", pkgPath, "infrastructure", ctxPkg),
+	}
+	if err := utils.CreateDirs(dirs...); err != nil {
+		return err
+	}
+
+	return createModuleStructure(config, contextName)
+}
+
+func generateContextBuildFiles(config *ProjectConfig, contextName string) error {
+	replacements := config.GetReplacements()
+	replacements["{{CONTEXT_NAME}}"] = contextName
+	replacements["{{CONTEXT_DESCRIPTION}}"] = strings.ReplaceAll(contextName, "-", " ")
+
+	buildFiles := []struct {
+		modulePath string
+		maven      string
+		gradle     string
+	}{
+		{"domain-" + contextName, templates.DomainContextPOM, templates.DomainContextBuildGradle},
+		{"infrastructure-" + contextName, templates.InfrastructureContextPOM, templates.InfrastructureContextBuildGradle},
+		{"application/application-" + contextName, templates.ApplicationContextPOM, templates.ApplicationContextBuildGradle},
+	}
+
+	for _, f := range buildFiles {
+		if err := writeModuleBuildFile(config, f.modulePath, f.maven, f.gradle, replacements); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addMapperScan adds a mapper package to @MapperScan on the Application class
+func addMapperScan(config *ProjectConfig, mapperPackage string) error {
+	appPath := filepath.Join(config.OutputDir, "starter/src/main/java", config.PackagePath, "Application.java")
+	content, err := os.ReadFile(appPath)
+	if err != nil {
+		utils.PrintWarning(fmt.Sprintf("未找到 Application.java，请手动将 %s 加入 @MapperScan", mapperPackage))
+		return nil
+	}
+
+	updated, ok := addMapperScanPackage(string(content), mapperPackage)
+	if !ok {
+		utils.PrintWarning(fmt.Sprintf("无法识别 @MapperScan 的写法，请手动将 %s 加入 @MapperScan", mapperPackage))
+		return nil
+	}
+	if updated == string(content) {
+		return nil
+	}
+	if err := utils.WriteFile(appPath, updated); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("@MapperScan 已包含 %s", mapperPackage))
+	return nil
+}
+
+func printContextSummary(config *ProjectConfig, contextName string, opts AddContextOptions) {
+	fmt.Println()
+	utils.PrintSuccess("==========================================")
+	utils.PrintSuccess(fmt.Sprintf("限界上下文 %s 创建完成！", contextName))
+	utils.PrintSuccess("==========================================")
+	fmt.Println()
+	utils.PrintInfo("模块依赖关系：")
+	fmt.Printf("  application-%s → domain-%s, common\n", contextName, contextName)
+	fmt.Printf("  infrastructure-%s → domain-%s, common\n", contextName, contextName)
+	fmt.Printf("  domain-%s → common\n", contextName)
+	fmt.Printf("  starter → infrastructure-%s\n", contextName)
+	fmt.Println()
+	utils.PrintInfo("下一步：")
+	fmt.Printf("  1. 在 domain-%s 中定义聚合根和仓储接口\n", contextName)
+	fmt.Printf("  2. 在 infrastructure-%s 中实现仓储\n", contextName)
+	fmt.Printf("  3. 重新构建项目: %s\n", config.BuildCommand())
+	if !opts.Wire {
+		fmt.Printf("  4. 如需对外提供 REST 接口，请将 application-%s 加入 adapter-rest 的依赖\n", contextName)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,176 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/catalog"
+	"github.com/phixia/phjvgen/internal/gradle"
+	"github.com/phixia/phjvgen/internal/pom"
+	"github.com/phixia/phjvgen/internal/schema"
+)
+
+// DefaultDatabase is the database of a new project
+const DefaultDatabase = schema.MySQL
+
+// databaseVendor describes how a generated project connects to a database
+type databaseVendor struct {
+	name        string
+	displayName string
+	// driver is the dependency catalog name of the JDBC driver
+	driver      string
+	driverClass string
+	// dbType is the MyBatis-Plus DbType constant
+	dbType string
+	// flyway is the dependency catalog name of Flyway's support for the
+	// database, empty when Flyway supports it out of the box
+	flyway   string
+	username string
+	// localURL and envURL return the JDBC URL of a local database and of a
+	// database located through the DB_HOST, DB_PORT and DB_NAME variables
+	localURL func(database string) string
+	envURL   func(database string) string
+}
+
+var databaseVendors = []databaseVendor{
+	{
+		name: schema.MySQL, displayName: "MySQL 8.0+", driver: "mysql",
+		driverClass: "com.mysql.cj.jdbc.Driver", dbType: "MYSQL", flyway: "flyway-mysql", username: "root",
+		localURL: func(db string) string {
+			return "jdbc:mysql://localhost:3306/" + db + "?useSSL=false&serverTimezone=Asia/Shanghai&characterEncoding=utf8"
+		},
+		envURL: func(db string) string {
+			return "jdbc:mysql://${DB_HOST:localhost}:${DB_PORT:3306}/${DB_NAME:" + db + "}?useSSL=false&serverTimezone=Asia/Shanghai&characterEncoding=utf8"
+		},
+	},
+	{
+		name: schema.PostgreSQL, displayName: "PostgreSQL 14+", driver: "postgresql",
+		driverClass: "org.postgresql.Driver", dbType: "POSTGRE_SQL", flyway: "flyway-postgresql", username: "postgres",
+		localURL: func(db string) string {
+			return "jdbc:postgresql://localhost:5432/" + db
+		},
+		envURL: func(db string) string {
+			return "jdbc:postgresql://${DB_HOST:localhost}:${DB_PORT:5432}/${DB_NAME:" + db + "}"
+		},
+	},
+	{
+		name: schema.MariaDB, displayName: "MariaDB 10.6+", driver: "mariadb",
+		driverClass: "org.mariadb.jdbc.Driver", dbType: "MARIADB", flyway: "flyway-mysql", username: "root",
+		localURL: func(db string) string {
+			return "jdbc:mariadb://localhost:3306/" + db
+		},
+		envURL: func(db string) string {
+			return "jdbc:mariadb://${DB_HOST:localhost}:${DB_PORT:3306}/${DB_NAME:" + db + "}"
+		},
+	},
+	{
+		name: schema.H2, displayName: "H2 2.x", driver: "h2",
+		driverClass: "org.h2.Driver", dbType: "H2", username: "sa",
+		localURL: func(db string) string {
+			return "jdbc:h2:file:./data/" + db + ";AUTO_SERVER=TRUE"
+		},
+		envURL: func(db string) string {
+			return "jdbc:h2:mem:${DB_NAME:" + db + "};DB_CLOSE_DELAY=-1"
+		},
+	},
+}
+
+// ValidateDatabase checks the value of --db
+func ValidateDatabase(name string) error {
+	if _, ok := lookupDatabaseVendor(name); !ok {
+		return fmt.Errorf("不支持的数据库: %s（可选: %s）", name, strings.Join(schema.Names(), ", "))
+	}
+	return nil
+}
+
+func lookupDatabaseVendor(name string) (databaseVendor, bool) {
+	for _, v := range databaseVendors {
+		if v.name == name {
+			return v, true
+		}
+	}
+	return databaseVendor{}, false
+}
+
+// database returns the project's database vendor
+func (c *ProjectConfig) database() databaseVendor {
+	if v, ok := lookupDatabaseVendor(c.Database); ok {
+		return v
+	}
+	v, _ := lookupDatabaseVendor(DefaultDatabase)
+	return v
+}
+
+// dialect returns the DDL dialect of the project's database
+func (c *ProjectConfig) dialect() schema.Dialect {
+	d, _ := schema.Lookup(c.database().name)
+	return d
+}
+
+// databaseReplacements returns the template placeholders of the project's database
+func (c *ProjectConfig) databaseReplacements() map[string]string {
+	vendor := c.database()
+	driver, _ := catalog.Lookup(vendor.driver)
+	return map[string]string{
+		"{{DB_DISPLAY_NAME}}":            vendor.displayName,
+		"{{DB_DRIVER_CLASS}}":            vendor.driverClass,
+		"{{DB_TYPE}}":                    vendor.dbType,
+		"{{DB_USERNAME}}":                vendor.username,
+		"{{DB_LOCAL_URL}}":               vendor.localURL(c.ArtifactID),
+		"{{DB_ENV_URL}}":                 vendor.envURL(c.ArtifactID),
+		"{{DB_DRIVER_GROUP_ID}}":         driver.GroupID,
+		"{{DB_DRIVER_ARTIFACT_ID}}":      driver.ArtifactID,
+		"{{DB_DRIVER_VERSION}}":          driver.Version,
+		"{{DB_DRIVER_VERSION_PROPERTY}}": driver.VersionProperty,
+		"{{DB_DRIVER_VERSION_NAME}}":     catalogVersionName(driver),
+		"{{DB_DRIVER_ACCESSOR}}":         gradle.Library{Alias: driver.ArtifactID}.Accessor(),
+	}
+}
+
+// detectDatabase finds the database of an existing project from the JDBC
+// driver declared in the root build, falling back to DefaultDatabase
+func detectDatabase(projectRoot, buildTool string) string {
+	var doc *pom.Document
+	if buildTool == BuildToolMaven {
+		doc, _ = pom.Load(filepath.Join(projectRoot, "pom.xml"))
+	}
+	for _, vendor := range databaseVendors {
+		driver, _ := catalog.Lookup(vendor.driver)
+		if buildTool == BuildToolGradle {
+			if _, ok := loadCatalogLibrary(projectRoot, driver.GroupID, driver.ArtifactID); ok {
+				return vendor.name
+			}
+		} else if doc != nil && doc.HasManagedDependency(driver.GroupID, driver.ArtifactID) {
+			return vendor.name
+		}
+	}
+	return DefaultDatabase
+}
+
+// userTable is the table of the demo User aggregate
+var userTable = schema.Table{
+	Name:    "t_user",
+	Comment: "用户表",
+	Columns: []schema.Column{
+		{Name: "id", Type: schema.BigInt, NotNull: true, AutoIncrement: true, Comment: "主键ID"},
+		{Name: "username", Type: schema.Varchar, Length: 50, NotNull: true, Comment: "用户名"},
+		{Name: "email", Type: schema.Varchar, Length: 100, Comment: "邮箱"},
+		{Name: "phone", Type: schema.Varchar, Length: 20, Comment: "手机号"},
+		{Name: "status", Type: schema.TinyInt, NotNull: true, Default: "1", Comment: "状态：0-禁用，1-启用"},
+		{Name: "create_time", Type: schema.DateTime, NotNull: true, Default: schema.CurrentTimestamp, Comment: "创建时间"},
+		{Name: "update_time", Type: schema.DateTime, NotNull: true, Default: schema.CurrentTimestamp, OnUpdateCurrentTimestamp: true, Comment: "更新时间"},
+		{Name: "deleted", Type: schema.TinyInt, NotNull: true, Default: "0", Comment: "删除标记：0-未删除，1-已删除"},
+	},
+	PrimaryKey: []string{"id"},
+	Indexes: []schema.Index{
+		{Name: "uk_username", Columns: []string{"username"}, Unique: true},
+		{Name: "idx_email", Columns: []string{"email"}},
+		{Name: "idx_phone", Columns: []string{"phone"}},
+	},
+}
+
+// userTableSQL renders the migration creating the demo user table
+func userTableSQL(config *ProjectConfig) string {
+	return config.dialect().CreateTable(scaffoldUserTable(config))
+}
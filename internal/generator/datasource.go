@@ -0,0 +1,238 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/templates"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// AddDataSourceOptions holds the options for adding a datasource
+type AddDataSourceOptions struct {
+	// ReadOnly adds a read replica of the primary datasource, used by
+	// read-only transactions, instead of a datasource with its own mappers
+	ReadOnly bool
+}
+
+// reservedDataSourceNames are used by the primary datasource configuration
+var reservedDataSourceNames = map[string]bool{"primary": true, "replicas": true}
+
+// envOnlyValueRe matches settings taken from the environment without a
+// default, e.g. ${DB_URL}
+var envOnlyValueRe = regexp.MustCompile(`^\$\{[A-Z0-9_]+\}$`)
+
+// AddDataSource adds a datasource to the project. A read replica joins the
+// routing datasource of the primary one; any other datasource gets its own
+// DataSource, SqlSessionFactory, transaction manager and mapper package.
+// Both are configured under app.datasource in every profile file.
+func AddDataSource(name string, opts AddDataSourceOptions) error {
+	if !validateModuleName(name) {
+		return fmt.Errorf("数据源名称格式不正确，请使用小写字母、数字和连字符")
+	}
+	if reservedDataSourceNames[name] {
+		return fmt.Errorf("数据源名称 %s 已被主数据源配置使用", name)
+	}
+
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml或settings.gradle.kts的项目目录或其子目录中运行此命令", err)
+	}
+
+	utils.PrintInfo(fmt.Sprintf("项目根目录: %s", projectRoot))
+
+	config, err := extractProjectInfo(projectRoot)
+	if err != nil {
+		return err
+	}
+	if !opts.ReadOnly && !config.usesMybatisPlus() {
+		return fmt.Errorf("独立数据源目前仅支持 MyBatis-Plus 项目，%s 项目可使用 --readonly 添加只读副本", config.persistence().displayName)
+	}
+
+	profileFiles, err := listProfileFiles(projectRoot)
+	if err != nil {
+		return err
+	}
+	if len(profileFiles) == 0 {
+		return fmt.Errorf("未找到 starter/src/main/resources/application-<profile>.yml")
+	}
+	path := dataSourceYAMLPath(name, opts.ReadOnly)
+	for _, file := range profileFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if yamlHasKey(string(content), path) {
+			return fmt.Errorf("%s 中已配置数据源 %s", filepath.Base(file), name)
+		}
+	}
+	configDir := filepath.Join(projectRoot, "infrastructure/src/main/java", config.PackagePath, "infrastructure/config/datasource")
+	configFile := filepath.Join(configDir, toCamelCase(name)+"DataSourceConfig.java")
+	if !opts.ReadOnly && utils.FileExists(configFile) {
+		return fmt.Errorf("%s 已存在", filepath.Base(configFile))
+	}
+
+	fmt.Println()
+	if opts.ReadOnly {
+		utils.PrintInfo(fmt.Sprintf("准备添加只读副本: %s（只读事务将路由到该副本）", name))
+	} else {
+		utils.PrintInfo(fmt.Sprintf("准备添加数据源: %s", name))
+	}
+	fmt.Println("  - 配置类: infrastructure/config/datasource")
+	fmt.Printf("  - 配置项: %s（%d 个 profile 文件）\n", strings.Join(path, "."), len(profileFiles))
+	confirm, err := utils.ReadInput("确认继续？(y/n): ")
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(confirm, "y") && !strings.EqualFold(confirm, "yes") {
+		utils.PrintWarning("已取消操作")
+		return nil
+	}
+
+	replacements := dataSourceReplacements(config, name)
+
+	utils.PrintInfo("生成数据源配置类...")
+	files := map[string]string{
+		"PrimaryDataSourceConfig.java":     templates.PrimaryDataSourceConfig,
+		"ReplicaDataSourceProperties.java": templates.ReplicaDataSourceProperties,
+		"ReadWriteRoutingDataSource.java":  templates.ReadWriteRoutingDataSource,
+	}
+	if !opts.ReadOnly {
+		files["PrimaryMybatisConfig.java"] = templates.PrimaryMybatisConfig
+		files[filepath.Base(configFile)] = templates.SecondaryDataSourceConfig
+	}
+	names := make([]string, 0, len(files))
+	for file := range files {
+		names = append(names, file)
+	}
+	sort.Strings(names)
+	for _, file := range names {
+		// The shared classes are generated by the first datasource added
+		target := filepath.Join(configDir, file)
+		if utils.FileExists(target) {
+			continue
+		}
+		if err := utils.WriteFile(target, utils.ReplacePlaceholders(files[file], replacements)); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("已生成 %s", file))
+	}
+
+	if !opts.ReadOnly {
+		pkgDir := filepath.Join(projectRoot, "infrastructure/src/main/java", config.PackagePath, "infrastructure/persistence", replacements["{{DATASOURCE_PACKAGE}}"])
+		if err := utils.CreateDirs(
+			filepath.Join(pkgDir, "mapper"),
+			filepath.Join(pkgDir, "dataobject"),
+			filepath.Join(projectRoot, "infrastructure/src/main/resources", "mapper-"+name),
+		); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("已创建 Mapper 包 infrastructure.persistence.%s.mapper", replacements["{{DATASOURCE_PACKAGE}}"]))
+	}
+
+	utils.PrintInfo("更新 profile 配置文件...")
+	for _, file := range profileFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		entry := dataSourceYAML(string(content), name, len(path)-1)
+		if err := utils.WriteFile(file, insertYAMLEntry(string(content), path[:len(path)-1], entry)); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("%s 已添加 %s", filepath.Base(file), strings.Join(path, ".")))
+	}
+
+	printDataSourceSummary(config, name, opts)
+	return nil
+}
+
+// dataSourceYAMLPath returns the key of a datasource's settings
+func dataSourceYAMLPath(name string, readOnly bool) []string {
+	if readOnly {
+		return []string{"app", "datasource", "replicas", name}
+	}
+	return []string{"app", "datasource", name}
+}
+
+func dataSourceReplacements(config *ProjectConfig, name string) map[string]string {
+	className := toCamelCase(name)
+	replacements := config.GetReplacements()
+	replacements["{{DATASOURCE_NAME}}"] = name
+	replacements["{{DATASOURCE_PACKAGE}}"] = strings.ReplaceAll(name, "-", "")
+	replacements["{{DATASOURCE_CLASS}}"] = className
+	replacements["{{DATASOURCE_BEAN}}"] = lowerFirst(className)
+	return replacements
+}
+
+// dataSourceYAML renders the settings of a datasource for a profile file.
+// Profiles that take the primary connection from the environment without a
+// default require the datasource's too; the others fall back to the primary
+// connection, so that the project keeps starting before it is configured.
+func dataSourceYAML(profile, name string, depth int) string {
+	env := strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_DB"
+	envOnly := envOnlyValueRe.MatchString(yamlValue(profile, []string{"spring", "datasource", "url"}))
+
+	indent := strings.Repeat("  ", depth)
+	var b strings.Builder
+	if !envOnly {
+		fmt.Fprintf(&b, "%s# 未配置 %s_* 环境变量时连接主库\n", indent, env)
+	}
+	fmt.Fprintf(&b, "%s%s:\n", indent, name)
+	for _, setting := range []struct{ key, suffix, primary string }{
+		{"jdbc-url", "URL", "url"},
+		{"username", "USERNAME", "username"},
+		{"password", "PASSWORD", "password"},
+	} {
+		if envOnly {
+			fmt.Fprintf(&b, "%s  %s: ${%s_%s}\n", indent, setting.key, env, setting.suffix)
+		} else {
+			fmt.Fprintf(&b, "%s  %s: ${%s_%s:${spring.datasource.%s}}\n", indent, setting.key, env, setting.suffix, setting.primary)
+		}
+	}
+	fmt.Fprintf(&b, "%s  driver-class-name: ${spring.datasource.driver-class-name}\n", indent)
+	fmt.Fprintf(&b, "%s  maximum-pool-size: 10\n", indent)
+	return b.String()
+}
+
+// listProfileFiles returns the application-<profile>.yml files of the
+// starter, leaving out the git-ignored local overrides
+func listProfileFiles(projectRoot string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(projectRoot, "starter/src/main/resources/application-*.yml"))
+	if err != nil {
+		return nil, err
+	}
+	var profiles []string
+	for _, f := range files {
+		if filepath.Base(f) != "application-"+localProfile+".yml" {
+			profiles = append(profiles, f)
+		}
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+func printDataSourceSummary(config *ProjectConfig, name string, opts AddDataSourceOptions) {
+	env := strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_DB"
+	fmt.Println()
+	utils.PrintSuccess("==========================================")
+	utils.PrintSuccess(fmt.Sprintf("数据源 %s 添加完成！", name))
+	utils.PrintSuccess("==========================================")
+	fmt.Println()
+	utils.PrintInfo("下一步：")
+	fmt.Printf("  1. 通过 %s_URL、%s_USERNAME、%s_PASSWORD 配置连接\n", env, env, env)
+	if opts.ReadOnly {
+		fmt.Println("  2. 查询方法使用 @Transactional(readOnly = true)，即会路由到只读副本")
+		fmt.Printf("  3. 重新构建项目: %s\n", config.BuildCommand())
+	} else {
+		bean := lowerFirst(toCamelCase(name))
+		fmt.Printf("  2. 在 infrastructure.persistence.%s.mapper 包中编写 Mapper，XML 放在 resources/mapper-%s\n", strings.ReplaceAll(name, "-", ""), name)
+		fmt.Printf("  3. 该数据源的事务使用 @Transactional(transactionManager = \"%sTransactionManager\")\n", bean)
+		fmt.Printf("  4. 重新构建项目: %s\n", config.BuildCommand())
+	}
+	fmt.Println()
+}
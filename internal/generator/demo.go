@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/phixia/phjvgen/internal/gradle"
+	"github.com/phixia/phjvgen/internal/pom"
 	"github.com/phixia/phjvgen/internal/templates"
 	"github.com/phixia/phjvgen/internal/utils"
 )
@@ -16,13 +18,13 @@ func GenerateDemoCode() error {
 	// Find project root by searching for pom.xml
 	projectRoot, err := findProjectRoot()
 	if err != nil {
-		return fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml的项目目录或其子目录中运行此命令", err)
+		return fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml或settings.gradle.kts的项目目录或其子目录中运行此命令", err)
 	}
 
 	utils.PrintInfo(fmt.Sprintf("项目根目录: %s", projectRoot))
 
 	// Extract project info from pom.xml
-	config, err := extractProjectInfoFromPOM(projectRoot)
+	config, err := extractProjectInfo(projectRoot)
 	if err != nil {
 		return err
 	}
@@ -35,7 +37,7 @@ func GenerateDemoCode() error {
 	utils.PrintWarning("此命令将生成完整的User模块CRUD示例代码，包括：")
 	fmt.Println("  - Common层：Result、BusinessException、ErrorCode")
 	fmt.Println("  - Domain层：User实体、UserRepository接口")
-	fmt.Println("  - Infrastructure层：UserDO、UserMapper、UserRepositoryImpl")
+	fmt.Printf("  - Infrastructure层：UserDO、%s、UserRepositoryImpl\n", config.persistence().accessType)
 	fmt.Println("  - Application层：UserDTO、UserService、UserAssembler")
 	fmt.Println("  - Adapter层：UserController、Request/Response、ExceptionHandler")
 	fmt.Println("  - Starter层：Application主类")
@@ -92,37 +94,52 @@ func GenerateDemoCode() error {
 	return nil
 }
 
-// findProjectRoot searches for the project root containing pom.xml
-// It starts from current directory and walks up the tree
+// findProjectRoot searches for the root of the multi-module project.
+// Starting from the nearest pom.xml above the current directory, it keeps
+// walking up while the next POM above aggregates the current one,
+// so running from inside any module finds the top-level aggregator.
 func findProjectRoot() (string, error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
 		return "", err
 	}
 
-	// Check current directory and parent directories
+	// Find the nearest directory with a pom.xml. A Gradle build has a single
+	// settings script at its root, so finding one ends the search.
 	dir := currentDir
-	for {
-		pomPath := filepath.Join(dir, "pom.xml")
-		if utils.FileExists(pomPath) {
-			// Verify it's a valid project structure
-			if utils.DirExists(filepath.Join(dir, "domain")) ||
-			   utils.DirExists(filepath.Join(dir, "common")) ||
-			   utils.DirExists(filepath.Join(dir, "application")) {
-				return dir, nil
-			}
+	for !utils.FileExists(filepath.Join(dir, "pom.xml")) {
+		if utils.FileExists(filepath.Join(dir, gradle.SettingsFile)) {
+			return dir, nil
 		}
-
-		// Go up one directory
 		parentDir := filepath.Dir(dir)
 		if parentDir == dir {
-			// Reached root directory
-			break
+			return "", fmt.Errorf("未找到pom.xml或%s", gradle.SettingsFile)
 		}
 		dir = parentDir
 	}
 
-	return "", fmt.Errorf("未找到包含pom.xml和项目模块的目录")
+	model, err := pom.Read(filepath.Join(dir, "pom.xml"))
+	if err != nil {
+		return "", err
+	}
+
+	// Walk up through the aggregators. Grouping directories such as
+	// application/ have no POM of their own and are skipped.
+	for ancestor := filepath.Dir(dir); ancestor != filepath.Dir(ancestor); ancestor = filepath.Dir(ancestor) {
+		if !utils.FileExists(filepath.Join(ancestor, "pom.xml")) {
+			continue
+		}
+		ancestorModel, err := pom.Read(filepath.Join(ancestor, "pom.xml"))
+		if err != nil || !ancestorModel.HasModule(dir) {
+			break
+		}
+		dir, model = ancestor, ancestorModel
+	}
+
+	if len(model.Modules) == 0 {
+		return "", fmt.Errorf("%s 不是多模块项目的聚合POM", filepath.Join(dir, "pom.xml"))
+	}
+	return dir, nil
 }
 
 func generateCommonCode(config *ProjectConfig) error {
@@ -174,15 +191,10 @@ func generateDomainCode(config *ProjectConfig) error {
 
 func generateInfrastructureCode(config *ProjectConfig) error {
 	replacements := config.GetReplacements()
-	pkgPath := config.PackagePath
 	baseDir := config.OutputDir
 
-	files := map[string]string{
-		filepath.Join(baseDir, "infrastructure/src/main/java", pkgPath, "infrastructure/persistence/dataobject/UserDO.java"): templates.UserDO,
-		filepath.Join(baseDir, "infrastructure/src/main/java", pkgPath, "infrastructure/persistence/mapper/UserMapper.java"): templates.UserMapper,
-		filepath.Join(baseDir, "infrastructure/src/main/java", pkgPath, "infrastructure/persistence/impl/UserRepositoryImpl.java"): templates.UserRepositoryImpl,
-		filepath.Join(baseDir, "infrastructure/src/main/resources/db/migration/V1__create_user_table.sql"): templates.UserTableSQL,
-	}
+	files := userPersistenceFiles(config)
+	files[filepath.Join(baseDir, "infrastructure/src/main/resources/db/migration/V1__create_user_table.sql")] = migrationScriptContent(config, "1", userTableSQL(config))
 
 	for path, template := range files {
 		content := utils.ReplacePlaceholders(template, replacements)
@@ -191,7 +203,8 @@ func generateInfrastructureCode(config *ProjectConfig) error {
 		}
 	}
 
-	return nil
+	// V1 creates the user table, so it starts out in the schema snapshot
+	return recordManifestTable(baseDir, scaffoldUserTable(config))
 }
 
 func generateApplicationCode(config *ProjectConfig) error {
@@ -269,27 +282,22 @@ func generateStarterCode(config *ProjectConfig) error {
 }
 
 func extractProjectInfoFromPOM(projectRoot string) (*ProjectConfig, error) {
-	// Read pom.xml from project root
-	pomPath := filepath.Join(projectRoot, "pom.xml")
-	content, err := os.ReadFile(pomPath)
+	model, err := pom.Read(filepath.Join(projectRoot, "pom.xml"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read pom.xml: %w", err)
 	}
 
-	pomContent := string(content)
-
-	// Extract groupId (simple parsing)
-	groupID := extractXMLTag(pomContent, "groupId")
-	if groupID == "" {
+	groupID := model.GroupID
+	if groupID == "" || strings.Contains(groupID, "${") {
 		return nil, fmt.Errorf("could not extract groupId from pom.xml")
 	}
 
-	version := extractXMLTag(pomContent, "version")
+	version := model.Version
 	if version == "" {
 		version = "1.0.0"
 	}
 
-	artifactID := extractXMLTag(pomContent, "artifactId")
+	artifactID := model.ArtifactID
 	if artifactID == "" {
 		artifactID = "app"
 	}
@@ -298,33 +306,17 @@ func extractProjectInfoFromPOM(projectRoot string) (*ProjectConfig, error) {
 	packagePath := strings.ReplaceAll(groupID, ".", "/")
 
 	return &ProjectConfig{
-		GroupID:     groupID,
-		ArtifactID:  artifactID,
-		Version:     version,
-		PackageName: packageName,
-		PackagePath: packagePath,
-		OutputDir:   projectRoot,
+		GroupID:            groupID,
+		ArtifactID:         artifactID,
+		Version:            version,
+		ProjectName:        model.Name,
+		ProjectDescription: model.Description,
+		PackageName:        packageName,
+		PackagePath:        packagePath,
+		OutputDir:          projectRoot,
 	}, nil
 }
 
-func extractXMLTag(content, tag string) string {
-	startTag := "<" + tag + ">"
-	endTag := "</" + tag + ">"
-
-	startIdx := strings.Index(content, startTag)
-	if startIdx == -1 {
-		return ""
-	}
-	startIdx += len(startTag)
-
-	endIdx := strings.Index(content[startIdx:], endTag)
-	if endIdx == -1 {
-		return ""
-	}
-
-	return strings.TrimSpace(content[startIdx : startIdx+endIdx])
-}
-
 func printDemoSummary() {
 	fmt.Println()
 	utils.PrintSuccess("==========================================")
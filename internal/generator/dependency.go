@@ -0,0 +1,385 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/catalog"
+	"github.com/phixia/phjvgen/internal/gradle"
+	"github.com/phixia/phjvgen/internal/pom"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// DependencyOptions holds the options of the dep commands
+type DependencyOptions struct {
+	// Module is the artifactId of the module to add to or remove from
+	Module string
+}
+
+// projectModule is a module declared in the root build
+type projectModule struct {
+	path       string
+	artifactID string
+}
+
+// AddDependency adds a catalog dependency to a module. The version is
+// declared once in the root build: a property and dependencyManagement entry
+// for Maven, the version catalog for Gradle.
+func AddDependency(name string, opts DependencyOptions) error {
+	entry, err := lookupCatalogEntry(name)
+	if err != nil {
+		return err
+	}
+
+	config, modules, err := loadDependencyProject()
+	if err != nil {
+		return err
+	}
+	module, err := findProjectModule(modules, opts.Module)
+	if err != nil {
+		return err
+	}
+
+	role := catalog.ModuleRole(module.artifactID)
+	if !entry.Allows(role) {
+		return fmt.Errorf("依赖 %s 不能添加到 %s 模块（允许的模块: %s）", entry.Name, module.artifactID, strings.Join(entry.Modules, ", "))
+	}
+
+	if err := declareDependencyVersion(config, entry); err != nil {
+		return err
+	}
+
+	added, err := addModuleDependency(config, module.path, entry.GroupID, entry.ArtifactID, entry.Scope)
+	if err != nil {
+		return err
+	}
+	if !added {
+		utils.PrintWarning(fmt.Sprintf("%s 已依赖 %s", module.artifactID, entry.Coordinates()))
+		return nil
+	}
+	utils.PrintSuccess(fmt.Sprintf("已将 %s 添加到 %s", entry.Coordinates(), module.artifactID))
+
+	fmt.Println()
+	utils.PrintInfo("下一步：")
+	fmt.Printf("  重新构建项目: %s\n", config.BuildCommand())
+	fmt.Println()
+	return nil
+}
+
+// RemoveDependency removes a catalog dependency from one module, or from every
+// module when opts.Module is empty. Once no module uses the dependency, its
+// version declaration is removed from the root build as well.
+func RemoveDependency(name string, opts DependencyOptions) error {
+	entry, err := lookupCatalogEntry(name)
+	if err != nil {
+		return err
+	}
+
+	config, modules, err := loadDependencyProject()
+	if err != nil {
+		return err
+	}
+	targets := modules
+	if opts.Module != "" {
+		module, err := findProjectModule(modules, opts.Module)
+		if err != nil {
+			return err
+		}
+		targets = []projectModule{module}
+	}
+
+	removed := 0
+	for _, module := range targets {
+		ok, err := removeModuleDependency(config, module.path, entry)
+		if err != nil {
+			return err
+		}
+		if ok {
+			utils.PrintSuccess(fmt.Sprintf("已从 %s 移除 %s", module.artifactID, entry.Coordinates()))
+			removed++
+		}
+	}
+	if removed == 0 {
+		return fmt.Errorf("没有模块依赖 %s", entry.Coordinates())
+	}
+
+	for _, module := range modules {
+		if moduleUsesDependency(config, module.path, entry) {
+			return nil
+		}
+	}
+	return removeDependencyVersion(config, entry)
+}
+
+// ListDependencies prints the catalog. Inside a project it also shows the
+// modules each dependency has been added to.
+func ListDependencies() error {
+	var config *ProjectConfig
+	var modules []projectModule
+	if _, err := findProjectRoot(); err == nil {
+		if config, modules, err = loadDependencyProject(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	utils.PrintInfo("可用依赖：")
+	fmt.Println()
+	for _, entry := range catalog.All() {
+		version := entry.Version
+		if version == "" {
+			version = "由 Spring Boot BOM 管理"
+		}
+		fmt.Printf("  %-24s %s\n", entry.Name, entry.Description)
+		fmt.Printf("  %-24s %s (%s)\n", "", entry.Coordinates(), version)
+		fmt.Printf("  %-24s 允许的模块: %s\n", "", strings.Join(entry.Modules, ", "))
+
+		if config != nil {
+			var used []string
+			for _, module := range modules {
+				if moduleUsesDependency(config, module.path, entry) {
+					used = append(used, module.artifactID)
+				}
+			}
+			if len(used) > 0 {
+				fmt.Printf("  %-24s 已添加到: %s\n", "", strings.Join(used, ", "))
+			}
+		}
+		fmt.Println()
+	}
+
+	utils.PrintInfo("使用 phjvgen dep add <name> --to <module> 添加依赖")
+	fmt.Println()
+	return nil
+}
+
+func lookupCatalogEntry(name string) (catalog.Entry, error) {
+	entry, ok := catalog.Lookup(name)
+	if !ok {
+		return catalog.Entry{}, fmt.Errorf("依赖目录中没有 %s\n提示: 使用 phjvgen dep list 查看可用依赖", name)
+	}
+	return entry, nil
+}
+
+func loadDependencyProject() (*ProjectConfig, []projectModule, error) {
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return nil, nil, fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml或settings.gradle.kts的项目目录或其子目录中运行此命令", err)
+	}
+
+	config, err := extractProjectInfo(projectRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	modules, err := listProjectModules(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, modules, nil
+}
+
+// listProjectModules returns the modules declared in the root build
+func listProjectModules(config *ProjectConfig) ([]projectModule, error) {
+	var modules []projectModule
+	if config.isGradle() {
+		settings, err := gradle.Load(filepath.Join(config.OutputDir, gradle.SettingsFile))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range settings.Modules() {
+			modules = append(modules, projectModule{path: m.Path, artifactID: m.Name})
+		}
+		return modules, nil
+	}
+
+	model, err := pom.Read(filepath.Join(config.OutputDir, "pom.xml"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range model.Modules {
+		artifactID := filepath.Base(path)
+		if doc, err := pom.Load(filepath.Join(config.OutputDir, path, "pom.xml")); err == nil {
+			if id := doc.Root().ChildText("artifactId"); id != "" {
+				artifactID = id
+			}
+		}
+		modules = append(modules, projectModule{path: path, artifactID: artifactID})
+	}
+	return modules, nil
+}
+
+func findProjectModule(modules []projectModule, name string) (projectModule, error) {
+	var names []string
+	for _, m := range modules {
+		if m.artifactID == name || m.path == name {
+			return m, nil
+		}
+		names = append(names, m.artifactID)
+	}
+	return projectModule{}, fmt.Errorf("模块 %s 不存在（可选: %s）", name, strings.Join(names, ", "))
+}
+
+// catalogVersionName is the version catalog name of a version property,
+// e.g. redisson for redisson.version
+func catalogVersionName(entry catalog.Entry) string {
+	return strings.TrimSuffix(entry.VersionProperty, ".version")
+}
+
+// declareDependencyVersion declares the dependency's version in the root
+// build unless it is already declared
+func declareDependencyVersion(config *ProjectConfig, entry catalog.Entry) error {
+	if config.isGradle() {
+		catalogPath := filepath.Join(config.OutputDir, gradle.CatalogFile)
+		toml, err := gradle.Load(catalogPath)
+		if err != nil {
+			return err
+		}
+		if _, ok := toml.Library(entry.Coordinates()); ok {
+			return nil
+		}
+		lib := gradle.Library{Alias: entry.ArtifactID, Module: entry.Coordinates()}
+		if entry.Version != "" {
+			lib.VersionRef = catalogVersionName(entry)
+			if _, ok := toml.CatalogVersion(lib.VersionRef); !ok {
+				toml.SetCatalogVersion(lib.VersionRef, entry.Version)
+			}
+		}
+		toml.AddLibrary(lib)
+		if err := toml.Save(catalogPath); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("%s 已声明 %s", gradle.CatalogFile, lib.Alias))
+		return nil
+	}
+
+	if entry.Version == "" {
+		return nil
+	}
+	pomPath := filepath.Join(config.OutputDir, "pom.xml")
+	doc, err := pom.Load(pomPath)
+	if err != nil {
+		return err
+	}
+	if doc.HasManagedDependency(entry.GroupID, entry.ArtifactID) {
+		return nil
+	}
+	if _, ok := doc.Property(entry.VersionProperty); !ok {
+		if err := doc.SetProperty(entry.VersionProperty, entry.Version); err != nil {
+			return err
+		}
+	}
+	managed := pom.Dependency{GroupID: entry.GroupID, ArtifactID: entry.ArtifactID, Version: "${" + entry.VersionProperty + "}"}
+	if _, err := doc.AddManagedDependency(managed); err != nil {
+		return err
+	}
+	if err := doc.Save(pomPath); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("父pom.xml已声明 %s 版本 ${%s}", entry.ArtifactID, entry.VersionProperty))
+	return nil
+}
+
+// removeDependencyVersion removes the version declaration added by
+// declareDependencyVersion, keeping versions still referenced elsewhere
+func removeDependencyVersion(config *ProjectConfig, entry catalog.Entry) error {
+	if config.isGradle() {
+		catalogPath := filepath.Join(config.OutputDir, gradle.CatalogFile)
+		toml, err := gradle.Load(catalogPath)
+		if err != nil {
+			return err
+		}
+		lib, ok := toml.Library(entry.Coordinates())
+		if !ok {
+			return nil
+		}
+		toml.RemoveLibrary(lib.Alias)
+		if lib.VersionRef != "" && !toml.References(lib.VersionRef) {
+			toml.RemoveCatalogVersion(lib.VersionRef)
+		}
+		if err := toml.Save(catalogPath); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("已从 %s 移除 %s", gradle.CatalogFile, lib.Alias))
+		return nil
+	}
+
+	pomPath := filepath.Join(config.OutputDir, "pom.xml")
+	doc, err := pom.Load(pomPath)
+	if err != nil {
+		return err
+	}
+	removed, err := doc.RemoveManagedDependency(entry.GroupID, entry.ArtifactID)
+	if err != nil || !removed {
+		return err
+	}
+	if entry.VersionProperty != "" && !strings.Contains(string(doc.Bytes()), "${"+entry.VersionProperty+"}") {
+		if _, err := doc.RemoveProperty(entry.VersionProperty); err != nil {
+			return err
+		}
+	}
+	if err := doc.Save(pomPath); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("已从父pom.xml移除 %s 的版本声明", entry.ArtifactID))
+	return nil
+}
+
+// moduleUsesDependency reports whether the module's build file declares the dependency
+func moduleUsesDependency(config *ProjectConfig, modulePath string, entry catalog.Entry) bool {
+	buildPath := filepath.Join(config.OutputDir, modulePath, config.buildFileName())
+	if config.isGradle() {
+		build, err := gradle.Load(buildPath)
+		if err != nil {
+			return false
+		}
+		for _, notation := range gradleNotations(config, entry) {
+			if build.HasDependency(notation) {
+				return true
+			}
+		}
+		return false
+	}
+	doc, err := pom.Load(buildPath)
+	return err == nil && doc.HasDependency(entry.GroupID, entry.ArtifactID)
+}
+
+// removeModuleDependency removes the dependency from the module's build file
+func removeModuleDependency(config *ProjectConfig, modulePath string, entry catalog.Entry) (bool, error) {
+	buildPath := filepath.Join(config.OutputDir, modulePath, config.buildFileName())
+	if config.isGradle() {
+		build, err := gradle.Load(buildPath)
+		if err != nil {
+			return false, err
+		}
+		removed := 0
+		for _, notation := range gradleNotations(config, entry) {
+			removed += build.RemoveDependency(notation)
+		}
+		if removed == 0 {
+			return false, nil
+		}
+		return true, build.Save(buildPath)
+	}
+
+	doc, err := pom.Load(buildPath)
+	if err != nil {
+		return false, err
+	}
+	removed, err := doc.RemoveDependency(entry.GroupID, entry.ArtifactID)
+	if err != nil || !removed {
+		return false, err
+	}
+	return true, doc.Save(buildPath)
+}
+
+// gradleNotations returns the ways a Gradle script can refer to the
+// dependency: its coordinates and its catalog accessor
+func gradleNotations(config *ProjectConfig, entry catalog.Entry) []string {
+	notations := []string{entry.Coordinates()}
+	if lib, ok := loadCatalogLibrary(config.OutputDir, entry.GroupID, entry.ArtifactID); ok {
+		notations = append(notations, lib.Accessor())
+	}
+	return notations
+}
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 2
+
+// printDiff prints a line diff between two versions of a file, in the style
+// of diff -u
+func printDiff(name string, before, after string) {
+	a := strings.Split(before, "\n")
+	b := strings.Split(after, "\n")
+	ops := diffLines(a, b)
+
+	fmt.Printf("--- %s\n+++ %s\n", name, name)
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk while changes are close together
+		start := max(i-diffContext, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*diffContext {
+				break
+			}
+			end = next
+		}
+		end = min(end+diffContext, len(ops))
+
+		fmt.Println(utils.InfoColor(fmt.Sprintf("@@ -%d +%d @@", ops[start].oldLine, ops[start].newLine)))
+		for _, op := range ops[start:end] {
+			switch op.kind {
+			case '-':
+				fmt.Println(utils.ErrorColor("-" + op.text))
+			case '+':
+				fmt.Println(utils.SuccessColor("+" + op.text))
+			default:
+				fmt.Println(" " + op.text)
+			}
+		}
+		i = end
+	}
+}
+
+type diffOp struct {
+	kind    byte
+	text    string
+	oldLine int
+	newLine int
+}
+
+// diffLines computes a minimal line diff from the longest common subsequence
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i + 1, j + 1})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i], i + 1, j + 1})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j], i + 1, j + 1})
+			j++
+		}
+	}
+	return ops
+}
@@ -0,0 +1,222 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/catalog"
+	"github.com/phixia/phjvgen/internal/pom"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+const (
+	enforcerPluginGroupID     = "org.apache.maven.plugins"
+	enforcerPluginArtifactID  = "maven-enforcer-plugin"
+	enforcerPluginVersion     = "3.5.0"
+	enforcerVersionProperty   = "maven-enforcer-plugin.version"
+	defaultRequiredMavenRange = "[3.9,)"
+)
+
+// layerOrder lists the module roles from the innermost layer outwards
+var layerOrder = []string{
+	catalog.RoleCommon,
+	catalog.RoleDomain,
+	catalog.RoleInfrastructure,
+	catalog.RoleApplication,
+	catalog.RoleAdapter,
+	catalog.RoleStarter,
+}
+
+// layerBannedModules lists, per role, the project modules a layer must not
+// depend on, as artifactId patterns within the project's groupId
+var layerBannedModules = map[string][]string{
+	catalog.RoleCommon:         {"domain*", "infrastructure*", "application-*", "adapter-*", "starter"},
+	catalog.RoleDomain:         {"infrastructure*", "application-*", "adapter-*", "starter"},
+	catalog.RoleInfrastructure: {"application-*", "adapter-*", "starter"},
+	catalog.RoleApplication:    {"adapter-*", "starter"},
+	catalog.RoleAdapter:        {"infrastructure*", "starter"},
+}
+
+// layerExecutionID is the id of the enforcer execution holding a layer's rules
+func layerExecutionID(role string) string {
+	return "enforce-" + role + "-layer"
+}
+
+// layerBannedDependencies returns the dependencies banned in a layer: the
+// project modules of outer layers and the catalog dependencies that are not
+// allowed in the layer
+func layerBannedDependencies(role string) []string {
+	var banned []string
+	for _, pattern := range layerBannedModules[role] {
+		banned = append(banned, "${project.groupId}:"+pattern)
+	}
+	for _, entry := range catalog.All() {
+		if !entry.Allows(role) {
+			banned = append(banned, entry.Coordinates())
+		}
+	}
+	return banned
+}
+
+// enforcerManagedPlugin renders the pluginManagement entry of the parent POM.
+// Each layer has an execution bound to no phase; modules activate the one
+// of their layer.
+func enforcerManagedPlugin() string {
+	var b strings.Builder
+	b.WriteString("<plugin>\n")
+	fmt.Fprintf(&b, "    <groupId>%s</groupId>\n", enforcerPluginGroupID)
+	fmt.Fprintf(&b, "    <artifactId>%s</artifactId>\n", enforcerPluginArtifactID)
+	fmt.Fprintf(&b, "    <version>${%s}</version>\n", enforcerVersionProperty)
+	b.WriteString("    <executions>\n")
+	b.WriteString("        <!-- 分层依赖规则：各模块在 validate 阶段启用所属层的 execution -->\n")
+	for _, role := range layerOrder {
+		banned := layerBannedDependencies(role)
+		if len(banned) == 0 {
+			continue
+		}
+		b.WriteString("        <execution>\n")
+		fmt.Fprintf(&b, "            <id>%s</id>\n", layerExecutionID(role))
+		b.WriteString("            <phase>none</phase>\n")
+		b.WriteString("            <goals>\n")
+		b.WriteString("                <goal>enforce</goal>\n")
+		b.WriteString("            </goals>\n")
+		b.WriteString("            <configuration>\n")
+		b.WriteString("                <rules>\n")
+		b.WriteString("                    <bannedDependencies>\n")
+		b.WriteString("                        <searchTransitive>false</searchTransitive>\n")
+		b.WriteString("                        <excludes>\n")
+		for _, dep := range banned {
+			fmt.Fprintf(&b, "                            <exclude>%s</exclude>\n", dep)
+		}
+		b.WriteString("                        </excludes>\n")
+		fmt.Fprintf(&b, "                        <message>%s 层不允许直接依赖以上模块或库</message>\n", role)
+		b.WriteString("                    </bannedDependencies>\n")
+		b.WriteString("                </rules>\n")
+		b.WriteString("            </configuration>\n")
+		b.WriteString("        </execution>\n")
+	}
+	b.WriteString("    </executions>\n")
+	b.WriteString("</plugin>")
+	return b.String()
+}
+
+// enforcerBuildPlugin renders the rules every module of the build checks
+func enforcerBuildPlugin(config *ProjectConfig) string {
+	mavenRange := defaultRequiredMavenRange
+	if config.MavenVersion != "" {
+		mavenRange = "[" + config.MavenVersion + ",)"
+	}
+	return fmt.Sprintf(`<plugin>
+    <groupId>%s</groupId>
+    <artifactId>%s</artifactId>
+    <executions>
+        <execution>
+            <id>enforce-build</id>
+            <goals>
+                <goal>enforce</goal>
+            </goals>
+            <configuration>
+                <rules>
+                    <dependencyConvergence/>
+                    <requireJavaVersion>
+                        <version>[${java.version},)</version>
+                    </requireJavaVersion>
+                    <requireMavenVersion>
+                        <version>%s</version>
+                    </requireMavenVersion>
+                </rules>
+            </configuration>
+        </execution>
+    </executions>
+</plugin>`, enforcerPluginGroupID, enforcerPluginArtifactID, mavenRange)
+}
+
+// enforcerModulePlugin renders the plugin entry that activates a layer's rules in a module
+func enforcerModulePlugin(role string) string {
+	return fmt.Sprintf(`<plugin>
+    <groupId>%s</groupId>
+    <artifactId>%s</artifactId>
+    <executions>
+        <execution>
+            <id>%s</id>
+            <phase>validate</phase>
+        </execution>
+    </executions>
+</plugin>`, enforcerPluginGroupID, enforcerPluginArtifactID, layerExecutionID(role))
+}
+
+// addEnforcerRules adds the enforcer rules to the parent POM and activates
+// the layer rules in every module
+func addEnforcerRules(config *ProjectConfig) error {
+	pomPath := filepath.Join(config.OutputDir, "pom.xml")
+	doc, err := pom.Load(pomPath)
+	if err != nil {
+		return err
+	}
+	if err := doc.SetProperty(enforcerVersionProperty, enforcerPluginVersion); err != nil {
+		return err
+	}
+	if _, err := doc.AddManagedPlugin(enforcerPluginGroupID, enforcerPluginArtifactID, enforcerManagedPlugin()); err != nil {
+		return err
+	}
+	if _, err := doc.AddPlugin(enforcerPluginGroupID, enforcerPluginArtifactID, enforcerBuildPlugin(config)); err != nil {
+		return err
+	}
+	if err := doc.Save(pomPath); err != nil {
+		return err
+	}
+
+	modules, err := listProjectModules(config)
+	if err != nil {
+		return err
+	}
+	for _, m := range modules {
+		if err := bindEnforcerLayer(config, m.path, m.artifactID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindEnforcerLayer activates the rules of the module's layer in its POM. It
+// does nothing when the parent POM declares no rules for that layer.
+func bindEnforcerLayer(config *ProjectConfig, modulePath, artifactID string) error {
+	role := catalog.ModuleRole(artifactID)
+	if role == "" || !hasEnforcerLayer(config, role) {
+		return nil
+	}
+
+	pomPath := filepath.Join(config.OutputDir, modulePath, "pom.xml")
+	doc, err := pom.Load(pomPath)
+	if err != nil {
+		return err
+	}
+	added, err := doc.AddPlugin(enforcerPluginGroupID, enforcerPluginArtifactID, enforcerModulePlugin(role))
+	if err != nil {
+		return err
+	}
+	if !added {
+		utils.PrintWarning(fmt.Sprintf("%s 已声明 maven-enforcer-plugin，请手动启用 %s", artifactID, layerExecutionID(role)))
+		return nil
+	}
+	return doc.Save(pomPath)
+}
+
+// hasEnforcerLayer reports whether the parent POM manages the rules of a layer
+func hasEnforcerLayer(config *ProjectConfig, role string) bool {
+	doc, err := pom.Load(filepath.Join(config.OutputDir, "pom.xml"))
+	if err != nil {
+		return false
+	}
+	plugin := doc.ManagedPlugin(enforcerPluginGroupID, enforcerPluginArtifactID)
+	if plugin == nil {
+		return false
+	}
+	for _, execution := range plugin.Path("executions").ChildrenNamed("execution") {
+		if execution.ChildText("id") == layerExecutionID(role) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,286 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/catalog"
+	"github.com/phixia/phjvgen/internal/gradle"
+	"github.com/phixia/phjvgen/internal/pom"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// Graph output formats
+const (
+	GraphFormatDOT     = "dot"
+	GraphFormatMermaid = "mermaid"
+)
+
+// GraphOptions holds the options for exporting the module dependency graph
+type GraphOptions struct {
+	// Format is GraphFormatDOT or GraphFormatMermaid
+	Format string
+	// Output is the file to write, stdout when empty
+	Output string
+	// Check only reports the dependencies that violate the layer direction
+	Check bool
+}
+
+// moduleEdge is a dependency of one project module on another
+type moduleEdge struct {
+	from, to  string
+	violation bool
+}
+
+// moduleGraph is the internal dependency graph of a project
+type moduleGraph struct {
+	modules []string
+	edges   []moduleEdge
+}
+
+// ValidateGraphFormat checks the value of --format
+func ValidateGraphFormat(format string) error {
+	if format != GraphFormatDOT && format != GraphFormatMermaid {
+		return fmt.Errorf("不支持的图格式: %s（可选: %s, %s）", format, GraphFormatDOT, GraphFormatMermaid)
+	}
+	return nil
+}
+
+// Graph renders the dependency graph between the project's modules, marking
+// the dependencies that point against the layer direction
+func Graph(opts GraphOptions) error {
+	if err := ValidateGraphFormat(opts.Format); err != nil {
+		return err
+	}
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml或settings.gradle.kts的项目目录或其子目录中运行此命令", err)
+	}
+	config, err := extractProjectInfo(projectRoot)
+	if err != nil {
+		return err
+	}
+	graph, err := readModuleGraph(config)
+	if err != nil {
+		return err
+	}
+
+	var violations []moduleEdge
+	for _, e := range graph.edges {
+		if e.violation {
+			violations = append(violations, e)
+		}
+	}
+
+	if opts.Check {
+		if len(violations) == 0 {
+			utils.PrintSuccess(fmt.Sprintf("%d 个模块、%d 条依赖均符合分层方向", len(graph.modules), len(graph.edges)))
+			return nil
+		}
+		printLayerViolations(violations)
+		return fmt.Errorf("发现 %d 条违反分层方向的依赖", len(violations))
+	}
+
+	var content string
+	if opts.Format == GraphFormatMermaid {
+		content = renderMermaidGraph(graph)
+	} else {
+		content = renderDOTGraph(graph, config.ArtifactID)
+	}
+	if opts.Output == "" {
+		fmt.Print(content)
+		return nil
+	}
+
+	if err := utils.WriteFile(opts.Output, content); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("依赖图已写入 %s", opts.Output))
+	if len(violations) > 0 {
+		printLayerViolations(violations)
+	}
+	return nil
+}
+
+func printLayerViolations(violations []moduleEdge) {
+	utils.PrintWarning(fmt.Sprintf("%d 条依赖违反分层方向：", len(violations)))
+	for _, e := range violations {
+		fmt.Printf("  %s → %s（%s 层不允许依赖 %s 层）\n", e.from, e.to,
+			catalog.ModuleRole(e.from), catalog.ModuleRole(e.to))
+	}
+}
+
+// readModuleGraph reads the dependencies between the project's modules from
+// the module POMs, or from the project(...) references of the Gradle scripts
+func readModuleGraph(config *ProjectConfig) (*moduleGraph, error) {
+	modules, err := listProjectModules(config)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, m := range modules {
+		known[m.artifactID] = true
+	}
+
+	graph := &moduleGraph{}
+	for _, m := range modules {
+		graph.modules = append(graph.modules, m.artifactID)
+		deps, err := readModuleDependencies(config, m)
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range deps {
+			if known[dep] && dep != m.artifactID {
+				graph.edges = append(graph.edges, moduleEdge{from: m.artifactID, to: dep, violation: violatesLayerDirection(m.artifactID, dep)})
+			}
+		}
+	}
+
+	sortModulesByLayer(graph.modules)
+	return graph, nil
+}
+
+// readModuleDependencies returns the artifactIds of the project modules a module depends on
+func readModuleDependencies(config *ProjectConfig, m projectModule) ([]string, error) {
+	var deps []string
+	if config.isGradle() {
+		content, err := os.ReadFile(filepath.Join(config.OutputDir, m.path, gradle.BuildFile))
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range projectReferenceRe.FindAllStringSubmatch(string(content), -1) {
+			deps = append(deps, strings.TrimPrefix(ref[1], ":"))
+		}
+		return deps, nil
+	}
+
+	model, err := pom.Read(filepath.Join(config.OutputDir, m.path, "pom.xml"))
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range model.Dependencies {
+		if dep.GroupID == config.GroupID {
+			deps = append(deps, dep.ArtifactID)
+		}
+	}
+	return deps, nil
+}
+
+// violatesLayerDirection reports whether a dependency points from a layer to
+// one it must not depend on, using the same rules as the enforcer layers
+func violatesLayerDirection(from, to string) bool {
+	for _, pattern := range layerBannedModules[catalog.ModuleRole(from)] {
+		if ok, _ := path.Match(pattern, to); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sortModulesByLayer orders modules from the innermost layer outwards
+func sortModulesByLayer(modules []string) {
+	rank := func(artifactID string) int {
+		for i, role := range layerOrder {
+			if catalog.ModuleRole(artifactID) == role {
+				return i
+			}
+		}
+		return len(layerOrder)
+	}
+	sort.SliceStable(modules, func(i, j int) bool {
+		ri, rj := rank(modules[i]), rank(modules[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return modules[i] < modules[j]
+	})
+}
+
+// layerGroups groups the modules by layer role, in layer order. Modules
+// without a role come last under "other".
+func layerGroups(modules []string) ([]string, map[string][]string) {
+	groups := map[string][]string{}
+	for _, m := range modules {
+		role := catalog.ModuleRole(m)
+		if role == "" {
+			role = "other"
+		}
+		groups[role] = append(groups[role], m)
+	}
+	var roles []string
+	for _, role := range layerOrder {
+		if len(groups[role]) > 0 {
+			roles = append(roles, role)
+		}
+	}
+	if len(groups["other"]) > 0 {
+		roles = append(roles, "other")
+	}
+	return roles, groups
+}
+
+func renderDOTGraph(g *moduleGraph, name string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", name)
+	b.WriteString("    rankdir=BT;\n")
+	b.WriteString("    node [shape=box, style=rounded];\n")
+
+	roles, groups := layerGroups(g.modules)
+	for _, role := range roles {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "    subgraph \"cluster_%s\" {\n", role)
+		fmt.Fprintf(&b, "        label=%q;\n", role)
+		for _, m := range groups[role] {
+			fmt.Fprintf(&b, "        %q;\n", m)
+		}
+		b.WriteString("    }\n")
+	}
+
+	b.WriteString("\n")
+	for _, e := range g.edges {
+		if e.violation {
+			fmt.Fprintf(&b, "    %q -> %q [color=red, penwidth=2, label=\"违反分层\"];\n", e.from, e.to)
+			continue
+		}
+		fmt.Fprintf(&b, "    %q -> %q;\n", e.from, e.to)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func renderMermaidGraph(g *moduleGraph) string {
+	// Module names such as "end" are Mermaid keywords, so nodes get numbered ids
+	ids := map[string]string{}
+	for i, m := range g.modules {
+		ids[m] = fmt.Sprintf("m%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart BT\n")
+	roles, groups := layerGroups(g.modules)
+	for _, role := range roles {
+		fmt.Fprintf(&b, "    subgraph %s\n", role)
+		for _, m := range groups[role] {
+			fmt.Fprintf(&b, "        %s[\"%s\"]\n", ids[m], m)
+		}
+		b.WriteString("    end\n")
+	}
+
+	var violations []string
+	for i, e := range g.edges {
+		if e.violation {
+			fmt.Fprintf(&b, "    %s -- 违反分层 --> %s\n", ids[e.from], ids[e.to])
+			violations = append(violations, fmt.Sprint(i))
+			continue
+		}
+		fmt.Fprintf(&b, "    %s --> %s\n", ids[e.from], ids[e.to])
+	}
+	if len(violations) > 0 {
+		fmt.Fprintf(&b, "    linkStyle %s stroke:red,stroke-width:2px\n", strings.Join(violations, ","))
+	}
+	return b.String()
+}
@@ -0,0 +1,214 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/phixia/phjvgen/internal/schema"
+	"github.com/phixia/phjvgen/internal/templates"
+)
+
+// Primary key strategies of the scaffolded tables
+const (
+	// IDStrategyAuto lets the database assign the key on insert
+	IDStrategyAuto = "auto"
+	// IDStrategySnowflake assigns 64-bit Snowflake IDs before insert
+	IDStrategySnowflake = "snowflake"
+	// IDStrategyUUIDv7 assigns time-ordered UUIDs before insert
+	IDStrategyUUIDv7 = "uuidv7"
+)
+
+// DefaultIDStrategy is the primary key strategy of a new project
+const DefaultIDStrategy = IDStrategyAuto
+
+// ValidateIDStrategy checks the value of --id-strategy
+func ValidateIDStrategy(strategy string) error {
+	if strategy != IDStrategyAuto && strategy != IDStrategySnowflake && strategy != IDStrategyUUIDv7 {
+		return fmt.Errorf("不支持的主键策略: %s（可选: %s, %s, %s）", strategy, IDStrategyAuto, IDStrategySnowflake, IDStrategyUUIDv7)
+	}
+	return nil
+}
+
+// idStrategy returns the project's primary key strategy
+func (c *ProjectConfig) idStrategy() string {
+	if ValidateIDStrategy(c.IDStrategy) != nil {
+		return DefaultIDStrategy
+	}
+	return c.IDStrategy
+}
+
+// userIDType returns the Java type of the User aggregate's ID
+func (c *ProjectConfig) userIDType() string {
+	if c.idStrategy() == IDStrategyUUIDv7 {
+		return "String"
+	}
+	return "Long"
+}
+
+// idTable returns a scaffolded table with the primary key of the project's
+// ID strategy. Assigned keys are not generated by the database.
+func idTable(config *ProjectConfig, t schema.Table) schema.Table {
+	strategy := config.idStrategy()
+	if strategy == IDStrategyAuto {
+		return t
+	}
+	columns := make([]schema.Column, len(t.Columns))
+	for i, c := range t.Columns {
+		if len(t.PrimaryKey) == 1 && c.Name == t.PrimaryKey[0] {
+			c.AutoIncrement = false
+			if strategy == IDStrategyUUIDv7 {
+				c.Type, c.Length = schema.Varchar, 36
+			}
+		}
+		columns[i] = c
+	}
+	t.Columns = columns
+	return t
+}
+
+// scaffoldUserTable returns the demo user table as the project creates it
+func scaffoldUserTable(config *ProjectConfig) schema.Table {
+	return tenantTable(config, idTable(config, userTable))
+}
+
+// idGeneratorFiles returns the files of the ID generator, relative to the
+// project root. Database-assigned keys need none.
+func idGeneratorFiles(config *ProjectConfig) map[string]string {
+	var impl, template string
+	switch config.idStrategy() {
+	case IDStrategySnowflake:
+		impl, template = "SnowflakeIdGenerator.java", templates.SnowflakeIdGenerator
+	case IDStrategyUUIDv7:
+		impl, template = "UuidV7IdGenerator.java", templates.UuidV7IdGenerator
+	default:
+		return nil
+	}
+	pkgPath := config.PackagePath
+	return map[string]string{
+		filepath.Join("domain/src/main/java", pkgPath, "domain/service/IdGenerator.java"): templates.IdGenerator,
+		filepath.Join("infrastructure/src/main/java", pkgPath, "infrastructure/id", impl): template,
+	}
+}
+
+// detectIDStrategy reports the ID strategy of an existing project from its
+// ID generator
+func detectIDStrategy(config *ProjectConfig) string {
+	dir := filepath.Join(config.OutputDir, "infrastructure/src/main/java", config.PackagePath, "infrastructure/id")
+	for strategy, file := range map[string]string{
+		IDStrategySnowflake: "SnowflakeIdGenerator.java",
+		IDStrategyUUIDv7:    "UuidV7IdGenerator.java",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err == nil {
+			return strategy
+		}
+	}
+	return IDStrategyAuto
+}
+
+// idReplacements returns the template placeholders of the ID strategy. The
+// blocks are empty for database-assigned keys.
+func (c *ProjectConfig) idReplacements() map[string]string {
+	idType := c.userIDType()
+	replacements := map[string]string{
+		"{{USER_ID_TYPE}}":            idType,
+		"{{MP_ID_TYPE}}":              "AUTO",
+		"{{JPA_ID_GENERATION}}":       "    @GeneratedValue(strategy = GenerationType.IDENTITY)\n",
+		"{{JDBC_ID_GETTER}}":          "getLong",
+		"{{JDBC_KEY_HOLDER_IMPORTS}}": "import org.springframework.jdbc.support.GeneratedKeyHolder;\nimport org.springframework.jdbc.support.KeyHolder;\n",
+		"{{JDBC_USER_INSERT}}": `    /**
+     * 插入用户，返回生成的主键
+     */
+    public Long insert(UserDO userDO) {
+        KeyHolder keyHolder = new GeneratedKeyHolder();
+        jdbcClient.sql("INSERT INTO t_user (username, email, phone, status) VALUES (:username, :email, :phone, :status)")
+                .param("username", userDO.getUsername())
+                .param("email", userDO.getEmail())
+                .param("phone", userDO.getPhone())
+                .param("status", userDO.getStatus())
+                .update(keyHolder, "id");
+        return keyHolder.getKey().longValue();
+    }
+
+`,
+		"{{ID_JSON_FORMAT}}":     "",
+		"{{USER_FACTORY}}":       "",
+		"{{ID_GENERATOR_FIELD}}": "",
+		"{{USER_CREATION}}": `        // 步骤2: 创建用户实体
+        User user = new User();
+        user.setUsername(username);
+        user.setEmail(email);
+        user.setPhone(phone);
+        user.enable(); // 使用领域方法设置状态
+`,
+		"{{ID_YML}}":        "",
+		"{{ID_TECH_STACK}}": "",
+	}
+	strategy := c.idStrategy()
+	if strategy == IDStrategyAuto {
+		return replacements
+	}
+
+	if idType == "String" {
+		replacements["{{JDBC_ID_GETTER}}"] = "getString"
+	}
+	// The domain assigns the key, the persistence layer only writes it
+	replacements["{{MP_ID_TYPE}}"] = "INPUT"
+	replacements["{{JPA_ID_GENERATION}}"] = ""
+	replacements["{{JDBC_KEY_HOLDER_IMPORTS}}"] = ""
+	replacements["{{JDBC_USER_INSERT}}"] = fmt.Sprintf(`    /**
+     * 插入用户，主键由 IdGenerator 预先分配
+     */
+    public %s insert(UserDO userDO) {
+        jdbcClient.sql("INSERT INTO t_user (id, username, email, phone, status) VALUES (:id, :username, :email, :phone, :status)")
+                .param("id", userDO.getId())
+                .param("username", userDO.getUsername())
+                .param("email", userDO.getEmail())
+                .param("phone", userDO.getPhone())
+                .param("status", userDO.getStatus())
+                .update();
+        return userDO.getId();
+    }
+
+`, idType)
+	replacements["{{USER_FACTORY}}"] = fmt.Sprintf(`
+    /**
+     * 创建启用状态的新用户，ID 由 IdGenerator 在持久化之前分配
+     */
+    public static User create(%s id, String username, String email, String phone) {
+        User user = new User();
+        user.setId(id);
+        user.setUsername(username);
+        user.setEmail(email);
+        user.setPhone(phone);
+        user.enable();
+        return user;
+    }
+`, idType)
+	replacements["{{ID_GENERATOR_FIELD}}"] = "    private final IdGenerator idGenerator;\n"
+	replacements["{{USER_CREATION}}"] = `        // 步骤2: 创建用户实体，ID 在插入之前分配
+        User user = User.create(idGenerator.nextId(), username, email, phone);
+`
+
+	if strategy == IDStrategySnowflake {
+		// Snowflake IDs exceed Number.MAX_SAFE_INTEGER of JavaScript clients
+		replacements["{{ID_JSON_FORMAT}}"] = `
+    /**
+     * 雪花算法ID超出 JavaScript 的安全整数范围，以字符串返回
+     */
+    @JsonFormat(shape = JsonFormat.Shape.STRING)`
+		// TENANT_YML opens the app section when both are enabled
+		header := "\napp:\n"
+		if c.MultiTenant {
+			header = ""
+		}
+		replacements["{{ID_YML}}"] = header + `  id:
+    # 雪花算法的机器号（0-1023），同时运行的每个实例必须不同，部署时通过环境变量设置
+    worker-id: ${SNOWFLAKE_WORKER_ID:0}
+`
+		replacements["{{ID_TECH_STACK}}"] = "- 主键：雪花算法（Snowflake），插入前由应用分配\n"
+	} else {
+		replacements["{{ID_TECH_STACK}}"] = "- 主键：UUIDv7，插入前由应用分配\n"
+	}
+	return replacements
+}
@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// The helpers in this file perform small, additive edits on existing Java
+// sources. They never rewrite code they did not insert, so classes that users
+// have already changed by hand keep their content.
+
+var (
+	javaPackageRe = regexp.MustCompile(`(?m)^package\s+[\w.]+\s*;[ \t]*\n`)
+	javaImportRe  = regexp.MustCompile(`(?m)^import\s+(static\s+)?[\w.*]+\s*;[ \t]*\n`)
+	javaClassRe   = regexp.MustCompile(`(?m)^(public\s+)?(final\s+|abstract\s+)*(class|interface|enum|record)\s+\w+[^{]*\{[ \t]*\n`)
+	javaFieldRe   = regexp.MustCompile(`(?m)^    private\s+(final\s+)?[\w<>,.\s?]+\s+\w+\s*(=[^;]*)?;[ \t]*\n`)
+
+	javaPrivateMethodRe = regexp.MustCompile(`(?m)^    private [\w<>,.\[\] ]+ \w+\(`)
+	// javaDocBeforeRe matches a member's Javadoc ending right before it
+	javaDocBeforeRe = regexp.MustCompile(`    /\*\*(?:[^*]|\*[^/])*\*/\n$`)
+)
+
+// hasJavaImport reports whether the source imports fqcn, either directly or
+// through a wildcard import of its package
+func hasJavaImport(src, fqcn string) bool {
+	if strings.Contains(src, "import "+fqcn+";") {
+		return true
+	}
+	if idx := strings.LastIndex(fqcn, "."); idx != -1 {
+		return strings.Contains(src, "import "+fqcn[:idx]+".*;")
+	}
+	return false
+}
+
+// addJavaImport adds an import statement after the last existing import,
+// or after the package declaration when the file has no imports yet
+func addJavaImport(src, fqcn string) string {
+	if hasJavaImport(src, fqcn) {
+		return src
+	}
+	line := fmt.Sprintf("import %s;\n", fqcn)
+
+	if locs := javaImportRe.FindAllStringIndex(src, -1); len(locs) > 0 {
+		end := locs[len(locs)-1][1]
+		return src[:end] + line + src[end:]
+	}
+	if loc := javaPackageRe.FindStringIndex(src); loc != nil {
+		return src[:loc[1]] + "\n" + line + src[loc[1]:]
+	}
+	return line + src
+}
+
+// hasJavaMethod reports whether a method with the given name is declared
+func hasJavaMethod(src, name string) bool {
+	re := regexp.MustCompile(`(?m)^\s*(public|protected|private|default|static|\s)*[\w<>\[\],.?\s]+\s` + regexp.QuoteMeta(name) + `\s*\(`)
+	return re.MatchString(src)
+}
+
+// hasJavaField reports whether a field with the given name is declared
+func hasJavaField(src, name string) bool {
+	re := regexp.MustCompile(`(?m)^\s*(private|protected|public)[^;(=]*\s` + regexp.QuoteMeta(name) + `\s*[;=]`)
+	return re.MatchString(src)
+}
+
+// addJavaField inserts a field declaration after the last private field of
+// the top-level class, or directly after the class opening brace
+func addJavaField(src, decl string) string {
+	line := "    " + strings.TrimSpace(decl) + "\n"
+
+	classLoc := javaClassRe.FindStringIndex(src)
+	if classLoc == nil {
+		return src
+	}
+	if locs := javaFieldRe.FindAllStringIndex(src[classLoc[1]:], -1); len(locs) > 0 {
+		end := classLoc[1] + locs[len(locs)-1][1]
+		return src[:end] + line + src[end:]
+	}
+	return src[:classLoc[1]] + "\n" + line + src[classLoc[1]:]
+}
+
+// appendJavaMethod inserts a method body before the closing brace of the
+// top-level class
+func appendJavaMethod(src, method string) string {
+	end := strings.LastIndex(src, "}")
+	if end == -1 {
+		return src
+	}
+	body := strings.TrimRight(src[:end], " \t\n")
+	return body + "\n\n" + strings.TrimRight(method, "\n") + "\n" + src[end:]
+}
+
+// insertBeforePrivateMethods inserts a method before the first private
+// method of the top-level class, together with that method's Javadoc, so
+// that public methods stay ahead of the private helpers
+func insertBeforePrivateMethods(src, method string) string {
+	loc := javaPrivateMethodRe.FindStringIndex(src)
+	if loc == nil {
+		return appendJavaMethod(src, method)
+	}
+	at := loc[0]
+	if m := javaDocBeforeRe.FindStringIndex(src[:at]); m != nil {
+		at = m[0]
+	}
+	return src[:at] + strings.TrimRight(method, "\n") + "\n\n" + src[at:]
+}
+
+// ensureClassAnnotation adds an annotation to the top-level class declaration
+// when it is not present yet
+func ensureClassAnnotation(src, annotation, fqcn string) string {
+	classLoc := javaClassRe.FindStringIndex(src)
+	if classLoc == nil {
+		return src
+	}
+	header := src[:classLoc[0]]
+	if regexp.MustCompile(`(?m)^@` + regexp.QuoteMeta(annotation) + `\b`).MatchString(header) {
+		return src
+	}
+	src = src[:classLoc[0]] + "@" + annotation + "\n" + src[classLoc[0]:]
+	return addJavaImport(src, fqcn)
+}
+
+// classRequestMapping returns the path of a class-level @RequestMapping, if any
+func classRequestMapping(src string) string {
+	classLoc := javaClassRe.FindStringIndex(src)
+	if classLoc == nil {
+		return ""
+	}
+	re := regexp.MustCompile(`@RequestMapping\(\s*(value\s*=\s*)?"([^"]*)"`)
+	if m := re.FindStringSubmatch(src[:classLoc[0]]); m != nil {
+		return m[2]
+	}
+	return ""
+}
+
+// lowerFirst lower-cases the first letter of a Java identifier
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+var mapperScanRe = regexp.MustCompile(`@MapperScan\(\s*(\{[^}]*\}|"[^"]*")\s*\)`)
+
+// addMapperScanPackage adds a package to the @MapperScan annotation, turning
+// a single value into an array when needed. It returns false when the
+// annotation is missing or uses a form it cannot extend.
+func addMapperScanPackage(src, pkg string) (string, bool) {
+	loc := mapperScanRe.FindStringSubmatchIndex(src)
+	if loc == nil {
+		return src, false
+	}
+
+	value := src[loc[2]:loc[3]]
+	if strings.Contains(value, `"`+pkg+`"`) {
+		return src, true
+	}
+
+	var updated string
+	if strings.HasPrefix(value, "{") {
+		inner := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(value, "{"), "}"))
+		if inner == "" {
+			updated = fmt.Sprintf(`{"%s"}`, pkg)
+		} else {
+			updated = fmt.Sprintf(`{%s, "%s"}`, inner, pkg)
+		}
+	} else {
+		updated = fmt.Sprintf(`{%s, "%s"}`, value, pkg)
+	}
+
+	return src[:loc[2]] + updated + src[loc[3]:], true
+}
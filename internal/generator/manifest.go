@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/schema"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// manifestFile is the project manifest, relative to the project root
+const manifestFile = "phjvgen.json"
+
+// projectManifest records what phjvgen keeps about a project beyond its
+// build files
+type projectManifest struct {
+	// Entities are the tables of the scaffolded entities as they should be.
+	// They are edited by hand when an entity gains or loses a field.
+	Entities []schema.Table `json:"entities"`
+	// SchemaSnapshot is the schema the migration scripts create so far
+	SchemaSnapshot []schema.Table `json:"schemaSnapshot"`
+}
+
+// readManifest reads the manifest of a project
+func readManifest(projectRoot string) (*projectManifest, error) {
+	content, err := os.ReadFile(filepath.Join(projectRoot, manifestFile))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("未找到 %s，该项目可能由旧版本 phjvgen 生成", manifestFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m projectManifest
+	if err := json.Unmarshal(content, &m); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", manifestFile, err)
+	}
+	return &m, nil
+}
+
+// writeManifest writes the manifest of a project
+func writeManifest(projectRoot string, m *projectManifest) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utils.WriteFile(filepath.Join(projectRoot, manifestFile), string(content)+"\n")
+}
+
+// recordManifestTable adds a table created by a generated migration to both
+// the entities and the schema snapshot, creating the manifest if needed
+func recordManifestTable(projectRoot string, table schema.Table) error {
+	m := &projectManifest{}
+	if _, err := os.Stat(filepath.Join(projectRoot, manifestFile)); err == nil {
+		if m, err = readManifest(projectRoot); err != nil {
+			return err
+		}
+	}
+	m.Entities = putTable(m.Entities, table)
+	m.SchemaSnapshot = putTable(m.SchemaSnapshot, table)
+	return writeManifest(projectRoot, m)
+}
+
+// putTable replaces the table of the same name or appends the table
+func putTable(tables []schema.Table, table schema.Table) []schema.Table {
+	for i, t := range tables {
+		if t.Name == table.Name {
+			tables[i] = table
+			return tables
+		}
+	}
+	return append(tables, table)
+}
+
+// validateEntities checks the hand-edited entity definitions before they are
+// turned into DDL
+func validateEntities(tables []schema.Table) error {
+	seen := map[string]bool{}
+	tableNames := map[string]bool{}
+	for _, t := range tables {
+		tableNames[t.Name] = true
+	}
+	for _, t := range tables {
+		if t.Name == "" {
+			return fmt.Errorf("%s 中存在未命名的表", manifestFile)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("%s 中表 %s 重复定义", manifestFile, t.Name)
+		}
+		seen[t.Name] = true
+		if len(t.Columns) == 0 {
+			return fmt.Errorf("表 %s 没有列", t.Name)
+		}
+
+		columns := map[string]bool{}
+		for _, c := range t.Columns {
+			if c.Name == "" {
+				return fmt.Errorf("表 %s 中存在未命名的列", t.Name)
+			}
+			if columns[c.Name] {
+				return fmt.Errorf("表 %s 中列 %s 重复定义", t.Name, c.Name)
+			}
+			columns[c.Name] = true
+			if (c.Type == schema.Varchar || c.Type == schema.Decimal) && c.Length <= 0 {
+				return fmt.Errorf("表 %s 的列 %s 缺少 length", t.Name, c.Name)
+			}
+			if table, _, _ := strings.Cut(c.References, "."); c.References != "" && !tableNames[table] {
+				return fmt.Errorf("表 %s 的列 %s 引用了不存在的表 %s", t.Name, c.Name, table)
+			}
+		}
+		for _, name := range t.PrimaryKey {
+			if !columns[name] {
+				return fmt.Errorf("表 %s 的主键引用了不存在的列 %s", t.Name, name)
+			}
+		}
+		indexes := map[string]bool{}
+		for _, idx := range t.Indexes {
+			if idx.Name == "" || len(idx.Columns) == 0 {
+				return fmt.Errorf("表 %s 中存在未命名或没有列的索引", t.Name)
+			}
+			if indexes[idx.Name] {
+				return fmt.Errorf("表 %s 中索引 %s 重复定义", t.Name, idx.Name)
+			}
+			indexes[idx.Name] = true
+			for _, name := range idx.Columns {
+				if !columns[name] {
+					return fmt.Errorf("表 %s 的索引 %s 引用了不存在的列 %s", t.Name, idx.Name, name)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,651 @@
+package generator
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/phixia/phjvgen/internal/catalog"
+	"github.com/phixia/phjvgen/internal/gradle"
+	"github.com/phixia/phjvgen/internal/schema"
+	"github.com/phixia/phjvgen/internal/templates"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// Database migration tools
+const (
+	MigrationFlyway    = "flyway"
+	MigrationLiquibase = "liquibase"
+	MigrationNone      = "none"
+)
+
+// DefaultMigration is the migration tool of a new project
+const DefaultMigration = MigrationFlyway
+
+// Locations of the migration scripts and their bookkeeping, relative to the project root
+const (
+	migrationDir       = "infrastructure/src/main/resources/db/migration"
+	liquibaseChangelog = "infrastructure/src/main/resources/db/changelog/db.changelog-master.yaml"
+	// migrationSumFile records the checksums of the scripts considered
+	// applied, like go.sum does for modules
+	migrationSumFile = "infrastructure/migration.sum"
+)
+
+// liquibaseAuthor is the changeset author of the generated scripts
+const liquibaseAuthor = "phjvgen"
+
+// migrationFileRe matches versioned scripts, e.g. V2__add_order_table.sql
+var migrationFileRe = regexp.MustCompile(`^V(\d+(?:[._]\d+)*)__(.+)\.sql$`)
+
+// migrationScript is a versioned migration script
+type migrationScript struct {
+	name    string
+	version []int
+}
+
+// ValidateMigration checks the value of --migration
+func ValidateMigration(tool string) error {
+	if tool != MigrationFlyway && tool != MigrationLiquibase && tool != MigrationNone {
+		return fmt.Errorf("不支持的迁移工具: %s（可选: %s, %s, %s）", tool, MigrationFlyway, MigrationLiquibase, MigrationNone)
+	}
+	return nil
+}
+
+// migration returns the project's migration tool
+func (c *ProjectConfig) migration() string {
+	if ValidateMigration(c.Migration) != nil {
+		return DefaultMigration
+	}
+	return c.Migration
+}
+
+// migrationDisplayName returns the name of the migration tool shown to users
+func migrationDisplayName(tool string) string {
+	switch tool {
+	case MigrationFlyway:
+		return "Flyway"
+	case MigrationLiquibase:
+		return "Liquibase"
+	}
+	return ""
+}
+
+// migrationDependencies returns the dependencies running the migration
+// scripts: the tool's starter and, for Flyway, its database support
+func (c *ProjectConfig) migrationDependencies() []catalog.Entry {
+	var names []string
+	switch c.migration() {
+	case MigrationFlyway:
+		names = append(names, "flyway")
+		if db := c.database(); db.flyway != "" {
+			names = append(names, db.flyway)
+		}
+	case MigrationLiquibase:
+		names = append(names, "liquibase")
+	}
+
+	var entries []catalog.Entry
+	for _, name := range names {
+		entry, _ := catalog.Lookup(name)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// migrationReplacements returns the template placeholders of the project's
+// migration tool. Like the persistence blocks, each block carries its own
+// line breaks and is empty without a migration tool.
+func (c *ProjectConfig) migrationReplacements() map[string]string {
+	var maven, gradleDeps, libraries strings.Builder
+	for _, entry := range c.migrationDependencies() {
+		fmt.Fprintf(&maven, "        <dependency>\n            <groupId>%s</groupId>\n            <artifactId>%s</artifactId>\n", entry.GroupID, entry.ArtifactID)
+		if entry.Scope != "" {
+			fmt.Fprintf(&maven, "            <scope>%s</scope>\n", entry.Scope)
+		}
+		maven.WriteString("        </dependency>\n")
+		fmt.Fprintf(&gradleDeps, "    %s(%s)\n", gradleConfiguration(entry.Scope, false), gradle.Library{Alias: entry.ArtifactID}.Accessor())
+		fmt.Fprintf(&libraries, "%s = { module = %q }\n", entry.ArtifactID, entry.Coordinates())
+	}
+
+	replacements := map[string]string{
+		"{{MIGRATION_DEPENDENCIES}}":        maven.String(),
+		"{{MIGRATION_GRADLE_DEPENDENCIES}}": gradleDeps.String(),
+		"{{MIGRATION_CATALOG_LIBRARIES}}":   "",
+		"{{MIGRATION_SPRING_YML}}":          "",
+		"{{MIGRATION_TECH_STACK}}":          "",
+	}
+	tool := c.migration()
+	if tool == MigrationNone {
+		return replacements
+	}
+	replacements["{{MIGRATION_CATALOG_LIBRARIES}}"] = fmt.Sprintf("# %s（版本由 BOM 管理）\n%s\n", migrationDisplayName(tool), libraries.String())
+	replacements["{{MIGRATION_TECH_STACK}}"] = fmt.Sprintf("- %s（启动时执行 db/migration 下的脚本）\n", migrationDisplayName(tool))
+	if tool == MigrationFlyway {
+		replacements["{{MIGRATION_SPRING_YML}}"] = `  flyway:
+    locations: classpath:db/migration
+`
+	} else {
+		replacements["{{MIGRATION_SPRING_YML}}"] = `  liquibase:
+    change-log: classpath:db/changelog/db.changelog-master.yaml
+`
+	}
+	return replacements
+}
+
+// migrationScriptContent prefixes a script with the Liquibase formatted SQL
+// header when the project uses Liquibase
+func migrationScriptContent(config *ProjectConfig, version, body string) string {
+	if config.migration() != MigrationLiquibase {
+		return body
+	}
+	changeset := fmt.Sprintf("--changeset %s:%s", liquibaseAuthor, version)
+	if config.database().name == schema.PostgreSQL {
+		// Function bodies contain semicolons, the PostgreSQL driver runs the
+		// statements of a changeset itself
+		changeset += " splitStatements:false"
+	}
+	return "--liquibase formatted sql\n\n" + changeset + "\n" + body
+}
+
+// generateMigrationConfig writes the Liquibase master changelog including
+// the first migration script
+func generateMigrationConfig(config *ProjectConfig) error {
+	if config.migration() != MigrationLiquibase {
+		return nil
+	}
+	return utils.WriteFile(filepath.Join(config.OutputDir, liquibaseChangelog), templates.LiquibaseChangelog)
+}
+
+// detectMigration finds the migration tool of an existing project from the
+// starters its modules depend on
+func detectMigration(config *ProjectConfig) string {
+	modules, err := listProjectModules(config)
+	if err != nil {
+		return MigrationNone
+	}
+	for _, tool := range []string{MigrationFlyway, MigrationLiquibase} {
+		entry, _ := catalog.Lookup(tool)
+		for _, m := range modules {
+			if moduleUsesDependency(config, m.path, entry) {
+				return tool
+			}
+		}
+	}
+	return MigrationNone
+}
+
+// NewMigration creates the next empty versioned migration script
+func NewMigration(description string) error {
+	slug := migrationSlug(description)
+	if slug == "" {
+		return fmt.Errorf("迁移描述不能为空")
+	}
+
+	projectRoot, config, err := migrationProject()
+	if err != nil {
+		return err
+	}
+	if _, err := createMigration(projectRoot, config, description, ""); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if tool := config.migration(); tool == MigrationNone {
+		utils.PrintWarning("项目未使用 Flyway 或 Liquibase，需要手动执行该脚本")
+	} else {
+		utils.PrintInfo(fmt.Sprintf("编写 SQL 后启动应用，%s 会自动执行该脚本", migrationDisplayName(tool)))
+	}
+	return nil
+}
+
+// DiffMigration compares the entities of the project manifest with its
+// schema snapshot and writes the changes as the next migration script.
+// Changes that may lose data are refused unless allowDestructive is set.
+func DiffMigration(description string, allowDestructive bool) error {
+	projectRoot, config, err := migrationProject()
+	if err != nil {
+		return err
+	}
+	manifest, err := readManifest(projectRoot)
+	if err != nil {
+		return err
+	}
+	if err := validateEntities(manifest.Entities); err != nil {
+		return err
+	}
+
+	changes := schema.Diff(manifest.SchemaSnapshot, manifest.Entities)
+	if len(changes) == 0 {
+		utils.PrintSuccess(fmt.Sprintf("%s 中的实体与快照一致，无需迁移", manifestFile))
+		return nil
+	}
+
+	destructive := 0
+	utils.PrintInfo("检测到以下表结构变更：")
+	for _, c := range changes {
+		if c.Destructive() {
+			destructive++
+			fmt.Printf("  ⚠️  %s（可能丢失数据）\n", describeSchemaChange(c))
+		} else {
+			fmt.Printf("  +  %s\n", describeSchemaChange(c))
+		}
+	}
+	fmt.Println()
+	if destructive > 0 && !allowDestructive {
+		return fmt.Errorf("包含 %d 项破坏性变更，确认数据可以丢弃后使用 --allow-destructive 重新执行", destructive)
+	}
+
+	if description == "" {
+		description = schemaChangeDescription(changes)
+	} else if migrationSlug(description) == "" {
+		return fmt.Errorf("迁移描述不能为空")
+	}
+	statements := make([]string, len(changes))
+	for i, c := range changes {
+		statements[i] = config.dialect().Migrate(c)
+	}
+	header := fmt.Sprintf("-- 由 phjvgen migration diff 根据 %s 生成\n", manifestFile)
+	if _, err := createMigration(projectRoot, config, description, header+"\n"+strings.Join(statements, "\n")); err != nil {
+		return err
+	}
+
+	manifest.SchemaSnapshot = manifest.Entities
+	if err := writeManifest(projectRoot, manifest); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("已更新 %s 中的表结构快照", manifestFile))
+
+	fmt.Println()
+	utils.PrintInfo("请同步修改对应的 DO 类，并检查生成的 SQL 后再提交")
+	if tool := config.migration(); tool == MigrationNone {
+		utils.PrintWarning("项目未使用 Flyway 或 Liquibase，需要手动执行该脚本")
+	}
+	return nil
+}
+
+// MarkMigrationsApplied records the checksums of scripts that have been
+// applied to a shared database in migration.sum, so that later edits to
+// them are refused. Without names, every script is recorded. Scripts
+// without SQL are skipped: they cannot have been applied meaningfully yet.
+func MarkMigrationsApplied(names []string) error {
+	projectRoot, _, err := migrationProject()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(projectRoot, migrationDir)
+	scripts, err := readMigrationScripts(dir)
+	if err != nil {
+		return err
+	}
+	if len(scripts) == 0 {
+		return fmt.Errorf("%s 中没有迁移脚本", migrationDir)
+	}
+
+	sumPath := filepath.Join(projectRoot, migrationSumFile)
+	sums, err := readMigrationSums(sumPath)
+	if err != nil {
+		return err
+	}
+	edited, err := checkMigrationSums(dir, sums)
+	if err != nil {
+		return err
+	}
+	if len(edited) > 0 {
+		for _, e := range edited {
+			utils.PrintWarning(e)
+		}
+		return fmt.Errorf("已应用的迁移脚本被修改，请先撤销修改；若脚本确未在任何环境执行过，可删除 %s 中对应的行", migrationSumFile)
+	}
+
+	targets := scripts
+	if len(names) > 0 {
+		targets = nil
+		for _, name := range names {
+			script, ok := findMigrationScript(scripts, name)
+			if !ok {
+				return fmt.Errorf("未找到迁移脚本 %s", name)
+			}
+			targets = append(targets, script)
+		}
+	}
+
+	marked := 0
+	for _, s := range targets {
+		if _, ok := sums[s.name]; ok {
+			continue
+		}
+		path := filepath.Join(dir, s.name)
+		ok, err := hasMigrationStatements(path)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			utils.PrintWarning(fmt.Sprintf("%s 还没有 SQL 语句，跳过", s.name))
+			continue
+		}
+		if sums[s.name], err = migrationChecksum(path); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("已将 %s 记录为已应用", s.name))
+		marked++
+	}
+	if marked == 0 {
+		utils.PrintInfo("没有需要记录的脚本")
+		return nil
+	}
+	if err := writeMigrationSums(sumPath, scripts, sums); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	utils.PrintInfo(fmt.Sprintf("请将 %s 提交到版本库", migrationSumFile))
+	return nil
+}
+
+// findMigrationScript finds a script by file name or version, e.g.
+// V2__add_order_table.sql, V2 or 2
+func findMigrationScript(scripts []migrationScript, name string) (migrationScript, bool) {
+	version := strings.TrimPrefix(strings.TrimPrefix(name, "V"), "v")
+	for _, s := range scripts {
+		if s.name == name || strings.HasPrefix(s.name, "V"+version+"__") {
+			return s, true
+		}
+	}
+	return migrationScript{}, false
+}
+
+// describeSchemaChange describes a schema change to users
+func describeSchemaChange(c schema.Change) string {
+	switch c.Kind {
+	case schema.CreateTable:
+		return "新建表 " + c.Table.Name
+	case schema.DropTable:
+		return "删除表 " + c.Table.Name
+	case schema.AddColumn:
+		return fmt.Sprintf("%s 新增列 %s", c.Table.Name, c.Column.Name)
+	case schema.DropColumn:
+		return fmt.Sprintf("%s 删除列 %s", c.Table.Name, c.Column.Name)
+	case schema.ModifyColumn:
+		return fmt.Sprintf("%s 修改列 %s", c.Table.Name, c.Column.Name)
+	}
+	kind := "索引"
+	if c.Index.Unique {
+		kind = "唯一键"
+	}
+	if c.Kind == schema.AddIndex {
+		return fmt.Sprintf("%s 新增%s %s", c.Table.Name, kind, c.Index.Name)
+	}
+	return fmt.Sprintf("%s 删除%s %s", c.Table.Name, kind, c.Index.Name)
+}
+
+// schemaChangeDescription names a diff migration after the table it
+// changes, e.g. alter t_user
+func schemaChangeDescription(changes []schema.Change) string {
+	table := changes[0].Table.Name
+	for _, c := range changes {
+		if c.Table.Name != table {
+			return "update schema"
+		}
+	}
+	switch changes[0].Kind {
+	case schema.CreateTable:
+		return "create " + table
+	case schema.DropTable:
+		return "drop " + table
+	}
+	return "alter " + table
+}
+
+// migrationProject finds the root and configuration of the current project
+func migrationProject() (string, *ProjectConfig, error) {
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return "", nil, fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml或settings.gradle.kts的项目目录或其子目录中运行此命令", err)
+	}
+	config, err := extractProjectInfo(projectRoot)
+	if err != nil {
+		return "", nil, err
+	}
+	return projectRoot, config, nil
+}
+
+// createMigration writes the next versioned migration script with the given
+// SQL and returns its name. It refuses to do so while two scripts share a
+// version or a script recorded in migration.sum has been edited, since
+// Flyway and Liquibase would reject the migration at startup. Scripts are
+// recorded in migration.sum only by MarkMigrationsApplied.
+func createMigration(projectRoot string, config *ProjectConfig, description, body string) (string, error) {
+	dir := filepath.Join(projectRoot, migrationDir)
+	scripts, err := readMigrationScripts(dir)
+	if err != nil {
+		return "", err
+	}
+	if collisions := migrationCollisions(scripts); len(collisions) > 0 {
+		for _, c := range collisions {
+			utils.PrintWarning(c)
+		}
+		return "", fmt.Errorf("迁移版本冲突，请先重新编号")
+	}
+
+	sumPath := filepath.Join(projectRoot, migrationSumFile)
+	sums, err := readMigrationSums(sumPath)
+	if err != nil {
+		return "", err
+	}
+	edited, err := checkMigrationSums(dir, sums)
+	if err != nil {
+		return "", err
+	}
+	if len(edited) > 0 {
+		for _, e := range edited {
+			utils.PrintWarning(e)
+		}
+		return "", fmt.Errorf("已应用的迁移脚本被修改，校验和将不一致。请撤销修改并新建迁移；若脚本确未在任何环境执行过，可删除 %s 中对应的行", migrationSumFile)
+	}
+
+	version := "1"
+	if len(scripts) > 0 {
+		version = strconv.Itoa(scripts[len(scripts)-1].version[0] + 1)
+	}
+	name := fmt.Sprintf("V%s__%s.sql", version, migrationSlug(description))
+	header := fmt.Sprintf("-- %s\n-- 创建时间: %s\n\n", description, time.Now().Format("2006-01-02 15:04:05"))
+	if err := utils.WriteFile(filepath.Join(dir, name), migrationScriptContent(config, version, header+body)); err != nil {
+		return "", err
+	}
+	utils.PrintSuccess(fmt.Sprintf("已创建 %s", filepath.ToSlash(filepath.Join(migrationDir, name))))
+
+	if config.migration() == MigrationLiquibase {
+		if err := includeInChangelog(filepath.Join(projectRoot, liquibaseChangelog), "db/migration/"+name); err != nil {
+			return "", err
+		}
+		utils.PrintSuccess(fmt.Sprintf("已将 %s 加入 %s", name, filepath.Base(liquibaseChangelog)))
+	}
+	return name, nil
+}
+
+// migrationSlug turns a description into the description part of a script
+// name, e.g. "add order table" into add_order_table
+func migrationSlug(description string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(strings.TrimSpace(description)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if underscore && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			underscore = false
+			continue
+		}
+		underscore = true
+	}
+	return b.String()
+}
+
+// readMigrationScripts returns the versioned scripts of the migration
+// directory ordered by version
+func readMigrationScripts(dir string) ([]migrationScript, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var scripts []migrationScript
+	for _, e := range entries {
+		m := migrationFileRe.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		var version []int
+		for _, part := range strings.FieldsFunc(m[1], func(r rune) bool { return r == '.' || r == '_' }) {
+			n, _ := strconv.Atoi(part)
+			version = append(version, n)
+		}
+		scripts = append(scripts, migrationScript{name: e.Name(), version: version})
+	}
+	sort.SliceStable(scripts, func(i, j int) bool {
+		return compareMigrationVersions(scripts[i].version, scripts[j].version) < 0
+	})
+	return scripts, nil
+}
+
+// compareMigrationVersions compares versions part by part, so that 1.0 equals 1
+func compareMigrationVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+// migrationCollisions describes the scripts sharing a version, e.g. two
+// V3 scripts created on different branches
+func migrationCollisions(scripts []migrationScript) []string {
+	var collisions []string
+	for i := 1; i < len(scripts); i++ {
+		if compareMigrationVersions(scripts[i-1].version, scripts[i].version) == 0 {
+			collisions = append(collisions, fmt.Sprintf("%s 与 %s 版本相同", scripts[i-1].name, scripts[i].name))
+		}
+	}
+	return collisions
+}
+
+// readMigrationSums reads migration.sum, which maps script names to checksums
+func readMigrationSums(path string) (map[string]string, error) {
+	sums := map[string]string{}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return sums, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			sums[fields[1]] = fields[0]
+		}
+	}
+	return sums, scanner.Err()
+}
+
+// checkMigrationSums describes the recorded scripts that were edited or deleted
+func checkMigrationSums(dir string, sums map[string]string) ([]string, error) {
+	names := make([]string, 0, len(sums))
+	for name := range sums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		sum, err := migrationChecksum(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			problems = append(problems, fmt.Sprintf("%s 已应用但被删除", name))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if sum != sums[name] {
+			problems = append(problems, fmt.Sprintf("%s 已应用但内容被修改", name))
+		}
+	}
+	return problems, nil
+}
+
+// writeMigrationSums writes the recorded checksums in version order
+func writeMigrationSums(path string, scripts []migrationScript, sums map[string]string) error {
+	var b strings.Builder
+	for _, s := range scripts {
+		if sum, ok := sums[s.name]; ok {
+			fmt.Fprintf(&b, "%s  %s\n", sum, s.name)
+		}
+	}
+	return utils.WriteFile(path, b.String())
+}
+
+// hasMigrationStatements reports whether a script contains SQL besides the
+// comments of its generated header
+func hasMigrationStatements(path string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// migrationChecksum hashes a script with line endings normalized, so that a
+// checkout with CRLF line endings is not reported as an edit
+func migrationChecksum(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(strings.ReplaceAll(string(content), "\r\n", "\n")))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// includeInChangelog appends an include of a script to the Liquibase master changelog
+func includeInChangelog(path, file string) error {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		content = []byte("databaseChangeLog:\n")
+	} else if err != nil {
+		return err
+	}
+	text := string(content)
+	if strings.Contains(text, "file: "+file+"\n") {
+		return nil
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	text += fmt.Sprintf("  - include:\n      file: %s\n", file)
+	return utils.WriteFile(path, text)
+}
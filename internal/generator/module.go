@@ -2,32 +2,49 @@ package generator
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
+	"github.com/phixia/phjvgen/internal/pom"
 	"github.com/phixia/phjvgen/internal/templates"
 	"github.com/phixia/phjvgen/internal/utils"
 )
 
+// AddModuleOptions holds the optional scaffolds for a new application module
+type AddModuleOptions struct {
+	// Wire adds the new module as a dependency of adapter-rest
+	Wire bool
+	// WithController generates a sample controller in adapter-rest
+	WithController bool
+	// WithListener generates a sample event listener
+	WithListener bool
+	// WithExecutor generates a sample executor the service delegates to
+	WithExecutor bool
+}
+
 // AddApplicationModule adds a new application module
-func AddApplicationModule(moduleName string) error {
+func AddApplicationModule(moduleName string, opts AddModuleOptions) error {
 	// Validate module name
 	if !validateModuleName(moduleName) {
 		return fmt.Errorf("模块名称格式不正确，请使用小写字母和连字符")
 	}
 
+	// A controller lives in adapter-rest, so it needs the dependency
+	if opts.WithController {
+		opts.Wire = true
+	}
+
 	// Find project root
 	projectRoot, err := findProjectRoot()
 	if err != nil {
-		return fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml的项目目录或其子目录中运行此命令", err)
+		return fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml或settings.gradle.kts的项目目录或其子目录中运行此命令", err)
 	}
 
 	utils.PrintInfo(fmt.Sprintf("项目根目录: %s", projectRoot))
 
 	// Extract project info
-	config, err := extractProjectInfoFromPOM(projectRoot)
+	config, err := extractProjectInfo(projectRoot)
 	if err != nil {
 		return err
 	}
@@ -43,8 +60,25 @@ func AddApplicationModule(moduleName string) error {
 		return fmt.Errorf("模块 application-%s 已存在", moduleName)
 	}
 
+	adapterRestBuild := filepath.Join("adapter", "adapter-rest", config.buildFileName())
+	if opts.Wire && !utils.FileExists(filepath.Join(projectRoot, adapterRestBuild)) {
+		return fmt.Errorf("未找到 %s，无法接入 adapter-rest", filepath.ToSlash(adapterRestBuild))
+	}
+
 	fmt.Println()
 	utils.PrintInfo(fmt.Sprintf("准备创建模块: application-%s", moduleName))
+	if opts.Wire {
+		fmt.Println("  - 接入 adapter-rest")
+	}
+	if opts.WithController {
+		fmt.Printf("  - 生成 %sController\n", toCamelCase(moduleName))
+	}
+	if opts.WithListener {
+		fmt.Printf("  - 生成 %sEventListener\n", toCamelCase(moduleName))
+	}
+	if opts.WithExecutor {
+		fmt.Printf("  - 生成 %sExecutor\n", toCamelCase(moduleName))
+	}
 	confirm, err := utils.ReadInput("确认继续？(y/n): ")
 	if err != nil {
 		return err
@@ -61,34 +95,47 @@ func AddApplicationModule(moduleName string) error {
 	}
 	utils.PrintSuccess("目录结构创建完成")
 
-	// Generate module POM
-	utils.PrintInfo("生成模块POM文件...")
+	// Generate module build file
+	utils.PrintInfo("生成模块构建文件...")
 	if err := generateModulePOM(config, moduleName); err != nil {
 		return err
 	}
-	utils.PrintSuccess("模块POM文件生成完成")
+	utils.PrintSuccess("模块构建文件生成完成")
 
-	// Update parent POM
-	utils.PrintInfo("更新父POM的modules声明...")
-	if err := updateParentPOMModules(projectRoot, moduleName); err != nil {
+	// Register the module in the root build
+	utils.PrintInfo("在根构建文件中注册模块...")
+	if err := registerModule(config, "application/application-"+moduleName, "application-"+moduleName); err != nil {
 		return err
 	}
-	utils.PrintSuccess("父POM modules更新完成")
-
-	utils.PrintInfo("更新父POM的dependencyManagement...")
-	if err := updateParentPOMDependencyManagement(projectRoot, config, moduleName); err != nil {
-		return err
+	utils.PrintSuccess("根构建文件更新完成")
+
+	// Wire into adapter-rest
+	if opts.Wire {
+		utils.PrintInfo("将模块接入adapter-rest...")
+		added, err := addModuleDependency(config, "adapter/adapter-rest", config.GroupID, "application-"+moduleName, "")
+		if err != nil {
+			return err
+		}
+		if added {
+			utils.PrintSuccess("adapter-rest依赖更新完成")
+		} else {
+			utils.PrintWarning("adapter-rest已依赖该模块")
+		}
 	}
-	utils.PrintSuccess("父POM dependencyManagement更新完成")
 
 	// Generate sample service
 	utils.PrintInfo("生成示例Service类...")
-	if err := generateSampleService(config, moduleName); err != nil {
+	if err := generateSampleService(config, moduleName, opts.WithExecutor); err != nil {
 		return err
 	}
 	utils.PrintSuccess("示例Service类生成完成")
 
-	printModuleSummary(config, moduleName)
+	// Generate optional scaffolds
+	if err := generateModuleScaffolds(config, moduleName, opts); err != nil {
+		return err
+	}
+
+	printModuleSummary(config, moduleName, opts)
 	return nil
 }
 
@@ -109,6 +156,7 @@ func createModuleStructure(config *ProjectConfig, moduleName string) error {
 		filepath.Join(moduleDir, "src/main/java", pkgPath, "application", modulePackage, "dto"),
 		filepath.Join(moduleDir, "src/main/java", pkgPath, "application", modulePackage, "assembler"),
 		filepath.Join(moduleDir, "src/main/java", pkgPath, "application", modulePackage, "executor"),
+		filepath.Join(moduleDir, "src/main/java", pkgPath, "application", modulePackage, "listener"),
 		filepath.Join(moduleDir, "src/main/resources"),
 		filepath.Join(moduleDir, "src/test/java", pkgPath, "application", modulePackage),
 	}
@@ -124,117 +172,121 @@ func generateModulePOM(config *ProjectConfig, moduleName string) error {
 	replacements["{{MODULE_NAME}}"] = moduleName
 	replacements["{{MODULE_DESCRIPTION}}"] = moduleDescription
 
-	content := utils.ReplacePlaceholders(templates.ApplicationModulePOM, replacements)
-	moduleDir := filepath.Join(config.OutputDir, "application", "application-"+moduleName)
-	return utils.WriteFile(filepath.Join(moduleDir, "pom.xml"), content)
+	return writeModuleBuildFile(config, "application/application-"+moduleName,
+		templates.ApplicationModulePOM, templates.ApplicationModuleBuildGradle, replacements)
 }
 
-func updateParentPOMModules(projectRoot string, moduleName string) error {
+func updateParentPOMModules(projectRoot string, modulePath string) error {
 	pomPath := filepath.Join(projectRoot, "pom.xml")
-	content, err := os.ReadFile(pomPath)
+	doc, err := pom.Load(pomPath)
 	if err != nil {
 		return err
 	}
 
-	pomContent := string(content)
-
-	// Check if module already exists
-	moduleEntry := fmt.Sprintf("<module>application/application-%s</module>", moduleName)
-	if strings.Contains(pomContent, moduleEntry) {
+	added, err := doc.AddModule(modulePath)
+	if err != nil {
+		return err
+	}
+	if !added {
 		utils.PrintWarning("模块已在父POM的modules中声明")
 		return nil
 	}
 
-	// Find </modules> and insert before it
-	modulesEnd := strings.Index(pomContent, "</modules>")
-	if modulesEnd == -1 {
-		return fmt.Errorf("could not find </modules> tag in parent pom.xml")
-	}
-
-	// Insert the new module
-	newModule := fmt.Sprintf("        <module>application/application-%s</module>\n    ", moduleName)
-	newContent := pomContent[:modulesEnd] + newModule + pomContent[modulesEnd:]
-
-	return os.WriteFile(pomPath, []byte(newContent), 0644)
+	return doc.Save(pomPath)
 }
 
-func updateParentPOMDependencyManagement(projectRoot string, config *ProjectConfig, moduleName string) error {
+func updateParentPOMDependencyManagement(projectRoot string, config *ProjectConfig, artifactID string) error {
 	pomPath := filepath.Join(projectRoot, "pom.xml")
-	content, err := os.ReadFile(pomPath)
+	doc, err := pom.Load(pomPath)
 	if err != nil {
 		return err
 	}
 
-	pomContent := string(content)
-
-	// Check if dependency already exists
-	artifactEntry := fmt.Sprintf("<artifactId>application-%s</artifactId>", moduleName)
-	if strings.Contains(pomContent, artifactEntry) {
+	added, err := doc.AddManagedDependency(pom.Dependency{
+		GroupID:    config.GroupID,
+		ArtifactID: artifactID,
+		Version:    "${project.version}",
+	})
+	if err != nil {
+		return err
+	}
+	if !added {
 		utils.PrintWarning("模块依赖已在父POM的dependencyManagement中声明")
 		return nil
 	}
 
-	// Find application-user dependency and add after it
-	userDepStart := strings.Index(pomContent, "<artifactId>application-user</artifactId>")
-	if userDepStart == -1 {
-		return fmt.Errorf("could not find application-user dependency in parent pom.xml")
-	}
-
-	// Find the closing </dependency> tag after application-user
-	searchStart := userDepStart
-	depEnd := strings.Index(pomContent[searchStart:], "</dependency>")
-	if depEnd == -1 {
-		return fmt.Errorf("could not find closing </dependency> tag")
-	}
-	depEnd += searchStart + len("</dependency>")
-
-	// Insert new dependency
-	newDep := fmt.Sprintf(`
-            <dependency>
-                <groupId>%s</groupId>
-                <artifactId>application-%s</artifactId>
-                <version>${project.version}</version>
-            </dependency>`, config.GroupID, moduleName)
-
-	newContent := pomContent[:depEnd] + newDep + pomContent[depEnd:]
-
-	return os.WriteFile(pomPath, []byte(newContent), 0644)
+	return doc.Save(pomPath)
 }
 
-func generateSampleService(config *ProjectConfig, moduleName string) error {
+func generateSampleService(config *ProjectConfig, moduleName string, withExecutor bool) error {
 	// Convert module-name to ModuleName (CamelCase)
 	className := toCamelCase(moduleName)
 
 	// Convert module-name to modulepackage (remove dashes for package)
 	modulePackage := strings.ReplaceAll(moduleName, "-", "")
 
-	serviceTemplate := fmt.Sprintf(`package %s.application.%s.service;
-
-import lombok.extern.slf4j.Slf4j;
-import org.springframework.stereotype.Service;
-
-/**
- * %s业务服务
- */
-@Slf4j
-@Service
-public class %sService {
-
-    /**
-     * 示例方法
-     */
-    public String execute() {
-        log.info("Executing %sService");
-        return "%s service executed successfully";
-    }
-}
-`, config.PackageName, modulePackage, className, className, className, className)
+	template := templates.ApplicationModuleService
+	if withExecutor {
+		template = templates.ApplicationModuleServiceWithExecutor
+	}
+	content := utils.ReplacePlaceholders(template, moduleReplacements(config, moduleName))
 
 	moduleDir := filepath.Join(config.OutputDir, "application", "application-"+moduleName)
 	pkgPath := config.PackagePath
 	servicePath := filepath.Join(moduleDir, "src/main/java", pkgPath, "application", modulePackage, "service", className+"Service.java")
 
-	return utils.WriteFile(servicePath, serviceTemplate)
+	return utils.WriteFile(servicePath, content)
+}
+
+// generateModuleScaffolds generates the optional executor, listener and controller
+func generateModuleScaffolds(config *ProjectConfig, moduleName string, opts AddModuleOptions) error {
+	className := toCamelCase(moduleName)
+	modulePackage := strings.ReplaceAll(moduleName, "-", "")
+	replacements := moduleReplacements(config, moduleName)
+
+	appJavaDir := filepath.Join(config.OutputDir, "application", "application-"+moduleName, "src/main/java", config.PackagePath, "application", modulePackage)
+	restJavaDir := filepath.Join(config.OutputDir, "adapter/adapter-rest/src/main/java", config.PackagePath, "adapter/rest")
+
+	type scaffold struct {
+		enabled  bool
+		name     string
+		path     string
+		template string
+	}
+	scaffolds := []scaffold{
+		{opts.WithExecutor, className + "Executor", filepath.Join(appJavaDir, "executor", className+"Executor.java"), templates.ApplicationModuleExecutor},
+		{opts.WithListener, className + "EventListener", filepath.Join(appJavaDir, "listener", className+"EventListener.java"), templates.ApplicationModuleListener},
+		{opts.WithController, className + "Controller", filepath.Join(restJavaDir, "controller", className+"Controller.java"), templates.ApplicationModuleController},
+	}
+
+	for _, s := range scaffolds {
+		if !s.enabled {
+			continue
+		}
+		if utils.FileExists(s.path) {
+			utils.PrintWarning(fmt.Sprintf("%s 已存在，跳过生成", s.name))
+			continue
+		}
+		utils.PrintInfo(fmt.Sprintf("生成%s...", s.name))
+		if err := utils.WriteFile(s.path, utils.ReplacePlaceholders(s.template, replacements)); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("%s生成完成", s.name))
+	}
+
+	return nil
+}
+
+// moduleReplacements returns the template replacements for an application module
+func moduleReplacements(config *ProjectConfig, moduleName string) map[string]string {
+	className := toCamelCase(moduleName)
+
+	replacements := config.GetReplacements()
+	replacements["{{MODULE_NAME}}"] = moduleName
+	replacements["{{MODULE_PACKAGE}}"] = strings.ReplaceAll(moduleName, "-", "")
+	replacements["{{MODULE_CLASS}}"] = className
+	replacements["{{MODULE_FIELD}}"] = lowerFirst(className)
+	return replacements
 }
 
 func toCamelCase(input string) string {
@@ -248,7 +300,7 @@ func toCamelCase(input string) string {
 	return strings.Join(parts, "")
 }
 
-func printModuleSummary(config *ProjectConfig, moduleName string) {
+func printModuleSummary(config *ProjectConfig, moduleName string, opts AddModuleOptions) {
 	fmt.Println()
 	utils.PrintSuccess("==========================================")
 	utils.PrintSuccess(fmt.Sprintf("模块 application-%s 创建完成！", moduleName))
@@ -256,13 +308,23 @@ func printModuleSummary(config *ProjectConfig, moduleName string) {
 	fmt.Println()
 	utils.PrintInfo("下一步：")
 	fmt.Printf("  1. 查看生成的文件: ls -la application/application-%s/\n", moduleName)
-	fmt.Println("  2. 重新构建项目: mvn clean install")
+	fmt.Printf("  2. 重新构建项目: %s\n", config.BuildCommand())
 	fmt.Println("  3. 开始开发业务逻辑")
 	fmt.Println()
-	utils.PrintInfo("如需在adapter-rest中使用此模块，请手动添加依赖：")
-	fmt.Println("  <dependency>")
-	fmt.Printf("      <groupId>%s</groupId>\n", config.GroupID)
-	fmt.Printf("      <artifactId>application-%s</artifactId>\n", moduleName)
-	fmt.Println("  </dependency>")
-	fmt.Println()
+	if opts.WithController {
+		utils.PrintInfo(fmt.Sprintf("测试示例接口: curl http://localhost:8080/api/%s", moduleName))
+		fmt.Println()
+	}
+	if !opts.Wire {
+		utils.PrintInfo("如需在adapter-rest中使用此模块，请手动添加依赖（或在创建时使用 --wire）：")
+		if config.isGradle() {
+			fmt.Printf("  api(project(\":application-%s\"))\n", moduleName)
+		} else {
+			fmt.Println("  <dependency>")
+			fmt.Printf("      <groupId>%s</groupId>\n", config.GroupID)
+			fmt.Printf("      <artifactId>application-%s</artifactId>\n", moduleName)
+			fmt.Println("  </dependency>")
+		}
+		fmt.Println()
+	}
 }
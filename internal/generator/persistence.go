@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/catalog"
+	"github.com/phixia/phjvgen/internal/gradle"
+	"github.com/phixia/phjvgen/internal/templates"
+)
+
+// Persistence stacks of the infrastructure layer
+const (
+	PersistenceMybatisPlus = "mybatis-plus"
+	PersistenceJPA         = "jpa"
+	PersistenceJDBC        = "jdbc"
+)
+
+// DefaultPersistence is the persistence stack of a new project
+const DefaultPersistence = PersistenceMybatisPlus
+
+// persistenceStack describes how the infrastructure layer reads and writes
+// the database. The domain layer's repository interfaces do not depend on it.
+type persistenceStack struct {
+	name        string
+	displayName string
+	// dependency is the dependency catalog name of the stack's starter
+	dependency string
+	// buildComment labels the starter in the root build
+	buildComment string
+	// accessPackage is the persistence subpackage of the data access type
+	// used by the repository implementations, e.g. mapper for UserMapper
+	accessPackage string
+	accessType    string
+	// Templates of the demo User aggregate
+	userDO             string
+	userAccess         string
+	userRepositoryImpl string
+}
+
+var persistenceStacks = []persistenceStack{
+	{
+		name: PersistenceMybatisPlus, displayName: "MyBatis Plus 3.5.8+", dependency: "mybatis-plus", buildComment: "MyBatis Plus",
+		accessPackage: "mapper", accessType: "UserMapper",
+		userDO: templates.UserDO, userAccess: templates.UserMapper, userRepositoryImpl: templates.UserRepositoryImpl,
+	},
+	{
+		name: PersistenceJPA, displayName: "Spring Data JPA（Hibernate）", dependency: "jpa", buildComment: "Spring Data JPA",
+		accessPackage: "repository", accessType: "UserJpaRepository",
+		userDO: templates.JpaUserDO, userAccess: templates.UserJpaRepository, userRepositoryImpl: templates.JpaUserRepositoryImpl,
+	},
+	{
+		name: PersistenceJDBC, displayName: "Spring JDBC（JdbcClient）", dependency: "jdbc", buildComment: "Spring JDBC",
+		accessPackage: "dao", accessType: "UserDao",
+		userDO: templates.JdbcUserDO, userAccess: templates.UserDao, userRepositoryImpl: templates.JdbcUserRepositoryImpl,
+	},
+}
+
+// ValidatePersistence checks the value of --persistence
+func ValidatePersistence(name string) error {
+	if _, ok := lookupPersistenceStack(name); !ok {
+		var names []string
+		for _, s := range persistenceStacks {
+			names = append(names, s.name)
+		}
+		return fmt.Errorf("不支持的持久层: %s（可选: %s）", name, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+func lookupPersistenceStack(name string) (persistenceStack, bool) {
+	for _, s := range persistenceStacks {
+		if s.name == name {
+			return s, true
+		}
+	}
+	return persistenceStack{}, false
+}
+
+// persistence returns the project's persistence stack
+func (c *ProjectConfig) persistence() persistenceStack {
+	if s, ok := lookupPersistenceStack(c.Persistence); ok {
+		return s
+	}
+	s, _ := lookupPersistenceStack(DefaultPersistence)
+	return s
+}
+
+// usesMybatisPlus reports whether the project scans MyBatis mappers
+func (c *ProjectConfig) usesMybatisPlus() bool {
+	return c.persistence().name == PersistenceMybatisPlus
+}
+
+// persistenceReplacements returns the template placeholders of the project's
+// persistence stack. Blocks that may be empty carry their own line breaks,
+// so that leaving one out keeps the surrounding layout unchanged.
+func (c *ProjectConfig) persistenceReplacements() map[string]string {
+	stack := c.persistence()
+	starter, _ := catalog.Lookup(stack.dependency)
+	replacements := map[string]string{
+		"{{PERSISTENCE_DISPLAY_NAME}}":       stack.displayName,
+		"{{PERSISTENCE_GROUP_ID}}":           starter.GroupID,
+		"{{PERSISTENCE_ARTIFACT_ID}}":        starter.ArtifactID,
+		"{{PERSISTENCE_ACCESSOR}}":           gradle.Library{Alias: starter.ArtifactID}.Accessor(),
+		"{{PERSISTENCE_VERSION_PROPERTY}}":   "",
+		"{{PERSISTENCE_MANAGED_DEPENDENCY}}": "",
+		"{{PERSISTENCE_CATALOG_VERSION}}":    "",
+		"{{PERSISTENCE_CATALOG_LIBRARY}}": fmt.Sprintf("# %s\n%s = { module = %q }\n\n",
+			stack.buildComment, starter.ArtifactID, starter.Coordinates()),
+		"{{MAPPER_SCAN_IMPORT}}":      "",
+		"{{MAPPER_SCAN}}":             "",
+		"{{PERSISTENCE_SPRING_YML}}":  "",
+		"{{PERSISTENCE_YML}}":         "",
+		"{{PERSISTENCE_PROFILE_YML}}": "",
+	}
+	// Starters without a version are managed by the Spring Boot BOM
+	if starter.Version != "" {
+		versionName := catalogVersionName(starter)
+		replacements["{{PERSISTENCE_VERSION_PROPERTY}}"] = fmt.Sprintf("        <%s>%s</%s>\n", starter.VersionProperty, starter.Version, starter.VersionProperty)
+		replacements["{{PERSISTENCE_MANAGED_DEPENDENCY}}"] = fmt.Sprintf(`            <!-- %s -->
+            <dependency>
+                <groupId>%s</groupId>
+                <artifactId>%s</artifactId>
+                <version>${%s}</version>
+            </dependency>
+
+`, stack.buildComment, starter.GroupID, starter.ArtifactID, starter.VersionProperty)
+		replacements["{{PERSISTENCE_CATALOG_VERSION}}"] = fmt.Sprintf("%s = %q\n", versionName, starter.Version)
+		replacements["{{PERSISTENCE_CATALOG_LIBRARY}}"] = fmt.Sprintf("# %s\n%s = { module = %q, version.ref = %q }\n\n",
+			stack.buildComment, starter.ArtifactID, starter.Coordinates(), versionName)
+	}
+
+	switch stack.name {
+	case PersistenceMybatisPlus:
+		replacements["{{MAPPER_SCAN_IMPORT}}"] = "\nimport org.mybatis.spring.annotation.MapperScan;"
+		replacements["{{MAPPER_SCAN}}"] = fmt.Sprintf("\n@MapperScan(%q)", c.PackageName+".infrastructure.persistence.mapper")
+		replacements["{{PERSISTENCE_YML}}"] = `
+mybatis-plus:
+  configuration:
+    map-underscore-to-camel-case: true
+    log-impl: org.apache.ibatis.logging.stdout.StdOutImpl
+  mapper-locations: classpath*:/mapper/**/*Mapper.xml
+`
+		replacements["{{PERSISTENCE_PROFILE_YML}}"] = `
+mybatis-plus:
+  configuration:
+    log-impl: org.apache.ibatis.logging.slf4j.Slf4jImpl
+`
+	case PersistenceJPA:
+		replacements["{{PERSISTENCE_SPRING_YML}}"] = `  jpa:
+    open-in-view: false
+    hibernate:
+      # 表结构由 db/migration 下的脚本维护
+      ddl-auto: none
+`
+	}
+	return replacements
+}
+
+// userPersistenceFiles returns the infrastructure files of the demo User
+// aggregate for the project's persistence stack
+func userPersistenceFiles(config *ProjectConfig) map[string]string {
+	stack := config.persistence()
+	dir := filepath.Join(config.OutputDir, "infrastructure/src/main/java", config.PackagePath, "infrastructure/persistence")
+	return map[string]string{
+		filepath.Join(dir, "dataobject/UserDO.java"):                      stack.userDO,
+		filepath.Join(dir, stack.accessPackage, stack.accessType+".java"): stack.userAccess,
+		filepath.Join(dir, "impl/UserRepositoryImpl.java"):                stack.userRepositoryImpl,
+	}
+}
+
+// detectPersistence finds the persistence stack of an existing project from
+// the starters its modules depend on, falling back to DefaultPersistence
+func detectPersistence(config *ProjectConfig) string {
+	modules, err := listProjectModules(config)
+	if err != nil {
+		return DefaultPersistence
+	}
+	for _, stack := range persistenceStacks {
+		entry, _ := catalog.Lookup(stack.dependency)
+		for _, m := range modules {
+			if moduleUsesDependency(config, m.path, entry) {
+				return stack.name
+			}
+		}
+	}
+	return DefaultPersistence
+}
@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/phixia/phjvgen/internal/pom"
+	"github.com/phixia/phjvgen/internal/templates"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// springProfileProperty is the Maven property filtered into application.yml
+const springProfileProperty = "spring.profiles.active"
+
+// localProfile is the profile of the git-ignored application-local.yml
+const localProfile = "local"
+
+// DefaultProfiles are the Spring profiles of a new project. The first one is
+// active by default.
+var DefaultProfiles = []string{"dev", "test", "prod"}
+
+// profileTemplates are the application-<profile>.yml templates of the
+// well-known profiles; other profiles use ApplicationProfileYML
+var profileTemplates = map[string]string{
+	"dev":  templates.ApplicationDevYML,
+	"test": templates.ApplicationTestYML,
+	"prod": templates.ApplicationProdYML,
+}
+
+var profileNameRe = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// ValidateProfiles checks a list of Spring profile names
+func ValidateProfiles(profiles []string) error {
+	if len(profiles) == 0 {
+		return fmt.Errorf("至少需要一个 profile")
+	}
+	seen := map[string]bool{}
+	for _, p := range profiles {
+		if !profileNameRe.MatchString(p) {
+			return fmt.Errorf("profile 名称格式不正确: %s（请使用小写字母、数字和连字符）", p)
+		}
+		if p == localProfile {
+			return fmt.Errorf("profile 名称 %s 已保留给 application-local.yml", p)
+		}
+		if seen[p] {
+			return fmt.Errorf("profile 重复: %s", p)
+		}
+		seen[p] = true
+	}
+	return nil
+}
+
+// profiles returns the project's Spring profiles
+func (c *ProjectConfig) profiles() []string {
+	if len(c.Profiles) == 0 {
+		return DefaultProfiles
+	}
+	return c.Profiles
+}
+
+// DefaultProfile returns the profile active when none is selected at build time
+func (c *ProjectConfig) DefaultProfile() string {
+	return c.profiles()[0]
+}
+
+// generateProfileFiles writes application-<profile>.yml for every profile
+// and the application-local.yml example
+func generateProfileFiles(config *ProjectConfig) error {
+	resources := filepath.Join(config.OutputDir, "starter/src/main/resources")
+	replacements := config.GetReplacements()
+
+	for _, profile := range config.profiles() {
+		template, ok := profileTemplates[profile]
+		if !ok {
+			template = templates.ApplicationProfileYML
+		}
+		replacements["{{PROFILE}}"] = profile
+		content := utils.ReplacePlaceholders(template, replacements)
+		if err := utils.WriteFile(filepath.Join(resources, "application-"+profile+".yml"), content); err != nil {
+			return err
+		}
+	}
+
+	content := utils.ReplacePlaceholders(templates.ApplicationLocalYMLExample, replacements)
+	return utils.WriteFile(filepath.Join(resources, "application-local.yml.example"), content)
+}
+
+// mavenProfile renders a <profile> that selects a Spring profile
+func mavenProfile(profile string, activeByDefault bool) string {
+	activation := ""
+	if activeByDefault {
+		activation = `
+    <activation>
+        <activeByDefault>true</activeByDefault>
+    </activation>`
+	}
+	return fmt.Sprintf(`<profile>
+    <id>%s</id>%s
+    <properties>
+        <%s>%s</%s>
+    </properties>
+</profile>`, profile, activation, springProfileProperty, profile, springProfileProperty)
+}
+
+// addMavenProfiles adds a Maven profile per Spring profile to the parent POM.
+// The property default also covers builds that activate unrelated profiles,
+// which turns off activeByDefault.
+func addMavenProfiles(config *ProjectConfig) error {
+	pomPath := filepath.Join(config.OutputDir, "pom.xml")
+	doc, err := pom.Load(pomPath)
+	if err != nil {
+		return err
+	}
+	if err := doc.SetProperty(springProfileProperty, config.DefaultProfile()); err != nil {
+		return err
+	}
+	for i, profile := range config.profiles() {
+		if _, err := doc.AddProfile(profile, mavenProfile(profile, i == 0)); err != nil {
+			return err
+		}
+	}
+	return doc.Save(pomPath)
+}
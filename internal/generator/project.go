@@ -2,7 +2,9 @@ package generator
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/phixia/phjvgen/internal/templates"
 	"github.com/phixia/phjvgen/internal/utils"
@@ -16,17 +18,47 @@ func GenerateProject(config *ProjectConfig) error {
 	}
 	utils.PrintSuccess("目录结构创建完成")
 
-	utils.PrintInfo("生成父POM文件...")
-	if err := generateParentPOM(config); err != nil {
-		return err
-	}
-	utils.PrintSuccess("父POM文件生成完成")
+	if config.isGradle() {
+		utils.PrintInfo("生成Gradle构建文件...")
+		if err := generateGradleBuild(config); err != nil {
+			return err
+		}
+		utils.PrintSuccess("Gradle构建文件生成完成")
+	} else {
+		utils.PrintInfo("生成父POM文件...")
+		if err := generateParentPOM(config); err != nil {
+			return err
+		}
+		utils.PrintSuccess("父POM文件生成完成")
 
-	utils.PrintInfo("生成模块POM文件...")
-	if err := generateModulePOMs(config); err != nil {
-		return err
+		utils.PrintInfo("生成模块POM文件...")
+		if err := generateModulePOMs(config); err != nil {
+			return err
+		}
+		utils.PrintSuccess("模块POM文件生成完成")
+
+		if config.MavenVersion != "" {
+			utils.PrintInfo("生成Maven Wrapper...")
+			if err := generateMavenWrapper(config); err != nil {
+				return err
+			}
+			utils.PrintSuccess(fmt.Sprintf("Maven Wrapper生成完成（Maven %s）", config.MavenVersion))
+		}
+
+		utils.PrintInfo("生成Maven profiles...")
+		if err := addMavenProfiles(config); err != nil {
+			return err
+		}
+		utils.PrintSuccess("Maven profiles生成完成")
+
+		if config.Enforcer {
+			utils.PrintInfo("生成架构约束规则...")
+			if err := addEnforcerRules(config); err != nil {
+				return err
+			}
+			utils.PrintSuccess("maven-enforcer-plugin规则生成完成")
+		}
 	}
-	utils.PrintSuccess("模块POM文件生成完成")
 
 	utils.PrintInfo("生成基础Java源文件...")
 	if err := generateBasicSourceFiles(config); err != nil {
@@ -85,7 +117,7 @@ func createDirectoryStructure(config *ProjectConfig) error {
 		filepath.Join(baseDir, "domain/src/test/java", pkgPath, "domain"),
 
 		// Infrastructure module
-		filepath.Join(baseDir, "infrastructure/src/main/java", pkgPath, "infrastructure/persistence/mapper"),
+		filepath.Join(baseDir, "infrastructure/src/main/java", pkgPath, "infrastructure/persistence", config.persistence().accessPackage),
 		filepath.Join(baseDir, "infrastructure/src/main/java", pkgPath, "infrastructure/persistence/impl"),
 		filepath.Join(baseDir, "infrastructure/src/main/java", pkgPath, "infrastructure/cache"),
 		filepath.Join(baseDir, "infrastructure/src/main/java", pkgPath, "infrastructure/mq"),
@@ -159,6 +191,57 @@ func generateModulePOMs(config *ProjectConfig) error {
 	return nil
 }
 
+// generateGradleBuild generates the Kotlin DSL build, the Gradle counterpart
+// of the parent and module POMs
+func generateGradleBuild(config *ProjectConfig) error {
+	replacements := config.GetReplacements()
+
+	files := map[string]string{
+		"settings.gradle.kts":                           templates.SettingsGradle,
+		"build.gradle.kts":                              templates.RootBuildGradle,
+		"gradle/libs.versions.toml":                     templates.LibsVersionsToml,
+		"common/build.gradle.kts":                       templates.CommonBuildGradle,
+		"domain/build.gradle.kts":                       templates.DomainBuildGradle,
+		"infrastructure/build.gradle.kts":               templates.InfrastructureBuildGradle,
+		"adapter/adapter-rest/build.gradle.kts":         templates.AdapterRestBuildGradle,
+		"adapter/adapter-schedule/build.gradle.kts":     templates.AdapterScheduleBuildGradle,
+		"application/application-user/build.gradle.kts": templates.ApplicationUserBuildGradle,
+		"starter/build.gradle.kts":                      templates.StarterBuildGradle,
+	}
+
+	for path, template := range files {
+		content := utils.ReplacePlaceholders(template, replacements)
+		if err := utils.WriteFile(filepath.Join(config.OutputDir, path), content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// generateMavenWrapper writes mvnw, mvnw.cmd and the wrapper properties, so
+// the project builds without a Maven installation
+func generateMavenWrapper(config *ProjectConfig) error {
+	baseDir := config.OutputDir
+
+	mvnw := filepath.Join(baseDir, "mvnw")
+	if err := utils.WriteFile(mvnw, templates.MavenWrapperScript); err != nil {
+		return err
+	}
+	if err := os.Chmod(mvnw, 0755); err != nil {
+		return fmt.Errorf("failed to make %s executable: %w", mvnw, err)
+	}
+
+	// Windows batch files need CRLF line endings
+	cmd := strings.ReplaceAll(templates.MavenWrapperCmd, "\n", "\r\n")
+	if err := utils.WriteFile(filepath.Join(baseDir, "mvnw.cmd"), cmd); err != nil {
+		return err
+	}
+
+	properties := utils.ReplacePlaceholders(templates.MavenWrapperProperties, config.GetReplacements())
+	return utils.WriteFile(filepath.Join(baseDir, ".mvn", "wrapper", "maven-wrapper.properties"), properties)
+}
+
 func generateBasicSourceFiles(config *ProjectConfig) error {
 	// This function is now simplified as demo code generation will handle most files
 	// We only generate the starter Application class here as it's always needed
@@ -170,6 +253,18 @@ func generateBasicSourceFiles(config *ProjectConfig) error {
 		// Starter module - always needed
 		filepath.Join("starter/src/main/java", pkgPath, "Application.java"): templates.ApplicationMain,
 	}
+	if config.usesMybatisPlus() {
+		// Pagination dialect of the selected database
+		files[filepath.Join("infrastructure/src/main/java", pkgPath, "infrastructure/config/MybatisPlusConfig.java")] = templates.MybatisPlusConfig
+	}
+	if config.MultiTenant {
+		for path, template := range tenantFiles(pkgPath) {
+			files[path] = template
+		}
+	}
+	for path, template := range idGeneratorFiles(config) {
+		files[path] = template
+	}
 
 	for path, template := range files {
 		content := utils.ReplacePlaceholders(template, replacements)
@@ -186,8 +281,7 @@ func generateConfigFiles(config *ProjectConfig) error {
 	baseDir := config.OutputDir
 
 	files := map[string]string{
-		"starter/src/main/resources/application.yml":     templates.ApplicationYML,
-		"starter/src/main/resources/application-dev.yml": templates.ApplicationDevYML,
+		"starter/src/main/resources/application.yml": templates.ApplicationYML,
 	}
 
 	for path, template := range files {
@@ -197,7 +291,10 @@ func generateConfigFiles(config *ProjectConfig) error {
 		}
 	}
 
-	return nil
+	if err := generateMigrationConfig(config); err != nil {
+		return err
+	}
+	return generateProfileFiles(config)
 }
 
 func generateREADME(config *ProjectConfig) error {
@@ -231,12 +328,8 @@ func generateDemoCodeForProject(config *ProjectConfig) error {
 	}
 
 	// Generate Infrastructure layer
-	infrastructureFiles := map[string]string{
-		filepath.Join(baseDir, "infrastructure/src/main/java", pkgPath, "infrastructure/persistence/dataobject/UserDO.java"): templates.UserDO,
-		filepath.Join(baseDir, "infrastructure/src/main/java", pkgPath, "infrastructure/persistence/mapper/UserMapper.java"): templates.UserMapper,
-		filepath.Join(baseDir, "infrastructure/src/main/java", pkgPath, "infrastructure/persistence/impl/UserRepositoryImpl.java"): templates.UserRepositoryImpl,
-		filepath.Join(baseDir, "infrastructure/src/main/resources/db/migration/V1__create_user_table.sql"): templates.UserTableSQL,
-	}
+	infrastructureFiles := userPersistenceFiles(config)
+	infrastructureFiles[filepath.Join(baseDir, "infrastructure/src/main/resources/db/migration/V1__create_user_table.sql")] = migrationScriptContent(config, "1", userTableSQL(config))
 
 	// Generate Application layer
 	applicationFiles := map[string]string{
@@ -290,7 +383,8 @@ func generateDemoCodeForProject(config *ProjectConfig) error {
 		}
 	}
 
-	return nil
+	// V1 creates the user table, so it starts out in the schema snapshot
+	return recordManifestTable(baseDir, scaffoldUserTable(config))
 }
 
 // PrintGenerationSummary prints a summary after project generation
@@ -306,20 +400,35 @@ func PrintGenerationSummary(config *ProjectConfig) {
 	fmt.Println("  ✅ Application主类（启动类）")
 	fmt.Println("  ✅ Common层：Result、BusinessException、ErrorCode")
 	fmt.Println("  ✅ Domain层：User实体、UserRepository接口")
-	fmt.Println("  ✅ Infrastructure层：UserDO、UserMapper、UserRepositoryImpl")
+	fmt.Printf("  ✅ Infrastructure层：UserDO、%s、UserRepositoryImpl\n", config.persistence().accessType)
 	fmt.Println("  ✅ Application层：UserDTO、UserService、UserAssembler")
 	fmt.Println("  ✅ Adapter层：UserController、Request/Response、ExceptionHandler")
 	fmt.Println("  ✅ 数据库脚本：V1__create_user_table.sql")
+	if config.MultiTenant {
+		fmt.Println("  ✅ 多租户：TenantContext、TenantFilter、MybatisTenantLineHandler")
+	}
 	fmt.Println()
 	utils.PrintInfo("后续步骤:")
 	fmt.Printf("  1. cd %s\n", config.OutputDir)
-	fmt.Println("  2. 创建数据库并配置连接（starter/src/main/resources/application-dev.yml）")
-	fmt.Println("  3. 执行数据库脚本：infrastructure/src/main/resources/db/migration/V1__create_user_table.sql")
-	fmt.Println("  4. mvn clean install")
-	fmt.Println("  5. java --enable-preview -jar starter/target/starter-*.jar")
+	fmt.Println("  2. 创建数据库，复制 starter/src/main/resources/application-local.yml.example 为 application-local.yml 并配置连接")
+	if tool := config.migration(); tool != MigrationNone {
+		fmt.Printf("  3. 数据库脚本由%s在启动时自动执行：%s/V1__create_user_table.sql\n", migrationDisplayName(tool), migrationDir)
+	} else {
+		fmt.Println("  3. 执行数据库脚本：infrastructure/src/main/resources/db/migration/V1__create_user_table.sql")
+	}
+	fmt.Printf("  4. %s\n", config.BuildCommand())
+	fmt.Printf("  5. java --enable-preview -jar %s\n", config.StarterJar())
 	fmt.Println("  6. 测试健康检查: curl http://localhost:8080/api/health")
-	fmt.Println("  7. 测试创建用户: curl -X POST http://localhost:8080/api/users -H 'Content-Type: application/json' -d '{\"username\":\"test\",\"email\":\"test@example.com\"}'")
+	tenantHeader := ""
+	if config.MultiTenant {
+		tenantHeader = " -H 'X-Tenant-Id: tenant_1'"
+	}
+	fmt.Printf("  7. 测试创建用户: curl -X POST http://localhost:8080/api/users%s -H 'Content-Type: application/json' -d '{\"username\":\"test\",\"email\":\"test@example.com\"}'\n", tenantHeader)
 	fmt.Println()
+	if config.isGradle() {
+		utils.PrintInfo("Gradle项目未包含Wrapper，可在项目目录执行 gradle wrapper 生成")
+		fmt.Println()
+	}
 	utils.PrintInfo("添加新模块:")
 	fmt.Println("  phjvgen add <模块名>")
 	fmt.Println()
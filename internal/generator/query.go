@@ -0,0 +1,628 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/schema"
+	"github.com/phixia/phjvgen/internal/templates"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// QueryOptions holds the options for generating a custom query
+type QueryOptions struct {
+	// Where is the SQL condition of the query. Comparisons without a right
+	// operand, e.g. "email LIKE", become method parameters.
+	Where string
+}
+
+// queryKind is what a query returns, chosen by its method name prefix
+type queryKind int
+
+const (
+	queryList queryKind = iota
+	queryCount
+	queryExists
+)
+
+var (
+	queryMethodRe  = regexp.MustCompile(`^[a-z][A-Za-z0-9]*$`)
+	queryPrefixes  = map[string]queryKind{"find": queryList, "list": queryList, "query": queryList, "search": queryList, "count": queryCount, "exists": queryExists}
+	whereTokenRe   = regexp.MustCompile(`^(\s+|'(?:[^']|'')*'|\d+(?:\.\d+)?|[A-Za-z_][A-Za-z0-9_]*|<=|>=|<>|!=|[=<>(),])`)
+	queryKeywords  = map[string]bool{"AND": true, "OR": true, "NOT": true}
+	javaTypeImport = map[string]string{
+		"BigDecimal":    "java.math.BigDecimal",
+		"LocalDate":     "java.time.LocalDate",
+		"LocalDateTime": "java.time.LocalDateTime",
+	}
+)
+
+// queryParam is a method parameter bound in the query
+type queryParam struct {
+	name     string
+	javaType string
+	// list is bound with <foreach>, for IN without values
+	list bool
+}
+
+// GenerateQuery adds a custom query to the MyBatis mapper of an entity: a
+// <select> in its mapper XML, which is created with a result map when
+// missing, the mapper method, and a repository method delegating to it
+func GenerateQuery(entity, method string, opts QueryOptions) error {
+	if !queryMethodRe.MatchString(method) {
+		return fmt.Errorf("方法名格式不正确，请使用小驼峰命名，例如: findActiveByEmailDomain")
+	}
+	kind, ok := queryMethodKind(method)
+	if !ok {
+		return fmt.Errorf("方法名需以 find、list、query、search（返回列表）、count（返回数量）或 exists（返回是否存在）开头")
+	}
+
+	projectRoot, config, err := migrationProject()
+	if err != nil {
+		return err
+	}
+	if !config.usesMybatisPlus() {
+		return fmt.Errorf("自定义查询生成 MyBatis Mapper XML，仅支持 MyBatis-Plus 项目，当前项目使用 %s", config.persistence().displayName)
+	}
+	manifest, err := readManifest(projectRoot)
+	if err != nil {
+		return err
+	}
+	if err := validateEntities(manifest.Entities); err != nil {
+		return err
+	}
+	table, ok := findEntityTable(manifest.Entities, entity)
+	if !ok {
+		return fmt.Errorf("%s 中未找到实体 %s 对应的表", manifestFile, entity)
+	}
+
+	condition, conditionText, params, err := parseWhere(opts.Where, table)
+	if err != nil {
+		return err
+	}
+
+	entityClass := toCamelCase(entity)
+	entityClass = strings.ToUpper(entityClass[:1]) + entityClass[1:]
+	javaDir := filepath.Join(projectRoot, "infrastructure/src/main/java", config.PackagePath, "infrastructure/persistence")
+	mapperPath := filepath.Join(javaDir, "mapper", entityClass+"Mapper.java")
+	implPath := filepath.Join(javaDir, "impl", entityClass+"RepositoryImpl.java")
+	repositoryPath := filepath.Join(projectRoot, "domain/src/main/java", config.PackagePath, "domain/repository", entityClass+"Repository.java")
+	xmlPath := filepath.Join(projectRoot, "infrastructure/src/main/resources/mapper", entityClass+"Mapper.xml")
+
+	sources := map[string]string{}
+	for _, path := range []string{mapperPath, implPath, repositoryPath} {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("未找到 %s", relPath(projectRoot, path))
+		}
+		if hasJavaMethod(string(content), method) {
+			return fmt.Errorf("%s 中已存在方法 %s", filepath.Base(path), method)
+		}
+		sources[path] = string(content)
+	}
+
+	xml := utils.ReplacePlaceholders(templates.MapperXML, mapperXMLReplacements(config, entityClass, table))
+	if content, err := os.ReadFile(xmlPath); err == nil {
+		xml = string(content)
+		if strings.Contains(xml, fmt.Sprintf(`id="%s"`, method)) {
+			return fmt.Errorf("%s 中已存在 id 为 %s 的语句", filepath.Base(xmlPath), method)
+		}
+	}
+
+	utils.PrintInfo(fmt.Sprintf("生成查询 %s.%s...", entityClass, method))
+
+	selectXML := querySelectXML(method, kind, table, condition)
+	if err := utils.WriteFile(xmlPath, insertBeforeClosingTag(xml, "</mapper>", selectXML)); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("已更新 %s", relPath(projectRoot, xmlPath)))
+
+	description := queryDescription(kind, table, conditionText)
+	edits := map[string]string{
+		mapperPath:     addQueryToMapper(sources[mapperPath], entityClass, method, kind, params, description),
+		repositoryPath: addQueryToRepository(sources[repositoryPath], entityClass, method, kind, params, description),
+		implPath:       addQueryToRepositoryImpl(sources[implPath], entityClass, method, kind, params),
+	}
+	paths := []string{mapperPath, repositoryPath, implPath}
+	// The caching decorator generated by phjvgen cache implements the
+	// repository as well
+	cachingPath := filepath.Join(projectRoot, "infrastructure/src/main/java", config.PackagePath, "infrastructure/cache", "Caching"+entityClass+"Repository.java")
+	if content, err := os.ReadFile(cachingPath); err == nil && !hasJavaMethod(string(content), method) {
+		edits[cachingPath] = addQueryToCachingRepository(string(content), entityClass, method, kind, params)
+		paths = append(paths, cachingPath)
+	}
+	for _, path := range paths {
+		if err := utils.WriteFile(path, edits[path]); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("已更新 %s", relPath(projectRoot, path)))
+	}
+
+	fmt.Println()
+	utils.PrintInfo(fmt.Sprintf("%s%s(%s) 已加入 %sRepository", lowerFirst(entityClass)+"Repository.", method, queryParamList(params, false), entityClass))
+	for _, p := range params {
+		if strings.HasSuffix(p.name, "Pattern") {
+			utils.PrintInfo(fmt.Sprintf("%s 是 LIKE 的匹配模式，调用时自行加上 %% 通配符，例如 \"%%@example.com\"", p.name))
+		}
+	}
+	return nil
+}
+
+// queryMethodKind returns the kind of a query from its method name prefix
+func queryMethodKind(method string) (queryKind, bool) {
+	for prefix, kind := range queryPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return kind, true
+		}
+	}
+	return 0, false
+}
+
+// parseWhere checks a condition against the columns of a table and renders
+// it for a mapper XML. Comparisons without a right operand are bound to
+// parameters named after their column: LIKE to <column>Pattern, IN to
+// <column>List and BETWEEN to <column>From and <column>To. The condition
+// is also returned as text for Javadoc, with parameters written as :name.
+func parseWhere(where string, table schema.Table) (xml, text string, params []queryParam, err error) {
+	var tokens []string
+	for rest := strings.TrimSpace(where); rest != ""; {
+		m := whereTokenRe.FindString(rest)
+		if m == "" {
+			return "", "", nil, fmt.Errorf("--where 中无法识别: %s", rest)
+		}
+		if strings.TrimSpace(m) != "" {
+			tokens = append(tokens, m)
+		}
+		rest = rest[len(m):]
+	}
+
+	p := &whereParser{tokens: tokens, table: table}
+	if len(tokens) == 0 {
+		return "", "", nil, nil
+	}
+	if err := p.expression(); err != nil {
+		return "", "", nil, err
+	}
+	if p.pos < len(tokens) {
+		return "", "", nil, fmt.Errorf("--where 中多余的内容: %s", strings.Join(tokens[p.pos:], " "))
+	}
+	return joinCondition(p.xml), joinCondition(p.text), p.params, nil
+}
+
+// joinCondition joins the parts of a condition with spaces, except inside
+// parentheses
+func joinCondition(parts []string) string {
+	var b strings.Builder
+	for i, part := range parts {
+		if i > 0 && part != ")" && parts[i-1] != "(" {
+			b.WriteByte(' ')
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+// whereParser parses conditions: predicates on columns combined with AND,
+// OR, NOT and parentheses
+type whereParser struct {
+	tokens []string
+	pos    int
+	table  schema.Table
+	// xml and text are the parts of the condition rendered for the mapper
+	// XML and for Javadoc
+	xml    []string
+	text   []string
+	params []queryParam
+}
+
+// emit appends a part of the condition, escaped for the mapper XML
+func (p *whereParser) emit(parts ...string) {
+	for _, part := range parts {
+		p.xml = append(p.xml, xmlEscape(part))
+		p.text = append(p.text, part)
+	}
+}
+
+// emitParam appends a bound parameter
+func (p *whereParser) emitParam(xml, text string) {
+	p.xml = append(p.xml, xml)
+	p.text = append(p.text, text)
+}
+
+func (p *whereParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// keyword consumes the next token if it is one of the keywords
+func (p *whereParser) keyword(words ...string) (string, bool) {
+	next := strings.ToUpper(p.peek())
+	for _, w := range words {
+		if next == w {
+			p.pos++
+			return w, true
+		}
+	}
+	return "", false
+}
+
+func (p *whereParser) expression() error {
+	for {
+		if err := p.term(); err != nil {
+			return err
+		}
+		op, ok := p.keyword("AND", "OR")
+		if !ok {
+			return nil
+		}
+		p.emit(op)
+	}
+}
+
+func (p *whereParser) term() error {
+	if _, ok := p.keyword("NOT"); ok {
+		p.emit("NOT")
+		return p.term()
+	}
+	if p.peek() == "(" {
+		p.pos++
+		p.emit("(")
+		if err := p.expression(); err != nil {
+			return err
+		}
+		if p.peek() != ")" {
+			return fmt.Errorf("--where 中括号不匹配")
+		}
+		p.pos++
+		p.emit(")")
+		return nil
+	}
+	return p.predicate()
+}
+
+func (p *whereParser) predicate() error {
+	name := p.peek()
+	column, ok := findQueryColumn(p.table, name)
+	if !ok {
+		var names []string
+		for _, c := range p.table.Columns {
+			names = append(names, c.Name)
+		}
+		if name == "" {
+			return fmt.Errorf("--where 不完整，缺少列名")
+		}
+		return fmt.Errorf("表 %s 中不存在列 %s（可用列: %s）", p.table.Name, name, strings.Join(names, ", "))
+	}
+	p.pos++
+	p.emit(column.Name)
+
+	not := ""
+	if _, ok := p.keyword("NOT"); ok {
+		not = "NOT "
+	}
+	switch op := strings.ToUpper(p.peek()); {
+	case op == "IS":
+		p.pos++
+		p.emit("IS")
+		if _, ok := p.keyword("NOT"); ok {
+			p.emit("NOT")
+		}
+		if _, ok := p.keyword("NULL"); !ok {
+			return fmt.Errorf("--where 中 %s IS 之后应为 NULL 或 NOT NULL", column.Name)
+		}
+		p.emit("NULL")
+	case op == "LIKE":
+		p.pos++
+		p.emit(not + "LIKE")
+		p.operand(column, "Pattern")
+	case op == "IN":
+		p.pos++
+		p.emit(not + "IN")
+		if p.peek() != "(" {
+			param := p.addParam(column, "List", true)
+			p.emitParam(fmt.Sprintf(`<foreach collection="%s" item="item" open="(" separator="," close=")">#{item}</foreach>`, param), "(:"+param+")")
+			return nil
+		}
+		var values []string
+		for p.pos++; p.peek() != ")"; p.pos++ {
+			if p.peek() == "" {
+				return fmt.Errorf("--where 中括号不匹配")
+			}
+			values = append(values, p.peek())
+		}
+		p.pos++
+		p.emit("(" + strings.ReplaceAll(strings.Join(values, " "), " ,", ",") + ")")
+	case op == "BETWEEN":
+		p.pos++
+		p.emit(not + "BETWEEN")
+		if p.operand(column, "From") {
+			param := p.addParam(column, "To", false)
+			p.emit("AND")
+			p.emitParam("#{"+param+"}", ":"+param)
+			return nil
+		}
+		if _, ok := p.keyword("AND"); !ok {
+			return fmt.Errorf("--where 中 %s BETWEEN 缺少 AND", column.Name)
+		}
+		p.emit("AND")
+		p.operand(column, "To")
+	case not == "" && (op == "=" || op == "<>" || op == "!=" || op == "<" || op == "<=" || op == ">" || op == ">="):
+		p.pos++
+		p.emit(op)
+		p.operand(column, "")
+	default:
+		return fmt.Errorf("--where 中 %s 之后缺少比较运算符（支持 =、<>、<、<=、>、>=、LIKE、IN、BETWEEN、IS NULL）", column.Name)
+	}
+	return nil
+}
+
+// operand appends the right operand of a comparison as written, or binds
+// a parameter when it is left out and reports so
+func (p *whereParser) operand(column schema.Column, suffix string) bool {
+	next := p.peek()
+	if next == "" || next == ")" || queryKeywords[strings.ToUpper(next)] {
+		param := p.addParam(column, suffix, false)
+		p.emitParam("#{"+param+"}", ":"+param)
+		return true
+	}
+	p.pos++
+	p.emit(next)
+	return false
+}
+
+func (p *whereParser) addParam(column schema.Column, suffix string, list bool) string {
+	base := columnProperty(column.Name) + suffix
+	name := base
+	for n := 2; p.hasParam(name); n++ {
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+	p.params = append(p.params, queryParam{name: name, javaType: columnJavaType(column.Type), list: list})
+	return name
+}
+
+func (p *whereParser) hasParam(name string) bool {
+	for _, param := range p.params {
+		if param.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func findQueryColumn(t schema.Table, name string) (schema.Column, bool) {
+	for _, c := range t.Columns {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+	return schema.Column{}, false
+}
+
+// columnProperty returns the DO property of a column, e.g. createTime
+func columnProperty(column string) string {
+	parts := strings.Split(strings.ToLower(column), "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// columnJavaType returns the Java type of a column in the generated DOs
+func columnJavaType(t schema.ColumnType) string {
+	switch t {
+	case schema.BigInt:
+		return "Long"
+	case schema.Int, schema.TinyInt:
+		return "Integer"
+	case schema.Decimal:
+		return "BigDecimal"
+	case schema.Boolean:
+		return "Boolean"
+	case schema.Date:
+		return "LocalDate"
+	case schema.DateTime:
+		return "LocalDateTime"
+	}
+	return "String"
+}
+
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+func mapperXMLReplacements(config *ProjectConfig, entityClass string, table schema.Table) map[string]string {
+	var resultMap strings.Builder
+	var columns []string
+	for _, c := range table.Columns {
+		tag := "result"
+		if isQueryPrimaryKey(table, c.Name) {
+			tag = "id"
+		}
+		fmt.Fprintf(&resultMap, "        <%s column=\"%s\" property=\"%s\"/>\n", tag, c.Name, columnProperty(c.Name))
+		columns = append(columns, c.Name)
+	}
+	replacements := config.GetReplacements()
+	replacements["{{ENTITY_CLASS}}"] = entityClass
+	replacements["{{RESULT_MAP_COLUMNS}}"] = resultMap.String()
+	replacements["{{BASE_COLUMN_LIST}}"] = strings.Join(columns, ", ")
+	return replacements
+}
+
+func isQueryPrimaryKey(t schema.Table, column string) bool {
+	for _, c := range t.PrimaryKey {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// querySelectXML renders the <select> of a query. Unlike the BaseMapper
+// methods, XML statements are not filtered by @TableLogic, so logically
+// deleted rows are left out here.
+func querySelectXML(method string, kind queryKind, table schema.Table, condition string) string {
+	if _, ok := findQueryColumn(table, "deleted"); ok && !strings.Contains(strings.ToLower(condition), "deleted") {
+		switch {
+		case condition == "":
+			condition = "deleted = 0"
+		case strings.Contains(strings.ToUpper(condition), " OR "):
+			condition = "(" + condition + ") AND deleted = 0"
+		default:
+			condition += " AND deleted = 0"
+		}
+	}
+
+	var b strings.Builder
+	switch kind {
+	case queryCount:
+		fmt.Fprintf(&b, "    <select id=\"%s\" resultType=\"long\">\n        SELECT COUNT(*)\n", method)
+	case queryExists:
+		fmt.Fprintf(&b, "    <select id=\"%s\" resultType=\"boolean\">\n        SELECT COUNT(*) &gt; 0\n", method)
+	default:
+		fmt.Fprintf(&b, "    <select id=\"%s\" resultMap=\"BaseResultMap\">\n        SELECT <include refid=\"Base_Column_List\"/>\n", method)
+	}
+	fmt.Fprintf(&b, "        FROM %s\n", table.Name)
+	if condition != "" {
+		fmt.Fprintf(&b, "        WHERE %s\n", condition)
+	}
+	b.WriteString("    </select>\n")
+	return b.String()
+}
+
+// insertBeforeClosingTag inserts an element, separated by a blank line,
+// before the closing tag of the root element
+func insertBeforeClosingTag(xml, tag, element string) string {
+	end := strings.LastIndex(xml, tag)
+	if end == -1 {
+		return xml
+	}
+	body := strings.TrimRight(xml[:end], " \t\n")
+	return body + "\n\n" + element + xml[end:]
+}
+
+// queryDescription describes a query in the Javadoc of its methods
+func queryDescription(kind queryKind, table schema.Table, condition string) string {
+	subject := strings.TrimSuffix(table.Comment, "表")
+	if subject == "" {
+		subject = table.Name
+	}
+	verb := map[queryKind]string{queryList: "查找", queryCount: "统计", queryExists: "检查是否存在"}[kind]
+	if condition == "" {
+		return verb + subject
+	}
+	return fmt.Sprintf("%s满足条件的%s: {@code %s}", verb, subject, condition)
+}
+
+// queryParamList renders the parameters of a query method, annotated with
+// @Param for a mapper
+func queryParamList(params []queryParam, annotated bool) string {
+	list := make([]string, len(params))
+	for i, p := range params {
+		javaType := p.javaType
+		if p.list {
+			javaType = "List<" + javaType + ">"
+		}
+		if annotated {
+			list[i] = fmt.Sprintf("@Param(\"%s\") %s %s", p.name, javaType, p.name)
+		} else {
+			list[i] = javaType + " " + p.name
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+// queryParamNames renders the arguments passing the parameters on
+func queryParamNames(params []queryParam) string {
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = p.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// addQueryParamImports imports the types of the parameters
+func addQueryParamImports(src string, params []queryParam) string {
+	for _, p := range params {
+		if p.list {
+			src = addJavaImport(src, "java.util.List")
+		}
+		if fqcn, ok := javaTypeImport[p.javaType]; ok {
+			src = addJavaImport(src, fqcn)
+		}
+	}
+	return src
+}
+
+func queryReturnType(kind queryKind, element string) string {
+	switch kind {
+	case queryCount:
+		return "long"
+	case queryExists:
+		return "boolean"
+	}
+	return "List<" + element + ">"
+}
+
+func addQueryToMapper(src, entityClass, method string, kind queryKind, params []queryParam, description string) string {
+	decl := fmt.Sprintf("    /**\n     * %s\n     */\n    %s %s(%s);\n",
+		description, queryReturnType(kind, entityClass+"DO"), method, queryParamList(params, true))
+	src = appendJavaMethod(src, decl)
+	// An interface without methods is declared as "{\n}", keep the blank
+	// line after its opening brace only
+	src = strings.Replace(src, "{\n\n\n", "{\n\n", 1)
+	if kind == queryList {
+		src = addJavaImport(src, "java.util.List")
+	}
+	if len(params) > 0 {
+		src = addJavaImport(src, "org.apache.ibatis.annotations.Param")
+	}
+	return addQueryParamImports(src, params)
+}
+
+func addQueryToRepository(src, entityClass, method string, kind queryKind, params []queryParam, description string) string {
+	decl := fmt.Sprintf("    /**\n     * %s\n     */\n    %s %s(%s);\n",
+		description, queryReturnType(kind, entityClass), method, queryParamList(params, false))
+	src = appendJavaMethod(src, decl)
+	if kind == queryList {
+		src = addJavaImport(src, "java.util.List")
+	}
+	return addQueryParamImports(src, params)
+}
+
+func addQueryToRepositoryImpl(src, entityClass, method string, kind queryKind, params []queryParam) string {
+	mapper := lowerFirst(entityClass) + "Mapper"
+	call := fmt.Sprintf("%s.%s(%s)", mapper, method, queryParamNames(params))
+	body := "        return " + call + ";\n"
+	if kind == queryList {
+		body = fmt.Sprintf("        return %s.stream()\n                .map(this::toEntity)\n                .collect(Collectors.toList());\n", call)
+	}
+	impl := fmt.Sprintf("    @Override\n    public %s %s(%s) {\n%s    }\n",
+		queryReturnType(kind, entityClass), method, queryParamList(params, false), body)
+
+	// Public methods go before the private conversion helpers
+	src = insertBeforePrivateMethods(src, impl)
+	if kind == queryList {
+		src = addJavaImport(src, "java.util.List")
+		src = addJavaImport(src, "java.util.stream.Collectors")
+	}
+	return addQueryParamImports(src, params)
+}
+
+// addQueryToCachingRepository delegates the query to the repository
+// implementation without caching its result
+func addQueryToCachingRepository(src, entityClass, method string, kind queryKind, params []queryParam) string {
+	impl := fmt.Sprintf("    @Override\n    public %s %s(%s) {\n        return delegate.%s(%s);\n    }\n",
+		queryReturnType(kind, entityClass), method, queryParamList(params, false), method, queryParamNames(params))
+	src = insertBeforePrivateMethods(src, impl)
+	if kind == queryList {
+		src = addJavaImport(src, "java.util.List")
+	}
+	return addQueryParamImports(src, params)
+}
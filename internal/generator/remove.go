@@ -0,0 +1,266 @@
+package generator
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/gradle"
+	"github.com/phixia/phjvgen/internal/pom"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// RemoveModuleOptions holds the options for removing an application module
+type RemoveModuleOptions struct {
+	// DryRun only prints the planned changes
+	DryRun bool
+}
+
+// buildFileChange is a pending rewrite of a POM or Gradle build file
+type buildFileChange struct {
+	path     string
+	original []byte
+	updated  []byte
+	summary  []string
+}
+
+// RemoveApplicationModule removes an application module and every POM
+// reference to it. It refuses to run while other modules still use the
+// module's package.
+func RemoveApplicationModule(moduleName string, opts RemoveModuleOptions) error {
+	moduleName = strings.TrimPrefix(moduleName, "application-")
+	if !validateModuleName(moduleName) {
+		return fmt.Errorf("模块名称格式不正确，请使用小写字母和连字符")
+	}
+	artifactID := "application-" + moduleName
+
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml或settings.gradle.kts的项目目录或其子目录中运行此命令", err)
+	}
+
+	utils.PrintInfo(fmt.Sprintf("项目根目录: %s", projectRoot))
+
+	config, err := extractProjectInfo(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	moduleDir := filepath.Join(projectRoot, "application", artifactID)
+	if !utils.DirExists(moduleDir) {
+		return fmt.Errorf("模块 %s 不存在", artifactID)
+	}
+
+	// Refuse while other modules still depend on the module's code
+	modulePackage := config.PackageName + ".application." + strings.ReplaceAll(moduleName, "-", "")
+	utils.PrintInfo(fmt.Sprintf("检查对 %s 的引用...", modulePackage))
+	refs, err := findPackageReferences(projectRoot, moduleDir, modulePackage)
+	if err != nil {
+		return err
+	}
+	if len(refs) > 0 {
+		utils.PrintError(fmt.Sprintf("以下文件仍在引用 %s：", modulePackage))
+		for _, ref := range refs {
+			fmt.Printf("  - %s\n", relPath(projectRoot, ref))
+		}
+		return fmt.Errorf("模块 %s 仍被 %d 个文件引用，请先移除这些引用", artifactID, len(refs))
+	}
+	utils.PrintSuccess("没有其他模块引用该模块")
+
+	changes, err := planModuleRemoval(projectRoot, moduleDir, "application/"+artifactID, artifactID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	utils.PrintInfo("将执行以下变更：")
+	fmt.Printf("  - 删除目录 %s\n", relPath(projectRoot, moduleDir))
+	for _, c := range changes {
+		for _, line := range c.summary {
+			fmt.Printf("  - %s: %s\n", relPath(projectRoot, c.path), line)
+		}
+	}
+	fmt.Println()
+
+	if opts.DryRun {
+		utils.PrintWarning("--dry-run 模式，未做任何修改")
+		return nil
+	}
+
+	confirm, err := utils.ReadInput("确认删除？(y/n): ")
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(confirm, "y") && !strings.EqualFold(confirm, "yes") {
+		utils.PrintWarning("已取消操作")
+		return nil
+	}
+
+	if err := applyModuleRemoval(moduleDir, changes); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	utils.PrintSuccess("==========================================")
+	utils.PrintSuccess(fmt.Sprintf("模块 %s 已删除！", artifactID))
+	utils.PrintSuccess("==========================================")
+	fmt.Println()
+	utils.PrintInfo("下一步：")
+	fmt.Printf("  重新构建项目: %s\n", config.BuildCommand())
+	fmt.Println()
+	return nil
+}
+
+// findPackageReferences returns the Java files outside excludeDir that
+// reference the given package
+func findPackageReferences(projectRoot, excludeDir, pkg string) ([]string, error) {
+	re := regexp.MustCompile(`\b` + regexp.QuoteMeta(pkg) + `\b`)
+
+	var refs []string
+	err := filepath.WalkDir(projectRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == excludeDir || isIgnoredDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".java") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if re.Match(content) {
+			refs = append(refs, path)
+		}
+		return nil
+	})
+
+	sort.Strings(refs)
+	return refs, err
+}
+
+// isIgnoredDir reports whether a directory never contains project sources
+func isIgnoredDir(name string) bool {
+	return name == "target" || name == "build" || name == "node_modules" || (strings.HasPrefix(name, ".") && name != ".")
+}
+
+// planModuleRemoval computes the build file rewrites needed to drop a module
+func planModuleRemoval(projectRoot, moduleDir, modulePath, artifactID string) ([]*buildFileChange, error) {
+	var changes []*buildFileChange
+	err := filepath.WalkDir(projectRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == moduleDir || isIgnoredDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		var plan func([]byte) ([]byte, []string, error)
+		switch d.Name() {
+		case "pom.xml":
+			plan = func(content []byte) ([]byte, []string, error) {
+				return planPOMRemoval(content, modulePath, artifactID)
+			}
+		case gradle.SettingsFile, gradle.BuildFile:
+			plan = func(content []byte) ([]byte, []string, error) {
+				updated, summary := planGradleRemoval(content, artifactID)
+				return updated, summary, nil
+			}
+		default:
+			return nil
+		}
+
+		original, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		updated, summary, err := plan(original)
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", path, err)
+		}
+		if len(summary) > 0 {
+			changes = append(changes, &buildFileChange{path: path, original: original, updated: updated, summary: summary})
+		}
+		return nil
+	})
+	return changes, err
+}
+
+// planPOMRemoval removes the module declaration and every dependency on the
+// module from a POM
+func planPOMRemoval(content []byte, modulePath, artifactID string) ([]byte, []string, error) {
+	doc, err := pom.Parse(content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var summary []string
+	removedModule, err := doc.RemoveModule(modulePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if removedModule {
+		summary = append(summary, fmt.Sprintf("移除 <module>%s</module>", modulePath))
+	}
+	n, err := doc.RemoveAllDependencies("", artifactID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n > 0 {
+		summary = append(summary, fmt.Sprintf("移除 %d 处 %s 依赖", n, artifactID))
+	}
+	return doc.Bytes(), summary, nil
+}
+
+// planGradleRemoval removes the module(...) declaration and every
+// project(":name") dependency from a Gradle script
+func planGradleRemoval(content []byte, artifactID string) ([]byte, []string) {
+	f := gradle.Parse(string(content))
+	var summary []string
+	if f.RemoveModule(artifactID) {
+		summary = append(summary, fmt.Sprintf("移除 module(\"%s\")", artifactID))
+	}
+	if n := f.RemoveProjectDependency(artifactID); n > 0 {
+		summary = append(summary, fmt.Sprintf("移除 %d 处 %s 依赖", n, artifactID))
+	}
+	return []byte(f.String()), summary
+}
+
+// applyModuleRemoval deletes the module directory and writes the build file changes
+// as one unit: if any step fails, everything written so far is rolled back
+func applyModuleRemoval(moduleDir string, changes []*buildFileChange) error {
+	backupDir := filepath.Join(filepath.Dir(moduleDir), fmt.Sprintf(".%s.removing-%d", filepath.Base(moduleDir), os.Getpid()))
+	if err := os.Rename(moduleDir, backupDir); err != nil {
+		return fmt.Errorf("无法移动模块目录: %w", err)
+	}
+
+	for i, c := range changes {
+		if err := os.WriteFile(c.path, c.updated, 0644); err != nil {
+			for _, done := range changes[:i] {
+				_ = os.WriteFile(done.path, done.original, 0644)
+			}
+			_ = os.Rename(backupDir, moduleDir)
+			return fmt.Errorf("更新 %s 失败，已回滚全部修改: %w", c.path, err)
+		}
+		utils.PrintSuccess(fmt.Sprintf("已更新 %s", c.path))
+	}
+
+	if err := os.RemoveAll(backupDir); err != nil {
+		utils.PrintWarning(fmt.Sprintf("POM 已更新，但临时目录删除失败，请手动删除: %s", backupDir))
+		return nil
+	}
+	utils.PrintSuccess(fmt.Sprintf("已删除 %s", moduleDir))
+	return nil
+}
@@ -0,0 +1,380 @@
+package generator
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/gradle"
+	"github.com/phixia/phjvgen/internal/pom"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// RenameOptions holds the options for the rename commands
+type RenameOptions struct {
+	// DryRun only prints the planned changes
+	DryRun bool
+}
+
+// renamePlan is the set of file rewrites and directory moves of a rename
+type renamePlan struct {
+	root         string
+	buildCommand string
+	edits        []*fileEdit
+	moves        []dirMove
+}
+
+type fileEdit struct {
+	path    string
+	content string
+}
+
+type dirMove struct {
+	from string
+	to   string
+}
+
+var packageNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+
+// RenameModule renames an application module: its directory, Java package,
+// artifactId and every reference to them across the project
+func RenameModule(oldName, newName string, opts RenameOptions) error {
+	oldName = strings.TrimPrefix(oldName, "application-")
+	newName = strings.TrimPrefix(newName, "application-")
+	if !validateModuleName(oldName) || !validateModuleName(newName) {
+		return fmt.Errorf("模块名称格式不正确，请使用小写字母和连字符")
+	}
+	if oldName == newName {
+		return fmt.Errorf("新旧模块名称相同")
+	}
+
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml或settings.gradle.kts的项目目录或其子目录中运行此命令", err)
+	}
+
+	config, err := extractProjectInfo(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	oldArtifact, newArtifact := "application-"+oldName, "application-"+newName
+	oldDir := filepath.Join(projectRoot, "application", oldArtifact)
+	newDir := filepath.Join(projectRoot, "application", newArtifact)
+	if !utils.DirExists(oldDir) {
+		return fmt.Errorf("模块 %s 不存在", oldArtifact)
+	}
+	if utils.DirExists(newDir) {
+		return fmt.Errorf("模块 %s 已存在", newArtifact)
+	}
+
+	oldPkgSegment := strings.ReplaceAll(oldName, "-", "")
+	newPkgSegment := strings.ReplaceAll(newName, "-", "")
+	oldPkg := config.PackageName + ".application." + oldPkgSegment
+	newPkg := config.PackageName + ".application." + newPkgSegment
+
+	plan := &renamePlan{root: projectRoot, buildCommand: config.BuildCommand()}
+
+	// Source rewrites: package/import statements, @MapperScan, mapper XML, YAML keys
+	if err := plan.rewritePackageReferences(oldPkg, newPkg); err != nil {
+		return err
+	}
+
+	// POM rewrites: artifactId, name and module path
+	if err := plan.rewritePOMs(func(doc *pom.Document) error {
+		if _, err := doc.ReplaceText(isElement("artifactId"), oldArtifact, newArtifact); err != nil {
+			return err
+		}
+		if _, err := doc.ReplaceText(isElement("name"), oldArtifact, newArtifact); err != nil {
+			return err
+		}
+		_, err := doc.ReplaceText(isElement("module"), "application/"+oldArtifact, "application/"+newArtifact)
+		return err
+	}); err != nil {
+		return err
+	}
+	if err := plan.rewriteGradle(func(f *gradle.File) {
+		f.RenameModule(oldArtifact, newArtifact, "application/"+newArtifact)
+		f.RenameProjectReferences(oldArtifact, newArtifact)
+	}); err != nil {
+		return err
+	}
+
+	// Directory moves: the Java package directories first, then the module itself
+	if oldPkgSegment != newPkgSegment {
+		for _, srcDir := range []string{"src/main/java", "src/test/java"} {
+			from := filepath.Join(oldDir, srcDir, config.PackagePath, "application", oldPkgSegment)
+			if utils.DirExists(from) {
+				plan.moves = append(plan.moves, dirMove{from, filepath.Join(oldDir, srcDir, config.PackagePath, "application", newPkgSegment)})
+			}
+		}
+	}
+	plan.moves = append(plan.moves, dirMove{oldDir, newDir})
+
+	return plan.run(fmt.Sprintf("重命名模块 %s → %s", oldArtifact, newArtifact), opts)
+}
+
+// RenamePackage moves the Java package oldPkg to newPkg in every module and
+// rewrites all references, including the groupId when it matches the package
+func RenamePackage(oldPkg, newPkg string, opts RenameOptions) error {
+	if !packageNameRe.MatchString(oldPkg) || !packageNameRe.MatchString(newPkg) {
+		return fmt.Errorf("包名格式不正确，请使用类似 com.mycompany.app 的格式")
+	}
+	if oldPkg == newPkg {
+		return fmt.Errorf("新旧包名相同")
+	}
+
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml或settings.gradle.kts的项目目录或其子目录中运行此命令", err)
+	}
+
+	config, err := extractProjectInfo(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	plan := &renamePlan{root: projectRoot, buildCommand: config.BuildCommand()}
+
+	if err := plan.rewritePackageReferences(oldPkg, newPkg); err != nil {
+		return err
+	}
+
+	// The project package is derived from the groupId, so they move together
+	if config.GroupID == oldPkg {
+		if err := plan.rewritePOMs(func(doc *pom.Document) error {
+			_, err := doc.ReplaceText(isElement("groupId"), oldPkg, newPkg)
+			return err
+		}); err != nil {
+			return err
+		}
+		if err := plan.rewriteGradle(func(f *gradle.File) {
+			if f.Group() == oldPkg {
+				f.SetGroup(newPkg)
+			}
+		}); err != nil {
+			return err
+		}
+	}
+
+	oldPath := filepath.FromSlash(strings.ReplaceAll(oldPkg, ".", "/"))
+	newPath := filepath.FromSlash(strings.ReplaceAll(newPkg, ".", "/"))
+	javaRoots, err := findJavaSourceRoots(projectRoot)
+	if err != nil {
+		return err
+	}
+	for _, javaRoot := range javaRoots {
+		if from := filepath.Join(javaRoot, oldPath); utils.DirExists(from) {
+			plan.moves = append(plan.moves, dirMove{from, filepath.Join(javaRoot, newPath)})
+		}
+	}
+
+	if len(plan.edits) == 0 && len(plan.moves) == 0 {
+		return fmt.Errorf("项目中没有找到包 %s", oldPkg)
+	}
+
+	return plan.run(fmt.Sprintf("移动包 %s → %s", oldPkg, newPkg), opts)
+}
+
+// rewritePackageReferences rewrites references to oldPkg in Java sources,
+// XML resources and YAML/properties configuration, including the
+// application-local.yml.example template
+func (p *renamePlan) rewritePackageReferences(oldPkg, newPkg string) error {
+	re := regexp.MustCompile(`(^|[^\w.])` + regexp.QuoteMeta(oldPkg) + `\b`)
+
+	return p.walkFiles(func(path string) bool {
+		ext := filepath.Ext(strings.TrimSuffix(path, ".example"))
+		return ext == ".java" || ext == ".yml" || ext == ".yaml" || ext == ".properties" ||
+			(ext == ".xml" && filepath.Base(path) != "pom.xml")
+	}, func(content string) (string, error) {
+		return re.ReplaceAllString(content, "${1}"+newPkg), nil
+	})
+}
+
+// rewritePOMs applies a structural rewrite to every pom.xml in the project
+func (p *renamePlan) rewritePOMs(rewrite func(*pom.Document) error) error {
+	return p.walkFiles(func(path string) bool {
+		return filepath.Base(path) == "pom.xml"
+	}, func(content string) (string, error) {
+		doc, err := pom.Parse([]byte(content))
+		if err != nil {
+			return "", err
+		}
+		if err := rewrite(doc); err != nil {
+			return "", err
+		}
+		return string(doc.Bytes()), nil
+	})
+}
+
+// rewriteGradle applies a rewrite to every Gradle script in the project
+func (p *renamePlan) rewriteGradle(rewrite func(*gradle.File)) error {
+	return p.walkFiles(func(path string) bool {
+		name := filepath.Base(path)
+		return name == gradle.SettingsFile || name == gradle.BuildFile
+	}, func(content string) (string, error) {
+		f := gradle.Parse(content)
+		rewrite(f)
+		return f.String(), nil
+	})
+}
+
+// isElement matches POM elements by name
+func isElement(name string) func(*pom.Element) bool {
+	return func(e *pom.Element) bool { return e.Name == name }
+}
+
+// walkFiles applies rewrite to the matching files and records the files it
+// changed. A file rewritten twice keeps both changes.
+func (p *renamePlan) walkFiles(match func(string) bool, rewrite func(string) (string, error)) error {
+	return filepath.WalkDir(p.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if isIgnoredDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !match(path) {
+			return nil
+		}
+
+		edit := p.edit(path)
+		if edit == nil {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			edit = &fileEdit{path: path, content: string(content)}
+			updated, err := rewrite(edit.content)
+			if err != nil {
+				return fmt.Errorf("failed to rewrite %s: %w", path, err)
+			}
+			if updated != edit.content {
+				edit.content = updated
+				p.edits = append(p.edits, edit)
+			}
+			return nil
+		}
+		updated, err := rewrite(edit.content)
+		if err != nil {
+			return fmt.Errorf("failed to rewrite %s: %w", path, err)
+		}
+		edit.content = updated
+		return nil
+	})
+}
+
+func (p *renamePlan) edit(path string) *fileEdit {
+	for _, e := range p.edits {
+		if e.path == path {
+			return e
+		}
+	}
+	return nil
+}
+
+// run prints the plan and, unless in dry-run mode, applies it
+func (p *renamePlan) run(title string, opts RenameOptions) error {
+	sort.Slice(p.edits, func(i, j int) bool { return p.edits[i].path < p.edits[j].path })
+
+	fmt.Println()
+	utils.PrintInfo(title)
+	utils.PrintInfo(fmt.Sprintf("将修改 %d 个文件，移动 %d 个目录：", len(p.edits), len(p.moves)))
+	for _, e := range p.edits {
+		fmt.Printf("  M %s\n", relPath(p.root, e.path))
+	}
+	for _, m := range p.moves {
+		fmt.Printf("  R %s → %s\n", relPath(p.root, m.from), relPath(p.root, m.to))
+	}
+	fmt.Println()
+
+	if opts.DryRun {
+		utils.PrintWarning("--dry-run 模式，未做任何修改")
+		return nil
+	}
+
+	confirm, err := utils.ReadInput("确认执行？(y/n): ")
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(confirm, "y") && !strings.EqualFold(confirm, "yes") {
+		utils.PrintWarning("已取消操作")
+		return nil
+	}
+
+	for _, e := range p.edits {
+		if err := utils.WriteFile(e.path, e.content); err != nil {
+			return err
+		}
+	}
+	for _, m := range p.moves {
+		if err := moveDir(m.from, m.to); err != nil {
+			return err
+		}
+	}
+
+	utils.PrintSuccess("==========================================")
+	utils.PrintSuccess(fmt.Sprintf("%s 完成！", title))
+	utils.PrintSuccess("==========================================")
+	fmt.Println()
+	utils.PrintInfo("下一步：")
+	fmt.Printf("  重新构建项目: %s\n", p.buildCommand)
+	fmt.Println()
+	return nil
+}
+
+// moveDir moves a directory, also when the target is nested inside the
+// source, and prunes the source parents that were left empty
+func moveDir(from, to string) error {
+	if utils.DirExists(to) {
+		return fmt.Errorf("目标目录已存在: %s", to)
+	}
+	tmp := filepath.Join(filepath.Dir(from), fmt.Sprintf(".phjvgen-move-%d", os.Getpid()))
+	if err := os.Rename(from, tmp); err != nil {
+		return fmt.Errorf("failed to move %s: %w", from, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(to), err)
+	}
+	if err := os.Rename(tmp, to); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", from, to, err)
+	}
+
+	for dir := filepath.Dir(from); ; dir = filepath.Dir(dir) {
+		if filepath.Base(dir) == "java" || os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// findJavaSourceRoots returns every src/main/java and src/test/java directory
+func findJavaSourceRoots(projectRoot string) ([]string, error) {
+	var roots []string
+	err := filepath.WalkDir(projectRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if isIgnoredDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		if d.Name() == "java" {
+			if parent := filepath.Base(filepath.Dir(path)); parent == "main" || parent == "test" {
+				roots = append(roots, path)
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	return roots, err
+}
@@ -0,0 +1,177 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/schema"
+	"github.com/phixia/phjvgen/internal/seed"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// seedDir holds the generated seed scripts, relative to the project root.
+// It is outside db/migration, so that only the profiles listing it in
+// spring.flyway.locations run them.
+const seedDir = "infrastructure/src/main/resources/db/seed"
+
+// seedBatchSize is the number of rows per INSERT statement
+const seedBatchSize = 100
+
+// SeedOptions holds the options for generating seed data
+type SeedOptions struct {
+	Count int
+	Seed  int64
+	// Flyway writes a repeatable migration run by the Flyway of Profile
+	Flyway  bool
+	Profile string
+	// Output is the script to write instead of db/seed/seed_<table>.sql
+	Output string
+}
+
+// GenerateSeed writes a script inserting fake rows into the table of an
+// entity of the project manifest, and into the tables it references. The
+// rows depend only on the tables, the count and the seed.
+func GenerateSeed(entity string, opts SeedOptions) error {
+	if opts.Count <= 0 {
+		return fmt.Errorf("--count 必须大于 0")
+	}
+	if opts.Flyway && opts.Output != "" {
+		return fmt.Errorf("--flyway 与 --output 不能同时使用")
+	}
+
+	projectRoot, config, err := migrationProject()
+	if err != nil {
+		return err
+	}
+	if opts.Flyway && config.migration() != MigrationFlyway {
+		return fmt.Errorf("--flyway 需要项目使用 Flyway，当前项目使用 %s", config.migration())
+	}
+	manifest, err := readManifest(projectRoot)
+	if err != nil {
+		return err
+	}
+	if err := validateEntities(manifest.Entities); err != nil {
+		return err
+	}
+	table, ok := findEntityTable(manifest.Entities, entity)
+	if !ok {
+		return fmt.Errorf("%s 中未找到实体 %s 对应的表", manifestFile, entity)
+	}
+
+	tables, err := seed.Generate(manifest.Entities, table.Name, opts.Count, opts.Seed)
+	if err != nil {
+		return fmt.Errorf("生成数据失败: %w", err)
+	}
+
+	path := opts.Output
+	switch {
+	case opts.Flyway:
+		path = filepath.Join(projectRoot, seedDir, "R__seed_"+table.Name+".sql")
+	case path == "":
+		path = filepath.Join(projectRoot, seedDir, "seed_"+table.Name+".sql")
+	}
+	if err := utils.WriteFile(path, seedScript(config.dialect(), tables, opts)); err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(projectRoot, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = path
+	}
+	utils.PrintSuccess(fmt.Sprintf("已生成 %s", filepath.ToSlash(rel)))
+	for _, t := range tables {
+		fmt.Printf("  - %s: %d 行\n", t.Table.Name, len(t.Values))
+	}
+
+	fmt.Println()
+	if !opts.Flyway {
+		utils.PrintInfo("在开发或测试数据库中手动执行该脚本即可导入数据")
+		return nil
+	}
+	return addSeedLocation(projectRoot, opts.Profile)
+}
+
+// findEntityTable finds the table of an entity name such as User or
+// order-item, ignoring case, underscores and the t_ prefix
+func findEntityTable(tables []schema.Table, entity string) (schema.Table, bool) {
+	normalize := func(name string) string {
+		return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	}
+	for _, t := range tables {
+		if normalize(strings.TrimPrefix(t.Name, "t_")) == normalize(entity) || t.Name == entity {
+			return t, true
+		}
+	}
+	return schema.Table{}, false
+}
+
+// seedScript renders the generated rows. Rows numbered like the new ones,
+// or holding the generated keys when the key is not numbered from 1, are
+// deleted first, children before parents, so that the script can be run
+// again; identity columns then continue after the inserted keys.
+func seedScript(dialect schema.Dialect, tables []seed.Rows, opts SeedOptions) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- 由 phjvgen seed 根据 %s 生成（--count %d --seed %d），请勿手动修改\n", manifestFile, opts.Count, opts.Seed)
+	if opts.Flyway {
+		b.WriteString("-- Flyway 可重复迁移：内容变化后会在列出 db/seed 的环境中重新执行\n")
+	}
+
+	var deletes []string
+	for i := len(tables) - 1; i >= 0; i-- {
+		t := tables[i]
+		if key, ok := seed.SerialKey(t.Table); ok {
+			deletes = append(deletes, dialect.DeleteRange(t.Table, key.Name, 1, int64(len(t.Values))))
+			continue
+		}
+		// Keys such as UUIDs are the same for the same seed, delete them by value
+		keys := t.PrimaryKeys()
+		for start := 0; start < len(keys); start += seedBatchSize {
+			end := min(start+seedBatchSize, len(keys))
+			deletes = append(deletes, dialect.DeleteKeys(t.Table, t.Table.PrimaryKey, keys[start:end]))
+		}
+	}
+	if len(deletes) > 0 {
+		b.WriteString("\n" + strings.Join(deletes, ""))
+	}
+
+	for _, t := range tables {
+		fmt.Fprintf(&b, "\n-- %s\n", t.Table.Name)
+		for start := 0; start < len(t.Values); start += seedBatchSize {
+			end := min(start+seedBatchSize, len(t.Values))
+			b.WriteString(dialect.Insert(t.Table, t.Columns, t.Values[start:end]))
+		}
+		if key, ok := seed.SerialKey(t.Table); ok && key.AutoIncrement {
+			b.WriteString(dialect.RestartIdentity(t.Table, key.Name, int64(len(t.Values))+1))
+		}
+	}
+	return b.String()
+}
+
+// addSeedLocation makes the Flyway of a profile run the scripts in db/seed
+func addSeedLocation(projectRoot, profile string) error {
+	file := filepath.Join(projectRoot, "starter/src/main/resources", "application-"+profile+".yml")
+	content, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("未找到 profile 配置文件 %s", filepath.Base(file))
+	}
+	if err != nil {
+		return err
+	}
+
+	location := "classpath:" + strings.TrimPrefix(seedDir, "infrastructure/src/main/resources/")
+	path := []string{"spring", "flyway", "locations"}
+	if yamlHasKey(string(content), path) {
+		if !strings.Contains(yamlValue(string(content), path), location) {
+			utils.PrintWarning(fmt.Sprintf("%s 已配置 spring.flyway.locations，请手动加入 %s", filepath.Base(file), location))
+		}
+		return nil
+	}
+	entry := fmt.Sprintf("    # 仅在该环境执行 db/seed 下的测试数据\n    locations: classpath:db/migration,%s\n", location)
+	if err := utils.WriteFile(file, insertYAMLEntry(string(content), path[:2], entry)); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("%s 已将 %s 加入 spring.flyway.locations", filepath.Base(file), location))
+	utils.PrintInfo(fmt.Sprintf("以 %s profile 启动应用时，Flyway 会导入这些数据", profile))
+	return nil
+}
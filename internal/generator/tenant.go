@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/phixia/phjvgen/internal/schema"
+	"github.com/phixia/phjvgen/internal/templates"
+)
+
+// tenantColumn isolates the rows of each tenant in a multi-tenant project
+var tenantColumn = schema.Column{Name: "tenant_id", Type: schema.Varchar, Length: 64, NotNull: true, Comment: "租户ID"}
+
+// ValidateMultiTenant checks that the persistence stack supports
+// --multi-tenant, which relies on the MyBatis-Plus tenant line interceptor
+func ValidateMultiTenant(persistence string) error {
+	if persistence != PersistenceMybatisPlus {
+		return fmt.Errorf("--multi-tenant 目前仅支持 MyBatis-Plus 持久层")
+	}
+	return nil
+}
+
+// tenantTable returns a scaffolded table as the project creates it: in a
+// multi-tenant project the tenant column follows the primary key, and
+// unique indexes are unique within a tenant.
+func tenantTable(config *ProjectConfig, t schema.Table) schema.Table {
+	if !config.MultiTenant {
+		return t
+	}
+	t.Columns = slices.Clone(t.Columns)
+	at := 0
+	for i, c := range t.Columns {
+		if slices.Contains(t.PrimaryKey, c.Name) {
+			at = i + 1
+		}
+	}
+	t.Columns = slices.Insert(t.Columns, at, tenantColumn)
+
+	// A unique index led by the tenant column also serves tenant lookups
+	t.Indexes = slices.Clone(t.Indexes)
+	indexed := false
+	for i, idx := range t.Indexes {
+		if idx.Unique {
+			t.Indexes[i].Columns = append([]string{tenantColumn.Name}, idx.Columns...)
+			indexed = true
+		}
+	}
+	if !indexed {
+		t.Indexes = append(t.Indexes, schema.Index{Name: "idx_tenant_id", Columns: []string{tenantColumn.Name}})
+	}
+	return t
+}
+
+// tenantFiles returns the files of the tenant context, its request filter
+// and the MyBatis-Plus tenant line handler, relative to the project root
+func tenantFiles(pkgPath string) map[string]string {
+	return map[string]string{
+		filepath.Join("common/src/main/java", pkgPath, "common/tenant/TenantContext.java"):                            templates.TenantContext,
+		filepath.Join("common/src/main/java", pkgPath, "common/tenant/TenantTaskDecorator.java"):                      templates.TenantTaskDecorator,
+		filepath.Join("adapter/adapter-rest/src/main/java", pkgPath, "adapter/rest/filter/TenantFilter.java"):         templates.TenantFilter,
+		filepath.Join("infrastructure/src/main/java", pkgPath, "infrastructure/config/MybatisTenantLineHandler.java"): templates.MybatisTenantLineHandler,
+	}
+}
+
+// detectMultiTenant reports whether an existing project was generated with
+// --multi-tenant, from its tenant context class
+func detectMultiTenant(config *ProjectConfig) bool {
+	path := filepath.Join(config.OutputDir, "common/src/main/java", config.PackagePath, "common/tenant/TenantContext.java")
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// tenantReplacements returns the template placeholders of multi-tenancy,
+// all empty in a single-tenant project
+func (c *ProjectConfig) tenantReplacements() map[string]string {
+	replacements := map[string]string{
+		"{{TENANT_DO_FIELD}}":            "",
+		"{{TENANT_INTERCEPTOR_IMPORTS}}": "",
+		"{{TENANT_INTERCEPTOR_DOC}}":     "",
+		"{{TENANT_INTERCEPTOR_PARAM}}":   "",
+		"{{TENANT_INTERCEPTOR}}":         "",
+		"{{TENANT_LISTENER_IMPORT}}":     "",
+		"{{TENANT_LISTENER_LOG}}":        "",
+		"{{TENANT_YML}}":                 "",
+		"{{TENANT_TECH_STACK}}":          "",
+		"{{TENANT_CACHE_IMPORT}}":        "",
+		"{{TENANT_CACHE_KEY}}":           "",
+		"{{TENANT_CACHE_KEY_DOC}}":       "",
+		"{{TENANT_CACHE_KEY_EXAMPLE}}":   "",
+	}
+	if !c.MultiTenant {
+		return replacements
+	}
+	replacements["{{TENANT_DO_FIELD}}"] = `
+    @TableField("tenant_id")
+    private String tenantId;
+`
+	replacements["{{TENANT_INTERCEPTOR_IMPORTS}}"] = `import com.baomidou.mybatisplus.extension.plugins.handler.TenantLineHandler;
+import com.baomidou.mybatisplus.extension.plugins.inner.TenantLineInnerInterceptor;
+`
+	replacements["{{TENANT_INTERCEPTOR_DOC}}"] = `；
+     * 多租户插件，为 SQL 追加当前租户的 tenant_id 条件，见 MybatisTenantLineHandler`
+	replacements["{{TENANT_INTERCEPTOR_PARAM}}"] = "TenantLineHandler tenantLineHandler"
+	replacements["{{TENANT_INTERCEPTOR}}"] = `        // 多租户插件需要在分页插件之前，分页的 COUNT 语句才会带上租户条件
+        interceptor.addInnerInterceptor(new TenantLineInnerInterceptor(tenantLineHandler));
+`
+	replacements["{{TENANT_LISTENER_IMPORT}}"] = fmt.Sprintf("import %s.common.tenant.TenantContext;\n", c.PackageName)
+	replacements["{{TENANT_LISTENER_LOG}}"] = `        // 租户由 TenantTaskDecorator 从发布事件的请求线程传入，这里访问数据同样按租户隔离
+        log.info("租户ID: {}", TenantContext.getTenantId());
+`
+	replacements["{{TENANT_YML}}"] = `
+app:
+  tenant:
+    # 租户请求头，只能由网关在认证之后设置，网关需要剔除外部请求自带的该请求头
+    header: X-Tenant-Id
+    # 改为从令牌（JWT）的该声明读取租户，例如 tenant_id。不校验令牌签名，
+    # 只有网关或认证过滤器已经校验过令牌时才能配置
+    claim:
+    # 不按租户隔离的表，逗号分隔，例如各租户共用的字典表
+    ignore-tables:
+`
+	// Cached rows of different tenants never share a key
+	replacements["{{TENANT_CACHE_IMPORT}}"] = fmt.Sprintf("import %s.common.tenant.TenantContext;\n", c.PackageName)
+	replacements["{{TENANT_CACHE_KEY}}"] = `TenantContext.requireTenantId() + ":" + `
+	replacements["{{TENANT_CACHE_KEY_DOC}}"] = "<租户>:"
+	replacements["{{TENANT_CACHE_KEY_EXAMPLE}}"] = "tenant_1:"
+	replacements["{{TENANT_TECH_STACK}}"] = "- 多租户：tenant_id 行级隔离（MyBatis-Plus TenantLineInnerInterceptor）\n"
+	return replacements
+}
@@ -0,0 +1,359 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/catalog"
+	"github.com/phixia/phjvgen/internal/gradle"
+	"github.com/phixia/phjvgen/internal/pom"
+	"github.com/phixia/phjvgen/internal/templates"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// UpgradeOptions holds the options for upgrading dependency versions
+type UpgradeOptions struct {
+	// Only limits the upgrade to these properties, e.g. lombok.version
+	Only []string
+	// DryRun only prints the table and the diff
+	DryRun bool
+	// Yes applies the upgrades without asking
+	Yes bool
+}
+
+// versionNotes are compatibility notes shown next to a recommended version
+var versionNotes = map[string]string{
+	"spring-boot.version":  "升级后请确认 MyBatis-Plus、Redisson 等 Starter 支持该 Spring Boot 版本",
+	"mybatis-plus.version": "需与 Spring Boot 主版本匹配（mybatis-plus-spring-boot3-starter）",
+	"redisson.version":     "redisson-spring-boot-starter 需与 Spring Boot 版本匹配",
+	"lombok.version":       "需支持项目使用的 Java 版本",
+	"mapstruct.version":    "mapstruct 与 mapstruct-processor 共用此版本",
+	"springdoc.version":    "2.x 对应 Spring Boot 3 及以上",
+}
+
+// versionUpgrade is a row of the upgrade table
+type versionUpgrade struct {
+	property    string
+	key         string // the property, or the version catalog name for Gradle
+	current     string
+	recommended string
+	note        string
+}
+
+// UpgradeVersions compares the project's versions with the versions shipped
+// in this phjvgen binary and applies the selected upgrades
+func UpgradeVersions(opts UpgradeOptions) error {
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml或settings.gradle.kts的项目目录或其子目录中运行此命令", err)
+	}
+	config, err := extractProjectInfo(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	recommended, err := recommendedVersions()
+	if err != nil {
+		return err
+	}
+
+	// The versions are declared in the parent POM properties, or in the
+	// [versions] section of the version catalog for Gradle
+	versionsPath := filepath.Join(projectRoot, "pom.xml")
+	if config.isGradle() {
+		versionsPath = filepath.Join(projectRoot, gradle.CatalogFile)
+	}
+	original, current, err := readProjectVersions(config, versionsPath)
+	if err != nil {
+		return err
+	}
+
+	var rows, upgrades []versionUpgrade
+	for _, rec := range recommended {
+		key := rec[0]
+		if config.isGradle() {
+			key = strings.TrimSuffix(key, ".version")
+		}
+		value, ok := current[key]
+		if !ok || strings.Contains(value, "${") {
+			continue
+		}
+		row := versionUpgrade{property: rec[0], key: key, current: value, recommended: rec[1], note: versionNotes[rec[0]]}
+		if compareVersions(value, rec[1]) < 0 {
+			if versionMajor(value) != versionMajor(rec[1]) {
+				row.note = strings.TrimSpace("主版本升级，可能包含不兼容变更。" + row.note)
+			}
+			upgrades = append(upgrades, row)
+		}
+		rows = append(rows, row)
+	}
+
+	printUpgradeTable(rows, upgrades)
+	if len(upgrades) == 0 {
+		utils.PrintSuccess("所有版本均已是推荐版本")
+		return nil
+	}
+
+	selected, err := selectUpgrades(upgrades, opts)
+	if err != nil || len(selected) == 0 {
+		return err
+	}
+
+	updated, err := applyVersionUpgrades(config, original, selected)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	utils.PrintInfo("将执行以下变更：")
+	printDiff(relPath(projectRoot, versionsPath), string(original), string(updated))
+	fmt.Println()
+
+	if opts.DryRun {
+		utils.PrintWarning("--dry-run 模式，未做任何修改")
+		return nil
+	}
+	if !opts.Yes {
+		confirm, err := utils.ReadInput("确认升级？(y/n): ")
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(confirm, "y") && !strings.EqualFold(confirm, "yes") {
+			utils.PrintWarning("已取消操作")
+			return nil
+		}
+	}
+
+	if err := utils.WriteFile(versionsPath, string(updated)); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("已升级 %d 个版本", len(selected)))
+	fmt.Println()
+	utils.PrintInfo("下一步：")
+	fmt.Printf("  重新构建并运行测试: %s\n", config.BuildCommand())
+	fmt.Println()
+	return nil
+}
+
+// recommendedVersions returns the version properties of the ParentPOM
+// template, the enforcer plugin and the dependency catalog
+func recommendedVersions() ([][2]string, error) {
+	// The JDBC driver of the default database stands in for the driver
+	// placeholders; the other drivers come from the catalog below
+	content := utils.ReplacePlaceholders(templates.ParentPOM, (&ProjectConfig{}).GetReplacements())
+	doc, err := pom.Parse([]byte(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ParentPOM template: %w", err)
+	}
+
+	var versions [][2]string
+	seen := map[string]bool{}
+	for _, prop := range doc.Properties() {
+		// The Java version also drives the compiler settings and the JDK, so it is not bumped here
+		if !strings.HasSuffix(prop[0], ".version") || prop[0] == "java.version" {
+			continue
+		}
+		versions = append(versions, prop)
+		seen[prop[0]] = true
+	}
+	versions = append(versions, [2]string{enforcerVersionProperty, enforcerPluginVersion})
+	seen[enforcerVersionProperty] = true
+	for _, entry := range catalog.All() {
+		if entry.VersionProperty != "" && !seen[entry.VersionProperty] {
+			versions = append(versions, [2]string{entry.VersionProperty, entry.Version})
+			seen[entry.VersionProperty] = true
+		}
+	}
+	return versions, nil
+}
+
+// readProjectVersions reads the file holding the project's versions
+func readProjectVersions(config *ProjectConfig, path string) ([]byte, map[string]string, error) {
+	current := map[string]string{}
+	if config.isGradle() {
+		toml, err := gradle.Load(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, v := range toml.CatalogVersions() {
+			current[v[0]] = v[1]
+		}
+		return []byte(toml.String()), current, nil
+	}
+
+	doc, err := pom.Load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, prop := range doc.Properties() {
+		current[prop[0]] = prop[1]
+	}
+	return doc.Bytes(), current, nil
+}
+
+// applyVersionUpgrades returns the content of the versions file with the upgrades applied
+func applyVersionUpgrades(config *ProjectConfig, content []byte, upgrades []versionUpgrade) ([]byte, error) {
+	if config.isGradle() {
+		toml := gradle.Parse(string(content))
+		for _, u := range upgrades {
+			toml.SetCatalogVersion(u.key, u.recommended)
+		}
+		return []byte(toml.String()), nil
+	}
+
+	doc, err := pom.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range upgrades {
+		if err := doc.SetProperty(u.key, u.recommended); err != nil {
+			return nil, err
+		}
+	}
+	return doc.Bytes(), nil
+}
+
+func printUpgradeTable(rows, upgrades []versionUpgrade) {
+	upgradable := map[string]int{}
+	for i, u := range upgrades {
+		upgradable[u.property] = i + 1
+	}
+
+	fmt.Println()
+	fmt.Printf("  %-4s %-32s %-14s %-14s %s\n", "#", "属性", "当前版本", "推荐版本", "说明")
+	for _, row := range rows {
+		if n, ok := upgradable[row.property]; ok {
+			fmt.Printf("  %-4d %-32s %-14s %s %s\n", n, row.property, row.current,
+				utils.SuccessColor(fmt.Sprintf("%-14s", row.recommended)), row.note)
+			continue
+		}
+		status := "已是推荐版本"
+		if compareVersions(row.current, row.recommended) > 0 {
+			status = "高于推荐版本"
+		}
+		fmt.Printf("  %-4s %-32s %-14s %-14s %s\n", "", row.property, row.current, row.recommended, status)
+	}
+	fmt.Println()
+}
+
+// selectUpgrades picks the upgrades from --only, or asks for their numbers
+func selectUpgrades(upgrades []versionUpgrade, opts UpgradeOptions) ([]versionUpgrade, error) {
+	if len(opts.Only) > 0 {
+		var selected []versionUpgrade
+		for _, name := range opts.Only {
+			found := false
+			for _, u := range upgrades {
+				if u.property == name || u.property == name+".version" {
+					selected = append(selected, u)
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("%s 不在可升级列表中", name)
+			}
+		}
+		return selected, nil
+	}
+	if opts.Yes || opts.DryRun {
+		return upgrades, nil
+	}
+
+	input, err := utils.ReadInput("选择要升级的编号（如 1,3，回车表示全部，n 取消）: ")
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(input, "n") {
+		utils.PrintWarning("已取消操作")
+		return nil, nil
+	}
+	if input == "" {
+		return upgrades, nil
+	}
+
+	var selected []versionUpgrade
+	for _, field := range strings.Split(input, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || n < 1 || n > len(upgrades) {
+			return nil, fmt.Errorf("无效的编号: %s", strings.TrimSpace(field))
+		}
+		selected = append(selected, upgrades[n-1])
+	}
+	return selected, nil
+}
+
+var versionTokenRe = regexp.MustCompile(`\d+|[A-Za-z]+`)
+
+// preReleaseQualifiers sort before the release they lead up to
+var preReleaseQualifiers = map[string]int{
+	"snapshot": 1, "alpha": 2, "a": 2, "beta": 3, "b": 3, "milestone": 4, "m": 4, "rc": 5, "cr": 5,
+}
+
+// compareVersions compares two Maven style versions, treating qualifiers such
+// as RC1 or M2 as earlier than the release: 4.0.0-RC1 < 4.0.0 < 4.0.1
+func compareVersions(a, b string) int {
+	ta := versionTokenRe.FindAllString(strings.ToLower(a), -1)
+	tb := versionTokenRe.FindAllString(strings.ToLower(b), -1)
+	for i := 0; i < len(ta) || i < len(tb); i++ {
+		switch {
+		case i >= len(ta):
+			return -versionTokenSign(tb[i])
+		case i >= len(tb):
+			return versionTokenSign(ta[i])
+		}
+		na, errA := strconv.Atoi(ta[i])
+		nb, errB := strconv.Atoi(tb[i])
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				return compareInts(na, nb)
+			}
+		case errA == nil:
+			return 1
+		case errB == nil:
+			return -1
+		case ta[i] != tb[i]:
+			qa, qb := preReleaseQualifiers[ta[i]], preReleaseQualifiers[tb[i]]
+			if qa != qb {
+				// Unknown qualifiers such as jre or final rank as releases
+				if qa == 0 {
+					qa = len(preReleaseQualifiers)
+				}
+				if qb == 0 {
+					qb = len(preReleaseQualifiers)
+				}
+				return compareInts(qa, qb)
+			}
+			return strings.Compare(ta[i], tb[i])
+		}
+	}
+	return 0
+}
+
+// versionTokenSign tells whether a trailing token makes a version newer (a
+// number) or older (a pre-release qualifier) than the version without it
+func versionTokenSign(token string) int {
+	if _, err := strconv.Atoi(token); err == nil {
+		return 1
+	}
+	if preReleaseQualifiers[token] > 0 {
+		return -1
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func versionMajor(version string) string {
+	return strings.SplitN(version, ".", 2)[0]
+}
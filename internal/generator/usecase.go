@@ -0,0 +1,358 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/templates"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// UseCaseOptions holds the options for generating a use case
+type UseCaseOptions struct {
+	// Name is the use case name in kebab-case, e.g. cancel-order
+	Name string
+	// Module is the application module name without the application- prefix
+	Module string
+	// HTTP is the endpoint in METHOD:/path form, e.g. POST:/api/orders/{id}/cancel
+	HTTP string
+}
+
+// httpEndpoint is a parsed METHOD:/path endpoint specification
+type httpEndpoint struct {
+	Method string
+	Path   string
+}
+
+var httpMappingAnnotations = map[string]string{
+	"GET":    "GetMapping",
+	"POST":   "PostMapping",
+	"PUT":    "PutMapping",
+	"PATCH":  "PatchMapping",
+	"DELETE": "DeleteMapping",
+}
+
+var pathVariableRe = regexp.MustCompile(`\{(\w+)\}`)
+
+// GenerateUseCase generates a Command, an Executor, a delegating service
+// method, a REST endpoint with its request VO and a unit test skeleton
+func GenerateUseCase(opts UseCaseOptions) error {
+	if !validateModuleName(opts.Name) {
+		return fmt.Errorf("用例名称格式不正确，请使用小写字母和连字符，例如: cancel-order")
+	}
+	if !validateModuleName(opts.Module) {
+		return fmt.Errorf("模块名称格式不正确，请使用小写字母和连字符")
+	}
+
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml或settings.gradle.kts的项目目录或其子目录中运行此命令", err)
+	}
+
+	config, err := extractProjectInfo(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	moduleDir := filepath.Join(projectRoot, "application", "application-"+opts.Module)
+	if !utils.DirExists(moduleDir) {
+		return fmt.Errorf("模块 application-%s 不存在，请先运行: phjvgen add %s", opts.Module, opts.Module)
+	}
+
+	if opts.HTTP == "" {
+		opts.HTTP = fmt.Sprintf("POST:/api/%s/%s", opts.Module, opts.Name)
+	}
+	endpoint, err := parseHTTPEndpoint(opts.HTTP)
+	if err != nil {
+		return err
+	}
+
+	useCaseClass := toCamelCase(opts.Name)
+	moduleClass := toCamelCase(opts.Module)
+	modulePackage := strings.ReplaceAll(opts.Module, "-", "")
+	pathVariables := extractPathVariables(endpoint.Path)
+
+	replacements := config.GetReplacements()
+	replacements["{{MODULE_PACKAGE}}"] = modulePackage
+	replacements["{{MODULE_CLASS}}"] = moduleClass
+	replacements["{{MODULE_FIELD}}"] = lowerFirst(moduleClass)
+	replacements["{{USECASE_CLASS}}"] = useCaseClass
+	replacements["{{USECASE_METHOD}}"] = lowerFirst(useCaseClass)
+	replacements["{{COMMAND_FIELDS}}"] = buildCommandFields(pathVariables)
+
+	appJavaDir := filepath.Join(moduleDir, "src/main/java", config.PackagePath, "application", modulePackage)
+	appTestDir := filepath.Join(moduleDir, "src/test/java", config.PackagePath, "application", modulePackage)
+	restJavaDir := filepath.Join(projectRoot, "adapter/adapter-rest/src/main/java", config.PackagePath, "adapter/rest")
+
+	// Check the endpoint against the controller before writing anything
+	controllerPath := filepath.Join(restJavaDir, "controller", moduleClass+"Controller.java")
+	mappingPath, err := controllerMappingPath(controllerPath, endpoint.Path)
+	if err != nil {
+		return err
+	}
+
+	commandPath := filepath.Join(appJavaDir, "dto", useCaseClass+"Command.java")
+	executorPath := filepath.Join(appJavaDir, "executor", useCaseClass+"Executor.java")
+	for _, path := range []string{commandPath, executorPath} {
+		if utils.FileExists(path) {
+			return fmt.Errorf("文件已存在，为避免覆盖已有代码已停止生成: %s", path)
+		}
+	}
+
+	utils.PrintInfo(fmt.Sprintf("生成用例 %s (模块 application-%s)...", useCaseClass, opts.Module))
+
+	files := map[string]string{
+		commandPath:  templates.UseCaseCommand,
+		executorPath: templates.UseCaseExecutor,
+	}
+	for path, template := range files {
+		content := utils.ReplacePlaceholders(template, replacements)
+		if err := utils.WriteFile(path, content); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("已生成 %s", relPath(projectRoot, path)))
+	}
+
+	// Service delegation
+	servicePath := filepath.Join(appJavaDir, "service", moduleClass+"Service.java")
+	if err := addUseCaseToService(servicePath, replacements); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("已更新 %s", relPath(projectRoot, servicePath)))
+
+	// Unit test skeleton
+	testPath := filepath.Join(appTestDir, "executor", useCaseClass+"ExecutorTest.java")
+	if utils.FileExists(testPath) {
+		utils.PrintWarning(fmt.Sprintf("测试类已存在，跳过: %s", relPath(projectRoot, testPath)))
+	} else {
+		content := utils.ReplacePlaceholders(templates.UseCaseExecutorTest, replacements)
+		if err := utils.WriteFile(testPath, content); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("已生成 %s", relPath(projectRoot, testPath)))
+	}
+
+	added, err := addModuleDependency(config, "application/application-"+opts.Module, "org.springframework.boot", "spring-boot-starter-test", "test")
+	if err != nil {
+		return err
+	}
+	if added {
+		utils.PrintSuccess(fmt.Sprintf("已为 application-%s 添加 spring-boot-starter-test 测试依赖", opts.Module))
+	}
+
+	// Request VO and endpoint
+	requestPath := filepath.Join(restJavaDir, "request", useCaseClass+"Request.java")
+	if utils.FileExists(requestPath) {
+		utils.PrintWarning(fmt.Sprintf("请求类已存在，跳过: %s", relPath(projectRoot, requestPath)))
+	} else {
+		content := utils.ReplacePlaceholders(templates.UseCaseRequest, replacements)
+		if err := utils.WriteFile(requestPath, content); err != nil {
+			return err
+		}
+		utils.PrintSuccess(fmt.Sprintf("已生成 %s", relPath(projectRoot, requestPath)))
+	}
+
+	if err := addUseCaseToController(controllerPath, endpoint.Method, mappingPath, pathVariables, replacements); err != nil {
+		return err
+	}
+	utils.PrintSuccess(fmt.Sprintf("已更新 %s", relPath(projectRoot, controllerPath)))
+
+	if !hasModuleDependency(config, "adapter/adapter-rest", "application-"+opts.Module) {
+		utils.PrintWarning(fmt.Sprintf("adapter-rest 尚未依赖 application-%s，请在 adapter/adapter-rest/%s 中添加依赖", opts.Module, config.buildFileName()))
+	}
+
+	printUseCaseSummary(useCaseClass, endpoint)
+	return nil
+}
+
+func parseHTTPEndpoint(spec string) (*httpEndpoint, error) {
+	parts := strings.SplitN(spec, ":", 2)
+	if len(parts) != 2 || !strings.HasPrefix(parts[1], "/") {
+		return nil, fmt.Errorf("HTTP 端点格式不正确: %s，请使用 METHOD:/path 格式，例如: POST:/api/orders/{id}/cancel", spec)
+	}
+
+	method := strings.ToUpper(parts[0])
+	if _, ok := httpMappingAnnotations[method]; !ok {
+		return nil, fmt.Errorf("不支持的 HTTP 方法: %s", parts[0])
+	}
+
+	return &httpEndpoint{Method: method, Path: parts[1]}, nil
+}
+
+func extractPathVariables(path string) []string {
+	var vars []string
+	for _, m := range pathVariableRe.FindAllStringSubmatch(path, -1) {
+		vars = append(vars, m[1])
+	}
+	return vars
+}
+
+// pathVariableType guesses the Java type of a path variable from its name
+func pathVariableType(name string) string {
+	if name == "id" || strings.HasSuffix(name, "Id") {
+		return "Long"
+	}
+	return "String"
+}
+
+func buildCommandFields(pathVariables []string) string {
+	var sb strings.Builder
+	for _, v := range pathVariables {
+		sb.WriteString(fmt.Sprintf("    private %s %s;\n", pathVariableType(v), v))
+	}
+	return sb.String()
+}
+
+func addUseCaseToService(servicePath string, replacements map[string]string) error {
+	var src string
+	if utils.FileExists(servicePath) {
+		content, err := os.ReadFile(servicePath)
+		if err != nil {
+			return err
+		}
+		src = string(content)
+	} else {
+		src = utils.ReplacePlaceholders(templates.UseCaseService, replacements)
+	}
+
+	useCaseClass := replacements["{{USECASE_CLASS}}"]
+	method := replacements["{{USECASE_METHOD}}"]
+	pkg := replacements["{{PACKAGE_NAME}}"] + ".application." + replacements["{{MODULE_PACKAGE}}"]
+
+	if hasJavaMethod(src, method) {
+		utils.PrintWarning(fmt.Sprintf("Service 中已存在方法 %s，跳过", method))
+		return nil
+	}
+
+	src = ensureClassAnnotation(src, "RequiredArgsConstructor", "lombok.RequiredArgsConstructor")
+	src = addJavaImport(src, pkg+".dto."+useCaseClass+"Command")
+	src = addJavaImport(src, pkg+".executor."+useCaseClass+"Executor")
+	if !hasJavaField(src, method+"Executor") {
+		src = addJavaField(src, fmt.Sprintf("private final %sExecutor %sExecutor;", useCaseClass, method))
+	}
+	src = appendJavaMethod(src, utils.ReplacePlaceholders(templates.UseCaseServiceMethod, replacements))
+
+	return utils.WriteFile(servicePath, src)
+}
+
+// controllerMappingPath returns the path of an endpoint relative to the
+// class-level @RequestMapping of an existing controller. An endpoint outside
+// that mapping cannot be added to the controller.
+func controllerMappingPath(controllerPath, path string) (string, error) {
+	if !utils.FileExists(controllerPath) {
+		return path, nil
+	}
+	content, err := os.ReadFile(controllerPath)
+	if err != nil {
+		return "", err
+	}
+	prefix := strings.TrimSuffix(classRequestMapping(string(content)), "/")
+	if prefix == "" {
+		return path, nil
+	}
+	if path == prefix {
+		return "", nil
+	}
+	if !strings.HasPrefix(path, prefix+"/") {
+		return "", fmt.Errorf("%s 的 @RequestMapping 为 %s，端点 %s 不在该路径下，请修改 --http 或将端点放到其他 Controller", filepath.Base(controllerPath), prefix, path)
+	}
+	return strings.TrimPrefix(path, prefix), nil
+}
+
+func addUseCaseToController(controllerPath, httpMethod, path string, pathVariables []string, replacements map[string]string) error {
+	var src string
+	if utils.FileExists(controllerPath) {
+		content, err := os.ReadFile(controllerPath)
+		if err != nil {
+			return err
+		}
+		src = string(content)
+	} else {
+		src = utils.ReplacePlaceholders(templates.UseCaseController, replacements)
+	}
+
+	useCaseClass := replacements["{{USECASE_CLASS}}"]
+	method := replacements["{{USECASE_METHOD}}"]
+	pkg := replacements["{{PACKAGE_NAME}}"]
+	appPkg := pkg + ".application." + replacements["{{MODULE_PACKAGE}}"]
+	moduleClass := replacements["{{MODULE_CLASS}}"]
+	serviceField := replacements["{{MODULE_FIELD}}"] + "Service"
+
+	if hasJavaMethod(src, method) {
+		utils.PrintWarning(fmt.Sprintf("Controller 中已存在方法 %s，跳过", method))
+		return nil
+	}
+
+	annotation := httpMappingAnnotations[httpMethod]
+	var params, assignments []string
+	for _, v := range pathVariables {
+		params = append(params, fmt.Sprintf("@PathVariable %s %s", pathVariableType(v), v))
+		assignments = append(assignments, fmt.Sprintf("        command.set%s(%s);\n", toCamelCase(v), v))
+	}
+	hasBody := httpMethod == "POST" || httpMethod == "PUT" || httpMethod == "PATCH"
+	if hasBody {
+		params = append(params, fmt.Sprintf("@Validated @RequestBody %sRequest request", useCaseClass))
+	} else {
+		params = append(params, fmt.Sprintf("@Validated %sRequest request", useCaseClass))
+	}
+
+	mapping := fmt.Sprintf("@%s(\"%s\")", annotation, path)
+	if path == "" {
+		mapping = "@" + annotation
+	}
+
+	methodReplacements := map[string]string{
+		"{{HTTP_MAPPING}}":        mapping,
+		"{{CONTROLLER_PARAMS}}":   strings.Join(params, ", "),
+		"{{COMMAND_ASSIGNMENTS}}": strings.Join(assignments, ""),
+		"{{MODULE_FIELD}}":        replacements["{{MODULE_FIELD}}"],
+		"{{USECASE_CLASS}}":       useCaseClass,
+		"{{USECASE_METHOD}}":      method,
+	}
+
+	src = addJavaImport(src, pkg+".adapter.rest.request."+useCaseClass+"Request")
+	src = addJavaImport(src, appPkg+".dto."+useCaseClass+"Command")
+	src = addJavaImport(src, appPkg+".service."+moduleClass+"Service")
+	src = addJavaImport(src, pkg+".common.response.Result")
+	src = addJavaImport(src, "org.springframework.validation.annotation.Validated")
+	src = addJavaImport(src, "org.springframework.web.bind.annotation."+annotation)
+	if len(pathVariables) > 0 {
+		src = addJavaImport(src, "org.springframework.web.bind.annotation.PathVariable")
+	}
+	if hasBody {
+		src = addJavaImport(src, "org.springframework.web.bind.annotation.RequestBody")
+	}
+	if !hasJavaField(src, serviceField) {
+		src = ensureClassAnnotation(src, "RequiredArgsConstructor", "lombok.RequiredArgsConstructor")
+		src = addJavaField(src, fmt.Sprintf("private final %sService %s;", moduleClass, serviceField))
+	}
+	src = appendJavaMethod(src, utils.ReplacePlaceholders(templates.UseCaseControllerMethod, methodReplacements))
+
+	return utils.WriteFile(controllerPath, src)
+}
+
+// relPath returns path relative to the project root for display
+func relPath(projectRoot, path string) string {
+	if rel, err := filepath.Rel(projectRoot, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+func printUseCaseSummary(useCaseClass string, endpoint *httpEndpoint) {
+	fmt.Println()
+	utils.PrintSuccess("==========================================")
+	utils.PrintSuccess(fmt.Sprintf("用例 %s 生成完成！", useCaseClass))
+	utils.PrintSuccess("==========================================")
+	fmt.Println()
+	utils.PrintInfo(fmt.Sprintf("HTTP 端点: %s %s", endpoint.Method, endpoint.Path))
+	fmt.Println()
+	utils.PrintInfo("下一步：")
+	fmt.Printf("  1. 在 %sCommand / %sRequest 中补充字段\n", useCaseClass, useCaseClass)
+	fmt.Printf("  2. 在 %sExecutor 中编排业务流程\n", useCaseClass)
+	fmt.Printf("  3. 完善 %sExecutorTest 单元测试\n", useCaseClass)
+	fmt.Println()
+}
@@ -0,0 +1,322 @@
+package generator
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/gradle"
+	"github.com/phixia/phjvgen/internal/pom"
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+var (
+	javaPackageNameRe  = regexp.MustCompile(`(?m)^\s*package\s+([\w.]+)\s*;`)
+	javaMapperRe       = regexp.MustCompile(`(?m)^\s*(?:public\s+)?interface\s+(\w+Mapper)\b([^{]*)\{`)
+	baseMapperEntityRe = regexp.MustCompile(`BaseMapper\s*<\s*([\w.]+)\s*>`)
+	quotedStringRe     = regexp.MustCompile(`"([^"]*)"`)
+	projectReferenceRe = regexp.MustCompile(`project\("(:[^"]+)"\)`)
+)
+
+// verifyIssue is a problem found by a check, reported against a file
+type verifyIssue struct {
+	path    string
+	message string
+}
+
+// verifyProject is the project state shared by the checks
+type verifyProject struct {
+	root   string
+	config *ProjectConfig
+	// roots are the src/main/java and src/test/java directories
+	roots []string
+	// sources are the Java files of all source roots
+	sources []javaSourceFile
+}
+
+// javaSourceFile is a Java file with the package its directory implies
+type javaSourceFile struct {
+	path     string
+	expected string // package implied by the directory
+	declared string // package declared in the file, "" when missing
+	content  string
+}
+
+// verifyCheck is a named consistency check
+type verifyCheck struct {
+	name string
+	run  func(p *verifyProject) ([]verifyIssue, error)
+}
+
+var verifyChecks = []verifyCheck{
+	{"模块目录", verifyModuleDirs},
+	{"内部依赖声明", verifyInternalDependencies},
+	{"Java 包声明", verifyJavaPackages},
+	{"@MapperScan 包", verifyMapperScan},
+	{"Mapper 与 DO", verifyMapperEntities},
+}
+
+// Verify checks the consistency of the project's build files and sources
+// without running Maven or Gradle. It returns an error when any check fails.
+func Verify() error {
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return fmt.Errorf("未找到项目根目录: %w\n提示: 请确保在包含pom.xml或settings.gradle.kts的项目目录或其子目录中运行此命令", err)
+	}
+	config, err := extractProjectInfo(projectRoot)
+	if err != nil {
+		return err
+	}
+	roots, err := findJavaSourceRoots(projectRoot)
+	if err != nil {
+		return err
+	}
+	sources, err := readJavaSources(roots)
+	if err != nil {
+		return err
+	}
+	p := &verifyProject{root: projectRoot, config: config, roots: roots, sources: sources}
+
+	utils.PrintInfo(fmt.Sprintf("检查项目: %s", projectRoot))
+	fmt.Println()
+
+	total := 0
+	for _, check := range verifyChecks {
+		issues, err := check.run(p)
+		if err != nil {
+			return fmt.Errorf("%s检查失败: %w", check.name, err)
+		}
+		if len(issues) == 0 {
+			utils.PrintSuccess(check.name)
+			continue
+		}
+		utils.PrintError(fmt.Sprintf("%s（%d 个问题）", check.name, len(issues)))
+		for _, issue := range issues {
+			fmt.Printf("    %s: %s\n", relPath(projectRoot, issue.path), issue.message)
+		}
+		total += len(issues)
+	}
+
+	fmt.Println()
+	if total > 0 {
+		return fmt.Errorf("发现 %d 个问题", total)
+	}
+	utils.PrintSuccess("项目结构一致")
+	return nil
+}
+
+// readJavaSources reads every Java file under the given source roots
+func readJavaSources(roots []string) ([]javaSourceFile, error) {
+	var sources []javaSourceFile
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".java") {
+				return err
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(root, filepath.Dir(path))
+			if err != nil {
+				return err
+			}
+			file := javaSourceFile{path: path, content: string(content)}
+			if rel != "." {
+				file.expected = strings.ReplaceAll(filepath.ToSlash(rel), "/", ".")
+			}
+			if m := javaPackageNameRe.FindStringSubmatch(file.content); m != nil {
+				file.declared = m[1]
+			}
+			sources = append(sources, file)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sources, nil
+}
+
+// verifyModuleDirs checks that every declared module has a directory with a build file
+func verifyModuleDirs(p *verifyProject) ([]verifyIssue, error) {
+	if p.config.isGradle() {
+		settingsPath := filepath.Join(p.root, gradle.SettingsFile)
+		settings, err := gradle.Load(settingsPath)
+		if err != nil {
+			return nil, err
+		}
+		var issues []verifyIssue
+		for _, m := range settings.Modules() {
+			if issue, ok := checkModuleDir(settingsPath, filepath.Join(p.root, m.Path), m.Path, gradle.BuildFile); !ok {
+				issues = append(issues, issue)
+			}
+		}
+		return issues, nil
+	}
+	return verifyPOMModules(filepath.Join(p.root, "pom.xml"))
+}
+
+// verifyPOMModules checks the <module> entries of a POM and of its existing modules
+func verifyPOMModules(pomPath string) ([]verifyIssue, error) {
+	doc, err := pom.Load(pomPath)
+	if err != nil {
+		return nil, err
+	}
+	var issues []verifyIssue
+	for _, module := range doc.Modules() {
+		dir := filepath.Join(filepath.Dir(pomPath), module)
+		issue, ok := checkModuleDir(pomPath, dir, module, "pom.xml")
+		if !ok {
+			issues = append(issues, issue)
+			continue
+		}
+		nested, err := verifyPOMModules(filepath.Join(dir, "pom.xml"))
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, nested...)
+	}
+	return issues, nil
+}
+
+func checkModuleDir(declaredIn, dir, module, buildFile string) (verifyIssue, bool) {
+	if !utils.DirExists(dir) {
+		return verifyIssue{declaredIn, fmt.Sprintf("模块 %s 的目录不存在", module)}, false
+	}
+	if !utils.FileExists(filepath.Join(dir, buildFile)) {
+		return verifyIssue{declaredIn, fmt.Sprintf("模块 %s 缺少 %s", module, buildFile)}, false
+	}
+	return verifyIssue{}, true
+}
+
+// verifyInternalDependencies checks that the dependencies between modules
+// resolve: Maven modules must be declared in dependencyManagement, Gradle
+// project dependencies must be included in the settings
+func verifyInternalDependencies(p *verifyProject) ([]verifyIssue, error) {
+	modules, err := listProjectModules(p.config)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []verifyIssue
+	if p.config.isGradle() {
+		included := map[string]bool{}
+		for _, m := range modules {
+			included[":"+m.artifactID] = true
+		}
+		for _, m := range modules {
+			buildPath := filepath.Join(p.root, m.path, gradle.BuildFile)
+			content, err := os.ReadFile(buildPath)
+			if err != nil {
+				continue // reported by the module check
+			}
+			for _, ref := range projectReferenceRe.FindAllStringSubmatch(string(content), -1) {
+				if !included[ref[1]] {
+					issues = append(issues, verifyIssue{buildPath, fmt.Sprintf("依赖的项目 %s 未在 %s 中声明", ref[1], gradle.SettingsFile)})
+				}
+			}
+		}
+		return issues, nil
+	}
+
+	for _, m := range modules {
+		pomPath := filepath.Join(p.root, m.path, "pom.xml")
+		if !utils.FileExists(pomPath) {
+			continue // reported by the module check
+		}
+		model, err := pom.Read(pomPath)
+		if err != nil {
+			return nil, err
+		}
+		managed := map[string]bool{}
+		for _, dep := range model.DependencyManagement {
+			managed[dep.GroupID+":"+dep.ArtifactID] = true
+		}
+		for _, dep := range model.Dependencies {
+			if dep.GroupID != p.config.GroupID || dep.Version != "" {
+				continue
+			}
+			if !managed[dep.GroupID+":"+dep.ArtifactID] {
+				issues = append(issues, verifyIssue{pomPath, fmt.Sprintf("依赖 %s 未在父 POM 的 dependencyManagement 中声明", dep.ArtifactID)})
+			}
+		}
+	}
+	return issues, nil
+}
+
+// verifyJavaPackages checks that every Java file declares the package of its directory
+func verifyJavaPackages(p *verifyProject) ([]verifyIssue, error) {
+	var issues []verifyIssue
+	for _, file := range p.sources {
+		switch {
+		case file.declared == file.expected:
+		case file.declared == "":
+			issues = append(issues, verifyIssue{file.path, fmt.Sprintf("缺少 package 声明，应为 %s", file.expected)})
+		case file.expected == "":
+			issues = append(issues, verifyIssue{file.path, fmt.Sprintf("package %s 与目录不符，源码根目录下的文件不应声明 package", file.declared)})
+		default:
+			issues = append(issues, verifyIssue{file.path, fmt.Sprintf("package %s 与目录不符，应为 %s", file.declared, file.expected)})
+		}
+	}
+	return issues, nil
+}
+
+// verifyMapperScan checks that the packages listed in @MapperScan exist
+func verifyMapperScan(p *verifyProject) ([]verifyIssue, error) {
+	var issues []verifyIssue
+	for _, file := range p.sources {
+		for _, m := range mapperScanRe.FindAllStringSubmatch(file.content, -1) {
+			for _, value := range quotedStringRe.FindAllStringSubmatch(m[1], -1) {
+				if !packageExists(p.roots, value[1]) {
+					issues = append(issues, verifyIssue{file.path, fmt.Sprintf("@MapperScan 的包 %s 不存在", value[1])})
+				}
+			}
+		}
+	}
+	return issues, nil
+}
+
+// packageExists reports whether the directory of pkg exists under one of the
+// source roots. A package without Java files yet, such as the mapper package
+// of a new bounded context, is valid.
+func packageExists(roots []string, pkg string) bool {
+	dir := filepath.FromSlash(strings.ReplaceAll(pkg, ".", "/"))
+	for _, root := range roots {
+		if utils.DirExists(filepath.Join(root, dir)) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyMapperEntities checks that every Mapper interface has its DO class.
+// The DO is the type argument of BaseMapper, or <Name>DO for a UserMapper
+// that does not extend BaseMapper.
+func verifyMapperEntities(p *verifyProject) ([]verifyIssue, error) {
+	classes := map[string]bool{}
+	for _, file := range p.sources {
+		name := strings.TrimSuffix(filepath.Base(file.path), ".java")
+		classes[name] = true
+		classes[file.declared+"."+name] = true
+	}
+
+	var issues []verifyIssue
+	for _, file := range p.sources {
+		m := javaMapperRe.FindStringSubmatch(file.content)
+		if m == nil {
+			continue
+		}
+		entity := strings.TrimSuffix(m[1], "Mapper") + "DO"
+		if e := baseMapperEntityRe.FindStringSubmatch(m[2]); e != nil {
+			entity = e[1]
+		}
+		if !classes[entity] {
+			issues = append(issues, verifyIssue{file.path, fmt.Sprintf("%s 对应的 %s 不存在", m[1], entity)})
+		}
+	}
+	return issues, nil
+}
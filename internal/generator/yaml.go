@@ -0,0 +1,98 @@
+package generator
+
+import "strings"
+
+// The helpers below edit YAML files line by line, keeping comments and
+// layout. They support the block mappings indented by two spaces per level
+// that phjvgen generates.
+
+func yamlIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// yamlSkippable reports whether a line is blank or a comment
+func yamlSkippable(line string) bool {
+	t := strings.TrimSpace(line)
+	return t == "" || strings.HasPrefix(t, "#")
+}
+
+// yamlBlockEnd returns the index after the last line nested under a key
+func yamlBlockEnd(lines []string, key int) int {
+	last := key
+	for i := key + 1; i < len(lines); i++ {
+		if yamlSkippable(lines[i]) {
+			continue
+		}
+		if yamlIndent(lines[i]) <= yamlIndent(lines[key]) {
+			break
+		}
+		last = i
+	}
+	return last + 1
+}
+
+// findYAMLKey returns the line of the key at a path such as spring.datasource.url.
+// When the path is missing, depth is the number of keys found and end is
+// where the block of the deepest one ends.
+func findYAMLKey(lines []string, path []string) (line, depth, end int) {
+	line, end = -1, len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	start := 0
+	for depth = 0; depth < len(path); depth++ {
+		found := -1
+		for i := start; i < end; i++ {
+			if yamlSkippable(lines[i]) || yamlIndent(lines[i]) != depth*2 {
+				continue
+			}
+			t := strings.TrimSpace(lines[i])
+			if t == path[depth]+":" || strings.HasPrefix(t, path[depth]+": ") {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return -1, depth, end
+		}
+		line, start, end = found, found+1, yamlBlockEnd(lines, found)
+	}
+	return line, depth, end
+}
+
+// yamlValue returns the scalar value of the key at a path, or "" if missing
+func yamlValue(content string, path []string) string {
+	lines := strings.Split(content, "\n")
+	line, _, _ := findYAMLKey(lines, path)
+	if line < 0 {
+		return ""
+	}
+	_, value, _ := strings.Cut(lines[line], ":")
+	return strings.TrimSpace(value)
+}
+
+// yamlHasKey reports whether the key at a path exists
+func yamlHasKey(content string, path []string) bool {
+	line, _, _ := findYAMLKey(strings.Split(content, "\n"), path)
+	return line >= 0
+}
+
+// insertYAMLEntry appends entry, already indented for its depth, to the
+// mapping at a path, creating the missing keys of the path
+func insertYAMLEntry(content string, path []string, entry string) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	_, depth, end := findYAMLKey(lines, path)
+
+	var insert []string
+	if depth == 0 {
+		// A new top-level key is separated from the previous one
+		insert = append(insert, "")
+	}
+	for d := depth; d < len(path); d++ {
+		insert = append(insert, strings.Repeat("  ", d)+path[d]+":")
+	}
+	insert = append(insert, strings.Split(strings.TrimRight(entry, "\n"), "\n")...)
+
+	result := append(append(append([]string{}, lines[:end]...), insert...), lines[end:]...)
+	return strings.Join(result, "\n") + "\n"
+}
@@ -0,0 +1,178 @@
+package gradle
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CatalogFile is the version catalog, relative to the project root
+const CatalogFile = "gradle/libs.versions.toml"
+
+var (
+	sectionRe        = regexp.MustCompile(`^\s*\[([\w.-]+)\]\s*$`)
+	catalogVersionRe = regexp.MustCompile(`^\s*([\w-]+)\s*=\s*"([^"]*)"`)
+	catalogLibraryRe = regexp.MustCompile(`^\s*([\w-]+)\s*=\s*\{\s*module\s*=\s*"([^"]+)"(?:\s*,\s*version\.ref\s*=\s*"([^"]+)")?`)
+)
+
+// Library is an entry of the [libraries] section of the version catalog
+type Library struct {
+	Alias      string
+	Module     string
+	VersionRef string
+}
+
+// Accessor returns the type-safe accessor of the library, e.g. libs.spring.boot.starter
+func (l Library) Accessor() string {
+	return "libs." + strings.NewReplacer("-", ".", "_", ".").Replace(l.Alias)
+}
+
+// Libraries returns the entries of the [libraries] section
+func (f *File) Libraries() []Library {
+	var libs []Library
+	start, end := f.section("libraries")
+	for i := start + 1; start != -1 && i < end; i++ {
+		if m := catalogLibraryRe.FindStringSubmatch(f.lines[i]); m != nil {
+			libs = append(libs, Library{Alias: m[1], Module: m[2], VersionRef: m[3]})
+		}
+	}
+	return libs
+}
+
+// Library returns the library declared for group:artifact
+func (f *File) Library(module string) (Library, bool) {
+	for _, lib := range f.Libraries() {
+		if lib.Module == module {
+			return lib, true
+		}
+	}
+	return Library{}, false
+}
+
+// AddLibrary appends a library to the [libraries] section. versionRef may be
+// empty for libraries whose version comes from a platform. It returns false
+// when the alias is already declared.
+func (f *File) AddLibrary(lib Library) bool {
+	for _, existing := range f.Libraries() {
+		if existing.Alias == lib.Alias {
+			return false
+		}
+	}
+	line := fmt.Sprintf(`%s = { module = "%s" }`, lib.Alias, lib.Module)
+	if lib.VersionRef != "" {
+		line = fmt.Sprintf(`%s = { module = "%s", version.ref = "%s" }`, lib.Alias, lib.Module, lib.VersionRef)
+	}
+	f.appendToSection("libraries", line)
+	return true
+}
+
+// RemoveLibrary removes a library from the [libraries] section
+func (f *File) RemoveLibrary(alias string) bool {
+	return f.removeFromSection("libraries", catalogLibraryRe, alias)
+}
+
+// CatalogVersions returns the entries of the [versions] section in file order
+func (f *File) CatalogVersions() [][2]string {
+	var versions [][2]string
+	start, end := f.section("versions")
+	for i := start + 1; start != -1 && i < end; i++ {
+		if m := catalogVersionRe.FindStringSubmatch(f.lines[i]); m != nil {
+			versions = append(versions, [2]string{m[1], m[2]})
+		}
+	}
+	return versions
+}
+
+// CatalogVersion returns a version of the [versions] section
+func (f *File) CatalogVersion(name string) (string, bool) {
+	for _, v := range f.CatalogVersions() {
+		if v[0] == name {
+			return v[1], true
+		}
+	}
+	return "", false
+}
+
+// SetCatalogVersion updates a version of the [versions] section, adding it
+// when missing
+func (f *File) SetCatalogVersion(name, version string) {
+	start, end := f.section("versions")
+	for i := start + 1; start != -1 && i < end; i++ {
+		if loc := catalogVersionRe.FindStringSubmatchIndex(f.lines[i]); loc != nil && f.lines[i][loc[2]:loc[3]] == name {
+			f.lines[i] = f.lines[i][:loc[4]] + version + f.lines[i][loc[5]:]
+			return
+		}
+	}
+	f.appendToSection("versions", fmt.Sprintf(`%s = "%s"`, name, version))
+}
+
+// RemoveCatalogVersion removes a version from the [versions] section
+func (f *File) RemoveCatalogVersion(name string) bool {
+	return f.removeFromSection("versions", catalogVersionRe, name)
+}
+
+// References reports whether a version is referenced by a library or plugin
+func (f *File) References(versionName string) bool {
+	ref := fmt.Sprintf(`version.ref = "%s"`, versionName)
+	for _, line := range f.lines {
+		if strings.Contains(line, ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// section returns the line of a [name] header and the line where the
+// section ends, or -1 when the section is missing
+func (f *File) section(name string) (int, int) {
+	start := -1
+	for i, line := range f.lines {
+		m := sectionRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if start != -1 {
+			return start, i
+		}
+		if m[1] == name {
+			start = i
+		}
+	}
+	if start == -1 {
+		return -1, -1
+	}
+	return start, len(f.lines)
+}
+
+// appendToSection adds a line after the last entry of a section, creating
+// the section at the end of the file when missing
+func (f *File) appendToSection(name, line string) {
+	start, end := f.section(name)
+	if start == -1 {
+		last := f.lastContentLine()
+		if last >= 0 {
+			f.insert(last+1, "", "["+name+"]", line)
+		} else {
+			f.insert(0, "["+name+"]", line)
+		}
+		return
+	}
+	at := start + 1
+	for i := start + 1; i < end; i++ {
+		if strings.TrimSpace(f.lines[i]) != "" {
+			at = i + 1
+		}
+	}
+	f.insert(at, line)
+}
+
+func (f *File) removeFromSection(name string, re *regexp.Regexp, key string) bool {
+	start, end := f.section(name)
+	for i := start + 1; start != -1 && i < end; i++ {
+		if m := re.FindStringSubmatch(f.lines[i]); m != nil && m[1] == key {
+			f.lines = append(f.lines[:i], f.lines[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,325 @@
+// Package gradle edits the Kotlin DSL build files generated for Gradle
+// projects. Edits are line based and only touch the lines they add, remove
+// or rename, so hand-written content is kept as is.
+package gradle
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	// SettingsFile is the name of the settings script at the project root
+	SettingsFile = "settings.gradle.kts"
+	// BuildFile is the name of the build script of each project
+	BuildFile = "build.gradle.kts"
+)
+
+var (
+	moduleLineRe = regexp.MustCompile(`^\s*module\("([^"]+)"(?:,\s*"([^"]+)")?\)\s*$`)
+	groupLineRe  = regexp.MustCompile(`^\s*group\s*=\s*"([^"]*)"`)
+	versionRe    = regexp.MustCompile(`^\s*version\s*=\s*"([^"]*)"`)
+	rootNameRe   = regexp.MustCompile(`^\s*rootProject\.name\s*=\s*"([^"]*)"`)
+)
+
+// File is a Gradle script held as lines
+type File struct {
+	lines []string
+}
+
+// Load reads a Gradle script
+func Load(path string) (*File, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(content)), nil
+}
+
+// Parse splits script content into lines
+func Parse(content string) *File {
+	return &File{lines: strings.Split(content, "\n")}
+}
+
+// String returns the script content
+func (f *File) String() string {
+	return strings.Join(f.lines, "\n")
+}
+
+// Save writes the script to path
+func (f *File) Save(path string) error {
+	return os.WriteFile(path, []byte(f.String()), 0644)
+}
+
+// Module is a project declared in settings.gradle.kts
+type Module struct {
+	Name string
+	Path string
+}
+
+// Modules returns the projects declared with module(...) in the settings script
+func (f *File) Modules() []Module {
+	var modules []Module
+	for _, line := range f.lines {
+		if m := moduleLineRe.FindStringSubmatch(line); m != nil {
+			path := m[2]
+			if path == "" {
+				path = m[1]
+			}
+			modules = append(modules, Module{Name: m[1], Path: path})
+		}
+	}
+	return modules
+}
+
+func moduleLine(name, path string) string {
+	if path == "" || path == name {
+		return fmt.Sprintf("module(%q)", name)
+	}
+	return fmt.Sprintf("module(%q, %q)", name, path)
+}
+
+// AddModule declares a project after the last module(...) line. It returns
+// false when the project is already declared.
+func (f *File) AddModule(name, path string) bool {
+	last := -1
+	for i, line := range f.lines {
+		if m := moduleLineRe.FindStringSubmatch(line); m != nil {
+			if m[1] == name {
+				return false
+			}
+			last = i
+		}
+	}
+	if last == -1 {
+		last = f.lastContentLine()
+	}
+	f.insert(last+1, moduleLine(name, path))
+	return true
+}
+
+// RemoveModule removes the module(...) line of a project. It returns false
+// when the project is not declared.
+func (f *File) RemoveModule(name string) bool {
+	for i, line := range f.lines {
+		if m := moduleLineRe.FindStringSubmatch(line); m != nil && m[1] == name {
+			f.lines = append(f.lines[:i], f.lines[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// RenameModule renames a project and moves it to a new directory
+func (f *File) RenameModule(oldName, newName, newPath string) bool {
+	for i, line := range f.lines {
+		if m := moduleLineRe.FindStringSubmatch(line); m != nil && m[1] == oldName {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			f.lines[i] = indent + moduleLine(newName, newPath)
+			return true
+		}
+	}
+	return false
+}
+
+// RootProjectName returns rootProject.name from the settings script
+func (f *File) RootProjectName() string {
+	return f.match(rootNameRe)
+}
+
+// Group returns the group assigned in the build script
+func (f *File) Group() string {
+	return f.match(groupLineRe)
+}
+
+// Version returns the version assigned in the build script
+func (f *File) Version() string {
+	return f.match(versionRe)
+}
+
+// SetGroup replaces the group assignment. It returns false when there is none.
+func (f *File) SetGroup(group string) bool {
+	for i, line := range f.lines {
+		if loc := groupLineRe.FindStringSubmatchIndex(line); loc != nil {
+			f.lines[i] = line[:loc[2]] + group + line[loc[3]:]
+			return true
+		}
+	}
+	return false
+}
+
+func (f *File) match(re *regexp.Regexp) string {
+	for _, line := range f.lines {
+		if m := re.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// projectDependencyRe matches a dependency on another project of the build
+func projectDependencyRe(name string) *regexp.Regexp {
+	return regexp.MustCompile(`^\s*"?\w+"?\(project\("` + regexp.QuoteMeta(":"+name) + `"\)\)\s*$`)
+}
+
+// HasProjectDependency reports whether the script depends on the project
+func (f *File) HasProjectDependency(name string) bool {
+	re := projectDependencyRe(name)
+	for _, line := range f.lines {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasDependency reports whether the script declares a dependency on the
+// given notation, either as a string or as a catalog alias
+func (f *File) HasDependency(notation string) bool {
+	for _, line := range f.lines {
+		if strings.Contains(line, `"`+notation+`"`) || strings.Contains(line, "("+notation+")") {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveDependency removes every dependency declared on the notation, either
+// as a string or as a catalog alias, and returns how many lines were removed
+func (f *File) RemoveDependency(notation string) int {
+	re := regexp.MustCompile(`^\s*"?\w+"?\((?:"` + regexp.QuoteMeta(notation) + `(?::[^"]*)?"|` + regexp.QuoteMeta(notation) + `)\)\s*$`)
+	kept := f.lines[:0]
+	removed := 0
+	for _, line := range f.lines {
+		if re.MatchString(line) {
+			removed++
+			continue
+		}
+		kept = append(kept, line)
+	}
+	f.lines = kept
+	return removed
+}
+
+// AddDependency adds a line such as api(project(":common")) to the top-level
+// dependencies block, creating the block when it is missing
+func (f *File) AddDependency(configuration, notation string) {
+	line := fmt.Sprintf("%s(%s)", configuration, notation)
+
+	start, end := f.dependenciesBlock()
+	if start == -1 {
+		if last := f.lastContentLine(); last >= 0 {
+			f.insert(last+1, "")
+			f.insert(last+2, "dependencies {", "    "+line, "}")
+		} else {
+			f.insert(0, "dependencies {", "    "+line, "}")
+		}
+		return
+	}
+
+	// Keep project dependencies together; put other dependencies after the
+	// last one with the same configuration, or at the end of the block
+	at := end
+	for i := start + 1; i < end; i++ {
+		trimmed := strings.TrimSpace(f.lines[i])
+		if strings.HasPrefix(notation, "project(") {
+			if strings.Contains(trimmed, "(project(") {
+				at = i + 1
+			}
+		} else if strings.HasPrefix(trimmed, configuration+"(") {
+			at = i + 1
+		}
+	}
+	f.insert(at, f.blockIndent(start, end)+line)
+}
+
+// ProjectDependencyConfiguration returns the configuration used by the
+// first project dependency, or "" when there is none
+func (f *File) ProjectDependencyConfiguration() string {
+	re := regexp.MustCompile(`^\s*"?(\w+)"?\(project\(`)
+	for _, line := range f.lines {
+		if m := re.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// RemoveProjectDependency removes every dependency on the project and returns
+// how many lines were removed
+func (f *File) RemoveProjectDependency(name string) int {
+	re := projectDependencyRe(name)
+	kept := f.lines[:0]
+	removed := 0
+	for _, line := range f.lines {
+		if re.MatchString(line) {
+			removed++
+			continue
+		}
+		kept = append(kept, line)
+	}
+	f.lines = kept
+	return removed
+}
+
+// RenameProjectReferences rewrites project(":old") to project(":new") and
+// returns how many references were changed
+func (f *File) RenameProjectReferences(oldName, newName string) int {
+	oldRef := fmt.Sprintf(`project(":%s")`, oldName)
+	newRef := fmt.Sprintf(`project(":%s")`, newName)
+	changed := 0
+	for i, line := range f.lines {
+		if strings.Contains(line, oldRef) {
+			f.lines[i] = strings.ReplaceAll(line, oldRef, newRef)
+			changed++
+		}
+	}
+	return changed
+}
+
+// dependenciesBlock returns the line indexes of the top-level
+// "dependencies {" line and of its closing brace, or -1 when missing
+func (f *File) dependenciesBlock() (int, int) {
+	for i, line := range f.lines {
+		if strings.TrimSpace(line) != "dependencies {" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		depth := 0
+		for j := i; j < len(f.lines); j++ {
+			depth += strings.Count(f.lines[j], "{") - strings.Count(f.lines[j], "}")
+			if depth == 0 {
+				return i, j
+			}
+		}
+	}
+	return -1, -1
+}
+
+func (f *File) blockIndent(start, end int) string {
+	for i := start + 1; i < end; i++ {
+		line := f.lines[i]
+		if trimmed := strings.TrimLeft(line, " \t"); trimmed != "" {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "    "
+}
+
+func (f *File) lastContentLine() int {
+	for i := len(f.lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(f.lines[i]) != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *File) insert(at int, lines ...string) {
+	updated := make([]string, 0, len(f.lines)+len(lines))
+	updated = append(updated, f.lines[:at]...)
+	updated = append(updated, lines...)
+	updated = append(updated, f.lines[at:]...)
+	f.lines = updated
+}
@@ -0,0 +1,383 @@
+// Package pom edits Maven POM files in place. Elements are located by
+// parsing the XML, and every edit splices text into the original bytes, so
+// formatting, comments and untouched sections are kept exactly as written.
+package pom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Document is a parsed POM file
+type Document struct {
+	src  []byte
+	root *Element
+}
+
+// Element is an XML element with its byte offsets in the source
+type Element struct {
+	Name     string
+	Parent   *Element
+	Children []*Element
+
+	// start and end delimit the whole element, from "<" of the start tag to
+	// after ">" of the end tag
+	start, end int
+	// contentStart and contentEnd delimit the element content
+	contentStart, contentEnd int
+	text                     string
+}
+
+// Load reads and parses a POM file
+func Load(path string) (*Document, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// Parse parses POM content
+func Parse(content []byte) (*Document, error) {
+	doc := &Document{src: content}
+	if err := doc.parse(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Bytes returns the current content of the document
+func (d *Document) Bytes() []byte {
+	return d.src
+}
+
+// Save writes the document to path
+func (d *Document) Save(path string) error {
+	return os.WriteFile(path, d.src, 0644)
+}
+
+// Root returns the <project> element
+func (d *Document) Root() *Element {
+	return d.root
+}
+
+func (d *Document) parse() error {
+	dec := xml.NewDecoder(bytes.NewReader(d.src))
+	dec.Strict = true
+
+	var stack []*Element
+	var root *Element
+	for {
+		offset := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			e := &Element{Name: t.Name.Local, start: offset, contentStart: int(dec.InputOffset())}
+			if len(stack) > 0 {
+				e.Parent = stack[len(stack)-1]
+				e.Parent.Children = append(e.Parent.Children, e)
+			} else if root == nil {
+				root = e
+			}
+			stack = append(stack, e)
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return fmt.Errorf("unexpected </%s>", t.Name.Local)
+			}
+			e := stack[len(stack)-1]
+			if e.Name != t.Name.Local {
+				return fmt.Errorf("element <%s> closed by </%s>", e.Name, t.Name.Local)
+			}
+			e.contentEnd = offset
+			e.end = int(dec.InputOffset())
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+
+	if len(stack) > 0 {
+		return fmt.Errorf("element <%s> is not closed", stack[len(stack)-1].Name)
+	}
+	if root == nil {
+		return fmt.Errorf("document has no root element")
+	}
+	d.root = root
+	return nil
+}
+
+// Child returns the first child element with the given name
+func (e *Element) Child(name string) *Element {
+	if e == nil {
+		return nil
+	}
+	for _, c := range e.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// ChildrenNamed returns the child elements with the given name
+func (e *Element) ChildrenNamed(name string) []*Element {
+	if e == nil {
+		return nil
+	}
+	var children []*Element
+	for _, c := range e.Children {
+		if c.Name == name {
+			children = append(children, c)
+		}
+	}
+	return children
+}
+
+// Path follows a chain of child names, returning nil when one is missing
+func (e *Element) Path(names ...string) *Element {
+	for _, name := range names {
+		e = e.Child(name)
+	}
+	return e
+}
+
+// Text returns the trimmed text content of the element
+func (e *Element) Text() string {
+	if e == nil {
+		return ""
+	}
+	return strings.TrimSpace(e.text)
+}
+
+// ChildText returns the trimmed text of the first child with the given name
+func (e *Element) ChildText(name string) string {
+	return e.Child(name).Text()
+}
+
+// Walk calls fn for the element and all its descendants in document order
+func (e *Element) Walk(fn func(*Element)) {
+	if e == nil {
+		return
+	}
+	fn(e)
+	for _, c := range e.Children {
+		c.Walk(fn)
+	}
+}
+
+// Find returns every element in the document matching the predicate
+func (d *Document) Find(match func(*Element) bool) []*Element {
+	var found []*Element
+	d.root.Walk(func(e *Element) {
+		if match(e) {
+			found = append(found, e)
+		}
+	})
+	return found
+}
+
+// splice replaces src[start:end] with text and re-parses the document.
+// Elements obtained before the call are stale afterwards.
+func (d *Document) splice(start, end int, text string) error {
+	updated := make([]byte, 0, len(d.src)-(end-start)+len(text))
+	updated = append(updated, d.src[:start]...)
+	updated = append(updated, text...)
+	updated = append(updated, d.src[end:]...)
+
+	previous := d.src
+	d.src = updated
+	if err := d.parse(); err != nil {
+		d.src = previous
+		_ = d.parse()
+		return err
+	}
+	return nil
+}
+
+// SetText replaces the content of an element with escaped text
+func (d *Document) SetText(e *Element, value string) error {
+	var buf bytes.Buffer
+	if err := xml.EscapeText(&buf, []byte(value)); err != nil {
+		return err
+	}
+	if e.contentStart == e.end {
+		// Self-closing element
+		return d.splice(e.start, e.end, fmt.Sprintf("<%s>%s</%s>", e.Name, buf.String(), e.Name))
+	}
+	return d.splice(e.contentStart, e.contentEnd, buf.String())
+}
+
+// Remove deletes an element. When the element sits on its own lines, the
+// surrounding indentation and line break are removed with it. A comment on
+// the line above an element that is alone in its group (followed by a blank
+// line or the end of its parent) labels that element, so it is removed as
+// well, together with one of the blank lines around the group.
+func (d *Document) Remove(e *Element) error {
+	start, end := e.start, e.end
+
+	lineStart := bytes.LastIndexByte(d.src[:start], '\n') + 1
+	if len(bytes.TrimSpace(d.src[lineStart:start])) == 0 {
+		lineEnd := d.nextLine(end)
+		if len(bytes.TrimSpace(d.src[end:lineEnd])) == 0 {
+			start, end = lineStart, lineEnd
+			if commentStart, ok := d.leadingComment(start); ok && d.endsGroup(end) {
+				start = commentStart
+				if next := d.nextLine(end); end < len(d.src) && len(bytes.TrimSpace(d.src[end:next])) == 0 {
+					end = next
+				} else if prev := d.previousLine(start); prev < start && len(bytes.TrimSpace(d.src[prev:start])) == 0 {
+					start = prev
+				}
+			}
+		}
+	}
+	return d.splice(start, end, "")
+}
+
+// nextLine returns the position after the line break following pos, or the
+// end of the document
+func (d *Document) nextLine(pos int) int {
+	if nl := bytes.IndexByte(d.src[pos:], '\n'); nl != -1 {
+		return pos + nl + 1
+	}
+	return len(d.src)
+}
+
+// previousLine returns the start of the line before the one starting at
+// lineStart
+func (d *Document) previousLine(lineStart int) int {
+	if lineStart == 0 {
+		return 0
+	}
+	return bytes.LastIndexByte(d.src[:lineStart-1], '\n') + 1
+}
+
+// leadingComment returns the start of the line of a comment that ends on
+// the line before lineStart and has nothing else on its lines
+func (d *Document) leadingComment(lineStart int) (int, bool) {
+	prev := d.previousLine(lineStart)
+	if prev == lineStart || !bytes.HasSuffix(bytes.TrimSpace(d.src[prev:lineStart]), []byte("-->")) {
+		return 0, false
+	}
+	open := bytes.LastIndex(d.src[:lineStart], []byte("<!--"))
+	if open == -1 {
+		return 0, false
+	}
+	commentLine := bytes.LastIndexByte(d.src[:open], '\n') + 1
+	if len(bytes.TrimSpace(d.src[commentLine:open])) != 0 {
+		return 0, false
+	}
+	return commentLine, true
+}
+
+// endsGroup reports whether the line starting at pos is blank or closes the
+// parent element, i.e. the lines before it end a group of siblings
+func (d *Document) endsGroup(pos int) bool {
+	line := bytes.TrimSpace(d.src[pos:d.nextLine(pos)])
+	return len(line) == 0 || bytes.HasPrefix(line, []byte("</"))
+}
+
+// AppendChild inserts an XML fragment as the last child of parent
+func (d *Document) AppendChild(parent *Element, fragment string) error {
+	if len(parent.Children) > 0 {
+		return d.InsertAfter(parent.Children[len(parent.Children)-1], fragment)
+	}
+
+	indent := d.lineIndent(parent.start)
+	childIndent := indent + d.indentUnit()
+	text := d.indentFragment(fragment, childIndent)
+
+	if parent.contentStart == parent.end {
+		// Self-closing element: expand it
+		return d.splice(parent.start, parent.end,
+			fmt.Sprintf("<%s>\n%s\n%s</%s>", parent.Name, text, indent, parent.Name))
+	}
+
+	// Keep the content before the end tag (comments, whitespace) and put the
+	// child on its own line above the end tag
+	lineStart := bytes.LastIndexByte(d.src[:parent.contentEnd], '\n') + 1
+	if lineStart > parent.contentStart && len(bytes.TrimSpace(d.src[lineStart:parent.contentEnd])) == 0 {
+		return d.splice(lineStart, lineStart, text+"\n")
+	}
+	content := strings.TrimRight(string(d.src[parent.contentStart:parent.contentEnd]), " \t\n")
+	return d.splice(parent.contentStart, parent.contentEnd, content+"\n"+text+"\n"+indent)
+}
+
+// InsertAfter inserts an XML fragment as the next sibling of e, using the
+// indentation of e
+func (d *Document) InsertAfter(e *Element, fragment string) error {
+	indent := d.lineIndent(e.start)
+	text := d.indentFragment(fragment, indent)
+
+	if nl := bytes.IndexByte(d.src[e.end:], '\n'); nl != -1 && len(bytes.TrimSpace(d.src[e.end:e.end+nl])) == 0 {
+		pos := e.end + nl + 1
+		return d.splice(pos, pos, text+"\n")
+	}
+	return d.splice(e.end, e.end, "\n"+text)
+}
+
+// InsertBefore inserts an XML fragment as the previous sibling of e, using
+// the indentation of e
+func (d *Document) InsertBefore(e *Element, fragment string) error {
+	indent := d.lineIndent(e.start)
+	text := d.indentFragment(fragment, indent)
+
+	lineStart := bytes.LastIndexByte(d.src[:e.start], '\n') + 1
+	if len(bytes.TrimSpace(d.src[lineStart:e.start])) == 0 {
+		return d.splice(lineStart, lineStart, text+"\n")
+	}
+	return d.splice(e.start, e.start, strings.TrimLeft(text, " \t")+"\n"+indent)
+}
+
+// lineIndent returns the whitespace at the start of the line holding pos
+func (d *Document) lineIndent(pos int) string {
+	lineStart := bytes.LastIndexByte(d.src[:pos], '\n') + 1
+	line := d.src[lineStart:pos]
+	n := 0
+	for n < len(line) && (line[n] == ' ' || line[n] == '\t') {
+		n++
+	}
+	return string(line[:n])
+}
+
+// indentUnit detects the indentation step of the document from the first
+// child of <project>, defaulting to four spaces
+func (d *Document) indentUnit() string {
+	for _, c := range d.root.Children {
+		indent := d.lineIndent(c.start)
+		rootIndent := d.lineIndent(d.root.start)
+		if len(indent) > len(rootIndent) && strings.HasPrefix(indent, rootIndent) {
+			return indent[len(rootIndent):]
+		}
+	}
+	return "    "
+}
+
+// indentFragment re-indents a fragment written with four-space steps and no
+// base indentation, using the document's indentation unit and the given base
+func (d *Document) indentFragment(fragment, base string) string {
+	unit := d.indentUnit()
+	lines := strings.Split(strings.Trim(fragment, "\n"), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		depth := (len(line) - len(trimmed)) / 4
+		lines[i] = base + strings.Repeat(unit, depth) + trimmed
+	}
+	return strings.Join(lines, "\n")
+}
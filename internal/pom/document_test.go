@@ -0,0 +1,254 @@
+package pom
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustParse(t *testing.T, src string) *Document {
+	t.Helper()
+	doc, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return doc
+}
+
+func assertXML(t *testing.T, doc *Document, want string) {
+	t.Helper()
+	if got := string(doc.Bytes()); got != want {
+		t.Errorf("document mismatch\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+	// Every edit must leave a document that parses again
+	if _, err := Parse(doc.Bytes()); err != nil {
+		t.Errorf("edited document does not parse: %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	doc := mustParse(t, `<?xml version="1.0"?>
+<!-- header -->
+<project>
+    <groupId>com.example</groupId>
+    <modules>
+        <module>a</module>
+        <module>b</module>
+    </modules>
+</project>
+`)
+	root := doc.Root()
+	if root.Name != "project" {
+		t.Fatalf("root = %s, want project", root.Name)
+	}
+	if got := root.ChildText("groupId"); got != "com.example" {
+		t.Errorf("groupId = %q", got)
+	}
+	if got := len(root.Path("modules").ChildrenNamed("module")); got != 2 {
+		t.Errorf("modules = %d, want 2", got)
+	}
+	if root.Path("build", "plugins") != nil {
+		t.Errorf("missing path should be nil")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, src := range map[string]string{
+		"unclosed":   "<project><modules></project>",
+		"mismatched": "<project><a></b></project>",
+		"empty":      "<!-- only a comment -->",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(src)); err == nil {
+				t.Errorf("Parse(%q) succeeded", src)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "own line",
+			src:  "<project>\n    <a>1</a>\n    <b>2</b>\n</project>\n",
+			want: "<project>\n    <b>2</b>\n</project>\n",
+		},
+		{
+			name: "shared line",
+			src:  "<project><a>1</a><b>2</b></project>",
+			want: "<project><b>2</b></project>",
+		},
+		{
+			name: "keeps comment of next element",
+			src:  "<project>\n    <a>1</a>\n    <!-- b -->\n    <b>2</b>\n</project>\n",
+			want: "<project>\n    <!-- b -->\n    <b>2</b>\n</project>\n",
+		},
+		{
+			name: "comment labelling the element",
+			src:  "<project>\n    <x/>\n\n    <!-- a -->\n    <a>1</a>\n\n    <!-- b -->\n    <b>2</b>\n</project>\n",
+			want: "<project>\n    <x/>\n\n    <!-- b -->\n    <b>2</b>\n</project>\n",
+		},
+		{
+			name: "comment labelling the last element",
+			src:  "<project>\n    <x/>\n\n    <!--\n      a\n    -->\n    <a>1</a>\n</project>\n",
+			want: "<project>\n    <x/>\n</project>\n",
+		},
+		{
+			name: "comment labelling a group",
+			src:  "<project>\n    <!-- group -->\n    <a>1</a>\n    <b>2</b>\n</project>\n",
+			want: "<project>\n    <!-- group -->\n    <b>2</b>\n</project>\n",
+		},
+		{
+			name: "comment sharing a line",
+			src:  "<project>\n    <x/> <!-- x -->\n    <a>1</a>\n</project>\n",
+			want: "<project>\n    <x/> <!-- x -->\n</project>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := mustParse(t, tt.src)
+			if err := doc.Remove(doc.Root().Child("a")); err != nil {
+				t.Fatal(err)
+			}
+			assertXML(t, doc, tt.want)
+		})
+	}
+}
+
+func TestSetText(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		value string
+		want  string
+	}{
+		{"replace", "<project>\n    <v>1.0</v>\n</project>", "2.0", "<project>\n    <v>2.0</v>\n</project>"},
+		{"escape", "<project><v>a</v></project>", "a<b&c", "<project><v>a&lt;b&amp;c</v></project>"},
+		{"self-closing", "<project><v/></project>", "x", "<project><v>x</v></project>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := mustParse(t, tt.src)
+			if err := doc.SetText(doc.Root().Child("v"), tt.value); err != nil {
+				t.Fatal(err)
+			}
+			assertXML(t, doc, tt.want)
+			if got := doc.Root().ChildText("v"); got != tt.value {
+				t.Errorf("re-parsed text = %q, want %q", got, tt.value)
+			}
+		})
+	}
+}
+
+func TestAppendChild(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "after last child",
+			src:  "<project>\n    <list>\n        <a/>\n    </list>\n</project>\n",
+			want: "<project>\n    <list>\n        <a/>\n        <x>\n            <y>1</y>\n        </x>\n    </list>\n</project>\n",
+		},
+		{
+			name: "empty element",
+			src:  "<project>\n    <list>\n    </list>\n</project>\n",
+			want: "<project>\n    <list>\n        <x>\n            <y>1</y>\n        </x>\n    </list>\n</project>\n",
+		},
+		{
+			name: "self-closing element",
+			src:  "<project>\n    <list/>\n</project>\n",
+			want: "<project>\n    <list>\n        <x>\n            <y>1</y>\n        </x>\n    </list>\n</project>\n",
+		},
+		{
+			name: "keeps trailing comment",
+			src:  "<project>\n    <list>\n        <!-- none yet -->\n    </list>\n</project>\n",
+			want: "<project>\n    <list>\n        <!-- none yet -->\n        <x>\n            <y>1</y>\n        </x>\n    </list>\n</project>\n",
+		},
+		{
+			name: "two-space indentation",
+			src:  "<project>\n  <list>\n    <a/>\n  </list>\n</project>\n",
+			want: "<project>\n  <list>\n    <a/>\n    <x>\n      <y>1</y>\n    </x>\n  </list>\n</project>\n",
+		},
+		{
+			name: "tab indentation",
+			src:  "<project>\n\t<list>\n\t</list>\n</project>\n",
+			want: "<project>\n\t<list>\n\t\t<x>\n\t\t\t<y>1</y>\n\t\t</x>\n\t</list>\n</project>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := mustParse(t, tt.src)
+			if err := doc.AppendChild(doc.Root().Child("list"), "<x>\n    <y>1</y>\n</x>"); err != nil {
+				t.Fatal(err)
+			}
+			assertXML(t, doc, tt.want)
+		})
+	}
+}
+
+func TestInsertBeforeAndAfter(t *testing.T) {
+	src := "<project>\n    <!-- first -->\n    <a/>\n    <b/>\n</project>\n"
+
+	doc := mustParse(t, src)
+	if err := doc.InsertAfter(doc.Root().Child("a"), "<x/>"); err != nil {
+		t.Fatal(err)
+	}
+	assertXML(t, doc, "<project>\n    <!-- first -->\n    <a/>\n    <x/>\n    <b/>\n</project>\n")
+
+	doc = mustParse(t, src)
+	if err := doc.InsertBefore(doc.Root().Child("b"), "<x/>"); err != nil {
+		t.Fatal(err)
+	}
+	assertXML(t, doc, "<project>\n    <!-- first -->\n    <a/>\n    <x/>\n    <b/>\n</project>\n")
+
+	doc = mustParse(t, "<project><a/></project>")
+	if err := doc.InsertAfter(doc.Root().Child("a"), "<x/>"); err != nil {
+		t.Fatal(err)
+	}
+	assertXML(t, doc, "<project><a/>\n<x/></project>")
+}
+
+func TestFailedSpliceKeepsDocument(t *testing.T) {
+	src := "<project>\n    <list>\n    </list>\n</project>\n"
+	doc := mustParse(t, src)
+	if err := doc.AppendChild(doc.Root().Child("list"), "<broken>"); err == nil {
+		t.Fatal("appending an unclosed element succeeded")
+	}
+	assertXML(t, doc, src)
+	if doc.Root().Child("list") == nil {
+		t.Error("document was not re-parsed after the failed edit")
+	}
+}
+
+func TestEditsKeepUntouchedBytes(t *testing.T) {
+	src := `<?xml version="1.0" encoding="UTF-8"?>
+<!-- 父 POM -->
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+    <modelVersion>4.0.0</modelVersion>
+
+    <properties>
+        <java.version>25</java.version>   <!-- LTS -->
+    </properties>
+
+    <modules>
+        <module>common</module>
+    </modules>
+</project>
+`
+	doc := mustParse(t, src)
+	if _, err := doc.AddModule("domain"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doc.RemoveModule("domain"); err != nil {
+		t.Fatal(err)
+	}
+	assertXML(t, doc, src)
+	if !strings.Contains(string(doc.Bytes()), "<!-- LTS -->") {
+		t.Error("comment lost")
+	}
+}
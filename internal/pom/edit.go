@@ -0,0 +1,477 @@
+package pom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// projectOrder is the element order of the Maven POM reference, used to
+// place sections that do not exist yet
+var projectOrder = []string{
+	"modelVersion", "parent", "groupId", "artifactId", "version", "packaging",
+	"name", "description", "url", "inceptionYear", "organization", "licenses",
+	"developers", "contributors", "mailingLists", "prerequisites", "modules",
+	"scm", "issueManagement", "ciManagement", "distributionManagement",
+	"properties", "dependencyManagement", "dependencies", "repositories",
+	"pluginRepositories", "build", "reporting", "profiles",
+}
+
+// Dependency is a Maven dependency declaration
+type Dependency struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	Type       string
+	Scope      string
+	Optional   bool
+}
+
+// String returns the groupId:artifactId coordinates of the dependency
+func (dep Dependency) String() string {
+	return dep.GroupID + ":" + dep.ArtifactID
+}
+
+func (dep Dependency) fragment() string {
+	var b strings.Builder
+	b.WriteString("<dependency>\n")
+	writeTag(&b, "groupId", dep.GroupID)
+	writeTag(&b, "artifactId", dep.ArtifactID)
+	writeTag(&b, "version", dep.Version)
+	writeTag(&b, "type", dep.Type)
+	writeTag(&b, "scope", dep.Scope)
+	if dep.Optional {
+		writeTag(&b, "optional", "true")
+	}
+	b.WriteString("</dependency>")
+	return b.String()
+}
+
+func writeTag(b *strings.Builder, name, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, "    <%s>%s</%s>\n", name, escape(value), name)
+}
+
+func escape(value string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(value))
+	return buf.String()
+}
+
+// ensureSection returns the named child of parent, creating an empty one
+// when it is missing. order lists the sibling names in their expected order.
+func (d *Document) ensureSection(parent *Element, name string, order []string) (*Element, error) {
+	if e := parent.Child(name); e != nil {
+		return e, nil
+	}
+
+	fragment := fmt.Sprintf("<%s>\n</%s>", name, name)
+	if anchor := sectionAnchor(parent, name, order); anchor != nil {
+		if err := d.InsertAfter(anchor, fragment); err != nil {
+			return nil, err
+		}
+	} else if len(parent.Children) > 0 {
+		if err := d.InsertBefore(parent.Children[0], fragment); err != nil {
+			return nil, err
+		}
+	} else if err := d.AppendChild(parent, fragment); err != nil {
+		return nil, err
+	}
+
+	// The document was re-parsed: locate the parent again by its path
+	return d.locate(elementPath(parent)).Child(name), nil
+}
+
+// sectionAnchor returns the last child of parent that comes before name in
+// order, or nil when the new section belongs first
+func sectionAnchor(parent *Element, name string, order []string) *Element {
+	index := indexOf(order, name)
+	var anchor *Element
+	for _, c := range parent.Children {
+		if i := indexOf(order, c.Name); i != -1 && (index == -1 || i < index) {
+			anchor = c
+		}
+	}
+	if anchor == nil && index == -1 && len(parent.Children) > 0 {
+		anchor = parent.Children[len(parent.Children)-1]
+	}
+	return anchor
+}
+
+func indexOf(list []string, value string) int {
+	for i, v := range list {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// elementPath returns the position of e as child indexes from the root
+func elementPath(e *Element) []int {
+	var path []int
+	for ; e.Parent != nil; e = e.Parent {
+		for i, c := range e.Parent.Children {
+			if c == e {
+				path = append([]int{i}, path...)
+				break
+			}
+		}
+	}
+	return path
+}
+
+func (d *Document) locate(path []int) *Element {
+	e := d.root
+	for _, i := range path {
+		e = e.Children[i]
+	}
+	return e
+}
+
+// Modules returns the module paths declared in <modules>
+func (d *Document) Modules() []string {
+	var modules []string
+	for _, m := range d.root.Path("modules").ChildrenNamed("module") {
+		modules = append(modules, m.Text())
+	}
+	return modules
+}
+
+// AddModule declares a module. It returns false when it is already declared.
+func (d *Document) AddModule(path string) (bool, error) {
+	for _, m := range d.Modules() {
+		if m == path {
+			return false, nil
+		}
+	}
+	modules, err := d.ensureSection(d.root, "modules", projectOrder)
+	if err != nil {
+		return false, err
+	}
+	return true, d.AppendChild(modules, fmt.Sprintf("<module>%s</module>", escape(path)))
+}
+
+// RemoveModule removes a module declaration. It returns false when the module
+// is not declared.
+func (d *Document) RemoveModule(path string) (bool, error) {
+	for _, m := range d.root.Path("modules").ChildrenNamed("module") {
+		if m.Text() == path {
+			return true, d.Remove(m)
+		}
+	}
+	return false, nil
+}
+
+// Dependencies returns the dependencies declared in <dependencies>
+func (d *Document) Dependencies() []Dependency {
+	return readDependencies(d.root.Path("dependencies"))
+}
+
+// ManagedDependencies returns the dependencies declared in <dependencyManagement>
+func (d *Document) ManagedDependencies() []Dependency {
+	return readDependencies(d.root.Path("dependencyManagement", "dependencies"))
+}
+
+func readDependencies(section *Element) []Dependency {
+	var deps []Dependency
+	for _, e := range section.ChildrenNamed("dependency") {
+		deps = append(deps, toDependency(e))
+	}
+	return deps
+}
+
+func toDependency(e *Element) Dependency {
+	return Dependency{
+		GroupID:    e.ChildText("groupId"),
+		ArtifactID: e.ChildText("artifactId"),
+		Version:    e.ChildText("version"),
+		Type:       e.ChildText("type"),
+		Scope:      e.ChildText("scope"),
+		Optional:   e.ChildText("optional") == "true",
+	}
+}
+
+// HasDependency reports whether <dependencies> declares the artifact
+func (d *Document) HasDependency(groupID, artifactID string) bool {
+	return findDependency(d.root.Path("dependencies"), groupID, artifactID) != nil
+}
+
+// HasManagedDependency reports whether <dependencyManagement> declares the artifact
+func (d *Document) HasManagedDependency(groupID, artifactID string) bool {
+	return findDependency(d.root.Path("dependencyManagement", "dependencies"), groupID, artifactID) != nil
+}
+
+// findDependency returns the dependency element for the artifact. An empty
+// groupID matches any group.
+func findDependency(section *Element, groupID, artifactID string) *Element {
+	for _, e := range section.ChildrenNamed("dependency") {
+		if e.ChildText("artifactId") == artifactID && (groupID == "" || e.ChildText("groupId") == groupID) {
+			return e
+		}
+	}
+	return nil
+}
+
+// AddDependency adds a dependency to <dependencies>. It returns false when
+// the artifact is already declared.
+func (d *Document) AddDependency(dep Dependency) (bool, error) {
+	if d.HasDependency(dep.GroupID, dep.ArtifactID) {
+		return false, nil
+	}
+	section, err := d.ensureSection(d.root, "dependencies", projectOrder)
+	if err != nil {
+		return false, err
+	}
+	return true, d.insertDependency(section, dep)
+}
+
+// MakeDependencyRequired removes <optional> from a dependency of
+// <dependencies>, so that modules depending on this one get it as well. It
+// returns false when the artifact is not declared or not optional.
+func (d *Document) MakeDependencyRequired(groupID, artifactID string) (bool, error) {
+	e := findDependency(d.root.Path("dependencies"), groupID, artifactID)
+	if e == nil {
+		return false, nil
+	}
+	optional := e.Child("optional")
+	if optional == nil {
+		return false, nil
+	}
+	return true, d.Remove(optional)
+}
+
+// AddManagedDependency adds a dependency to <dependencyManagement>. It
+// returns false when the artifact is already declared.
+func (d *Document) AddManagedDependency(dep Dependency) (bool, error) {
+	if d.HasManagedDependency(dep.GroupID, dep.ArtifactID) {
+		return false, nil
+	}
+	management, err := d.ensureSection(d.root, "dependencyManagement", projectOrder)
+	if err != nil {
+		return false, err
+	}
+	section, err := d.ensureSection(management, "dependencies", nil)
+	if err != nil {
+		return false, err
+	}
+	return true, d.insertDependency(section, dep)
+}
+
+// insertDependency keeps dependencies grouped: the new one goes after the
+// last dependency of the same group, or at the end of the section
+func (d *Document) insertDependency(section *Element, dep Dependency) error {
+	var anchor *Element
+	for _, e := range section.ChildrenNamed("dependency") {
+		if e.ChildText("groupId") == dep.GroupID {
+			anchor = e
+		}
+	}
+	if anchor != nil {
+		return d.InsertAfter(anchor, dep.fragment())
+	}
+	return d.AppendChild(section, dep.fragment())
+}
+
+// RemoveDependency removes the artifact from <dependencies>. It returns
+// false when the artifact is not declared.
+func (d *Document) RemoveDependency(groupID, artifactID string) (bool, error) {
+	e := findDependency(d.root.Path("dependencies"), groupID, artifactID)
+	if e == nil {
+		return false, nil
+	}
+	return true, d.Remove(e)
+}
+
+// RemoveManagedDependency removes the artifact from <dependencyManagement>.
+// It returns false when the artifact is not declared.
+func (d *Document) RemoveManagedDependency(groupID, artifactID string) (bool, error) {
+	e := findDependency(d.root.Path("dependencyManagement", "dependencies"), groupID, artifactID)
+	if e == nil {
+		return false, nil
+	}
+	return true, d.Remove(e)
+}
+
+// RemoveAllDependencies removes every <dependency> on the artifact anywhere
+// in the document, including dependencyManagement, profiles and plugins,
+// and returns how many were removed
+func (d *Document) RemoveAllDependencies(groupID, artifactID string) (int, error) {
+	removed := 0
+	for {
+		found := d.Find(func(e *Element) bool {
+			return e.Name == "dependency" && e.ChildText("artifactId") == artifactID &&
+				(groupID == "" || e.ChildText("groupId") == groupID)
+		})
+		if len(found) == 0 {
+			return removed, nil
+		}
+		if err := d.Remove(found[0]); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+}
+
+// Property returns the value of a property declared in <properties>
+func (d *Document) Property(name string) (string, bool) {
+	e := d.root.Path("properties", name)
+	if e == nil {
+		return "", false
+	}
+	return e.Text(), true
+}
+
+// Properties returns the properties declared in <properties> in document order
+func (d *Document) Properties() [][2]string {
+	var props [][2]string
+	if section := d.root.Child("properties"); section != nil {
+		for _, e := range section.Children {
+			props = append(props, [2]string{e.Name, e.Text()})
+		}
+	}
+	return props
+}
+
+// SetProperty sets a property, adding it to <properties> when missing
+func (d *Document) SetProperty(name, value string) error {
+	if e := d.root.Path("properties", name); e != nil {
+		if e.Text() == value {
+			return nil
+		}
+		return d.SetText(e, value)
+	}
+	section, err := d.ensureSection(d.root, "properties", projectOrder)
+	if err != nil {
+		return err
+	}
+	return d.AppendChild(section, fmt.Sprintf("<%s>%s</%s>", name, escape(value), name))
+}
+
+// RemoveProperty removes a property. It returns false when it is not declared.
+func (d *Document) RemoveProperty(name string) (bool, error) {
+	e := d.root.Path("properties", name)
+	if e == nil {
+		return false, nil
+	}
+	return true, d.Remove(e)
+}
+
+// HasPlugin reports whether <build><plugins> declares the plugin
+func (d *Document) HasPlugin(groupID, artifactID string) bool {
+	return findPlugin(d.root.Path("build", "plugins"), groupID, artifactID) != nil
+}
+
+func findPlugin(section *Element, groupID, artifactID string) *Element {
+	for _, e := range section.ChildrenNamed("plugin") {
+		// The groupId of a plugin defaults to org.apache.maven.plugins
+		group := e.ChildText("groupId")
+		if group == "" {
+			group = "org.apache.maven.plugins"
+		}
+		if e.ChildText("artifactId") == artifactID && (groupID == "" || group == groupID) {
+			return e
+		}
+	}
+	return nil
+}
+
+// AddPlugin adds a <plugin> fragment to <build><plugins>. It returns false
+// when the plugin is already declared.
+func (d *Document) AddPlugin(groupID, artifactID, fragment string) (bool, error) {
+	if d.HasPlugin(groupID, artifactID) {
+		return false, nil
+	}
+	build, err := d.ensureSection(d.root, "build", projectOrder)
+	if err != nil {
+		return false, err
+	}
+	plugins, err := d.ensureSection(build, "plugins", []string{"pluginManagement", "plugins"})
+	if err != nil {
+		return false, err
+	}
+	return true, d.AppendChild(plugins, fragment)
+}
+
+// ManagedPlugin returns the plugin declared in <build><pluginManagement>, or nil
+func (d *Document) ManagedPlugin(groupID, artifactID string) *Element {
+	return findPlugin(d.root.Path("build", "pluginManagement", "plugins"), groupID, artifactID)
+}
+
+// AddManagedPlugin adds a <plugin> fragment to <build><pluginManagement><plugins>.
+// It returns false when the plugin is already managed.
+func (d *Document) AddManagedPlugin(groupID, artifactID, fragment string) (bool, error) {
+	if d.ManagedPlugin(groupID, artifactID) != nil {
+		return false, nil
+	}
+	build, err := d.ensureSection(d.root, "build", projectOrder)
+	if err != nil {
+		return false, err
+	}
+	management, err := d.ensureSection(build, "pluginManagement", []string{"pluginManagement", "plugins"})
+	if err != nil {
+		return false, err
+	}
+	plugins, err := d.ensureSection(management, "plugins", []string{"plugins"})
+	if err != nil {
+		return false, err
+	}
+	return true, d.AppendChild(plugins, fragment)
+}
+
+// RemovePlugin removes a plugin from <build><plugins>. It returns false when
+// the plugin is not declared.
+func (d *Document) RemovePlugin(groupID, artifactID string) (bool, error) {
+	e := findPlugin(d.root.Path("build", "plugins"), groupID, artifactID)
+	if e == nil {
+		return false, nil
+	}
+	return true, d.Remove(e)
+}
+
+// HasProfile reports whether <profiles> declares a profile with the id
+func (d *Document) HasProfile(id string) bool {
+	for _, e := range d.root.Path("profiles").ChildrenNamed("profile") {
+		if e.ChildText("id") == id {
+			return true
+		}
+	}
+	return false
+}
+
+// AddProfile adds a <profile> fragment to <profiles>. It returns false when
+// a profile with the id is already declared.
+func (d *Document) AddProfile(id, fragment string) (bool, error) {
+	if d.HasProfile(id) {
+		return false, nil
+	}
+	profiles, err := d.ensureSection(d.root, "profiles", projectOrder)
+	if err != nil {
+		return false, err
+	}
+	return true, d.AppendChild(profiles, fragment)
+}
+
+// ReplaceText sets the text of every element matching the predicate whose
+// text equals oldValue, returning how many were changed
+func (d *Document) ReplaceText(match func(*Element) bool, oldValue, newValue string) (int, error) {
+	if oldValue == newValue {
+		return 0, nil
+	}
+	changed := 0
+	for {
+		found := d.Find(func(e *Element) bool {
+			return len(e.Children) == 0 && e.Text() == oldValue && match(e)
+		})
+		if len(found) == 0 {
+			return changed, nil
+		}
+		if err := d.SetText(found[0], newValue); err != nil {
+			return changed, err
+		}
+		changed++
+	}
+}
@@ -0,0 +1,429 @@
+package pom
+
+import (
+	"testing"
+)
+
+func TestAddDependency(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		dep  Dependency
+		want string
+	}{
+		{
+			name: "after same group",
+			src: `<project>
+    <dependencies>
+        <dependency>
+            <groupId>org.a</groupId>
+            <artifactId>one</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>org.b</groupId>
+            <artifactId>two</artifactId>
+        </dependency>
+    </dependencies>
+</project>
+`,
+			dep: Dependency{GroupID: "org.a", ArtifactID: "three", Scope: "test"},
+			want: `<project>
+    <dependencies>
+        <dependency>
+            <groupId>org.a</groupId>
+            <artifactId>one</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>org.a</groupId>
+            <artifactId>three</artifactId>
+            <scope>test</scope>
+        </dependency>
+        <dependency>
+            <groupId>org.b</groupId>
+            <artifactId>two</artifactId>
+        </dependency>
+    </dependencies>
+</project>
+`,
+		},
+		{
+			name: "creates section in POM order",
+			src: `<project>
+    <artifactId>app</artifactId>
+    <properties>
+        <x>1</x>
+    </properties>
+
+    <build>
+    </build>
+</project>
+`,
+			dep: Dependency{GroupID: "org.a", ArtifactID: "one", Optional: true},
+			want: `<project>
+    <artifactId>app</artifactId>
+    <properties>
+        <x>1</x>
+    </properties>
+    <dependencies>
+        <dependency>
+            <groupId>org.a</groupId>
+            <artifactId>one</artifactId>
+            <optional>true</optional>
+        </dependency>
+    </dependencies>
+
+    <build>
+    </build>
+</project>
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := mustParse(t, tt.src)
+			added, err := doc.AddDependency(tt.dep)
+			if err != nil || !added {
+				t.Fatalf("AddDependency = %v, %v", added, err)
+			}
+			assertXML(t, doc, tt.want)
+
+			added, err = doc.AddDependency(tt.dep)
+			if err != nil || added {
+				t.Errorf("adding twice = %v, %v, want false", added, err)
+			}
+		})
+	}
+}
+
+func TestAddManagedDependency(t *testing.T) {
+	doc := mustParse(t, `<project>
+    <properties>
+        <x>1</x>
+    </properties>
+</project>
+`)
+	dep := Dependency{GroupID: "org.a", ArtifactID: "bom", Version: "${a.version}", Type: "pom", Scope: "import"}
+	if _, err := doc.AddManagedDependency(dep); err != nil {
+		t.Fatal(err)
+	}
+	assertXML(t, doc, `<project>
+    <properties>
+        <x>1</x>
+    </properties>
+    <dependencyManagement>
+        <dependencies>
+            <dependency>
+                <groupId>org.a</groupId>
+                <artifactId>bom</artifactId>
+                <version>${a.version}</version>
+                <type>pom</type>
+                <scope>import</scope>
+            </dependency>
+        </dependencies>
+    </dependencyManagement>
+</project>
+`)
+	if got := doc.ManagedDependencies(); len(got) != 1 || got[0] != dep {
+		t.Errorf("ManagedDependencies = %+v", got)
+	}
+	if doc.HasDependency("org.a", "bom") {
+		t.Error("managed dependency reported as a dependency")
+	}
+}
+
+func TestRemoveAllDependencies(t *testing.T) {
+	doc := mustParse(t, `<project>
+    <dependencyManagement>
+        <dependencies>
+            <dependency>
+                <groupId>org.r</groupId>
+                <artifactId>redisson</artifactId>
+                <version>1</version>
+            </dependency>
+        </dependencies>
+    </dependencyManagement>
+    <dependencies>
+        <dependency>
+            <groupId>org.r</groupId>
+            <artifactId>redisson</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>org.other</groupId>
+            <artifactId>redisson</artifactId>
+        </dependency>
+    </dependencies>
+    <profiles>
+        <profile>
+            <id>dev</id>
+            <dependencies>
+                <dependency>
+                    <groupId>org.r</groupId>
+                    <artifactId>redisson</artifactId>
+                </dependency>
+            </dependencies>
+        </profile>
+    </profiles>
+</project>
+`)
+	removed, err := doc.RemoveAllDependencies("org.r", "redisson")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 3 {
+		t.Errorf("removed = %d, want 3", removed)
+	}
+	assertXML(t, doc, `<project>
+    <dependencyManagement>
+        <dependencies>
+        </dependencies>
+    </dependencyManagement>
+    <dependencies>
+        <dependency>
+            <groupId>org.other</groupId>
+            <artifactId>redisson</artifactId>
+        </dependency>
+    </dependencies>
+    <profiles>
+        <profile>
+            <id>dev</id>
+            <dependencies>
+            </dependencies>
+        </profile>
+    </profiles>
+</project>
+`)
+
+	// An empty group matches any group
+	removed, err = doc.RemoveAllDependencies("", "redisson")
+	if err != nil || removed != 1 {
+		t.Errorf("RemoveAllDependencies(any group) = %d, %v, want 1", removed, err)
+	}
+}
+
+func TestMakeDependencyRequired(t *testing.T) {
+	src := `<project>
+    <dependencies>
+        <dependency>
+            <groupId>org.r</groupId>
+            <artifactId>redisson</artifactId>
+            <optional>true</optional>
+        </dependency>
+        <dependency>
+            <groupId>org.c</groupId>
+            <artifactId>caffeine</artifactId>
+        </dependency>
+    </dependencies>
+</project>
+`
+	tests := []struct {
+		name       string
+		artifactID string
+		changed    bool
+		want       string
+	}{
+		{
+			name:       "optional",
+			artifactID: "redisson",
+			changed:    true,
+			want: `<project>
+    <dependencies>
+        <dependency>
+            <groupId>org.r</groupId>
+            <artifactId>redisson</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>org.c</groupId>
+            <artifactId>caffeine</artifactId>
+        </dependency>
+    </dependencies>
+</project>
+`,
+		},
+		{name: "not optional", artifactID: "caffeine", want: src},
+		{name: "not declared", artifactID: "lombok", want: src},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := mustParse(t, src)
+			changed, err := doc.MakeDependencyRequired("", tt.artifactID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed != tt.changed {
+				t.Errorf("changed = %v, want %v", changed, tt.changed)
+			}
+			assertXML(t, doc, tt.want)
+		})
+	}
+}
+
+func TestProperties(t *testing.T) {
+	doc := mustParse(t, `<project>
+    <artifactId>app</artifactId>
+</project>
+`)
+	if err := doc.SetProperty("a.version", "1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetProperty("b.version", "2.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.SetProperty("a.version", "1.1"); err != nil {
+		t.Fatal(err)
+	}
+	assertXML(t, doc, `<project>
+    <artifactId>app</artifactId>
+    <properties>
+        <a.version>1.1</a.version>
+        <b.version>2.0</b.version>
+    </properties>
+</project>
+`)
+	if v, ok := doc.Property("a.version"); !ok || v != "1.1" {
+		t.Errorf("Property = %q, %v", v, ok)
+	}
+
+	removed, err := doc.RemoveProperty("a.version")
+	if err != nil || !removed {
+		t.Fatalf("RemoveProperty = %v, %v", removed, err)
+	}
+	if got := doc.Properties(); len(got) != 1 || got[0] != [2]string{"b.version", "2.0"} {
+		t.Errorf("Properties = %v", got)
+	}
+}
+
+func TestModules(t *testing.T) {
+	doc := mustParse(t, `<project>
+    <artifactId>app</artifactId>
+    <packaging>pom</packaging>
+
+    <properties>
+    </properties>
+</project>
+`)
+	for _, m := range []string{"common", "domain"} {
+		if _, err := doc.AddModule(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertXML(t, doc, `<project>
+    <artifactId>app</artifactId>
+    <packaging>pom</packaging>
+    <modules>
+        <module>common</module>
+        <module>domain</module>
+    </modules>
+
+    <properties>
+    </properties>
+</project>
+`)
+	if removed, _ := doc.RemoveModule("missing"); removed {
+		t.Error("removed a module that is not declared")
+	}
+}
+
+func TestPlugins(t *testing.T) {
+	doc := mustParse(t, `<project>
+    <build>
+        <pluginManagement>
+            <plugins>
+            </plugins>
+        </pluginManagement>
+    </build>
+</project>
+`)
+	fragment := "<plugin>\n    <artifactId>maven-enforcer-plugin</artifactId>\n</plugin>"
+	if _, err := doc.AddPlugin("", "maven-enforcer-plugin", fragment); err != nil {
+		t.Fatal(err)
+	}
+	assertXML(t, doc, `<project>
+    <build>
+        <pluginManagement>
+            <plugins>
+            </plugins>
+        </pluginManagement>
+        <plugins>
+            <plugin>
+                <artifactId>maven-enforcer-plugin</artifactId>
+            </plugin>
+        </plugins>
+    </build>
+</project>
+`)
+	// The groupId of a plugin defaults to org.apache.maven.plugins
+	if !doc.HasPlugin("org.apache.maven.plugins", "maven-enforcer-plugin") {
+		t.Error("plugin without groupId not found by the default group")
+	}
+	if doc.ManagedPlugin("", "maven-enforcer-plugin") != nil {
+		t.Error("plugin reported as managed")
+	}
+}
+
+func TestReplaceText(t *testing.T) {
+	doc := mustParse(t, `<project>
+    <groupId>com.old</groupId>
+    <dependencies>
+        <dependency>
+            <groupId>com.old</groupId>
+            <artifactId>common</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>com.old.tools</groupId>
+            <artifactId>other</artifactId>
+        </dependency>
+    </dependencies>
+</project>
+`)
+	changed, err := doc.ReplaceText(func(e *Element) bool { return e.Name == "groupId" }, "com.old", "com.new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed != 2 {
+		t.Errorf("changed = %d, want 2", changed)
+	}
+	assertXML(t, doc, `<project>
+    <groupId>com.new</groupId>
+    <dependencies>
+        <dependency>
+            <groupId>com.new</groupId>
+            <artifactId>common</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>com.old.tools</groupId>
+            <artifactId>other</artifactId>
+        </dependency>
+    </dependencies>
+</project>
+`)
+}
+
+func TestAddThenRemoveRestoresDocument(t *testing.T) {
+	src := `<?xml version="1.0" encoding="UTF-8"?>
+<project>
+    <!-- 依赖 -->
+    <dependencies>
+        <dependency>
+            <groupId>org.a</groupId>
+            <artifactId>one</artifactId>
+        </dependency>
+    </dependencies>
+</project>
+`
+	doc := mustParse(t, src)
+	dep := Dependency{GroupID: "org.b", ArtifactID: "two", Version: "1"}
+	if _, err := doc.AddDependency(dep); err != nil {
+		t.Fatal(err)
+	}
+
+	// The edited bytes parse back to the same model
+	reparsed := mustParse(t, string(doc.Bytes()))
+	if got := reparsed.Dependencies(); len(got) != 2 || got[1] != dep {
+		t.Fatalf("re-parsed dependencies = %+v", got)
+	}
+
+	if _, err := reparsed.RemoveDependency("org.b", "two"); err != nil {
+		t.Fatal(err)
+	}
+	assertXML(t, reparsed, src)
+}
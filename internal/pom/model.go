@@ -0,0 +1,231 @@
+package pom
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/utils"
+)
+
+// ParentRef is the <parent> block of a POM
+type ParentRef struct {
+	GroupID      string
+	ArtifactID   string
+	Version      string
+	RelativePath string
+}
+
+// Model is the effective content of a POM: coordinates inherited from the
+// parent, properties merged along the parent chain and ${...} expressions
+// resolved
+type Model struct {
+	// Path is the POM file the model was read from
+	Path string
+
+	Parent *ParentRef
+	// ParentModel is the parent POM when it is found on disk
+	ParentModel *Model
+
+	GroupID     string
+	ArtifactID  string
+	Version     string
+	Packaging   string
+	Name        string
+	Description string
+
+	// Properties holds the inherited and own properties, unresolved
+	Properties map[string]string
+
+	Modules              []string
+	DependencyManagement []Dependency
+	Dependencies         []Dependency
+}
+
+var expressionRe = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// maxParentDepth bounds the parent chain, guarding against cycles
+const maxParentDepth = 16
+
+// Read reads the effective model of a POM file
+func Read(path string) (*Model, error) {
+	return read(path, 0)
+}
+
+func read(path string, depth int) (*Model, error) {
+	doc, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	root := doc.Root()
+	if root.Name != "project" {
+		return nil, fmt.Errorf("%s is not a POM: root element is <%s>", path, root.Name)
+	}
+
+	m := &Model{
+		Path:        path,
+		GroupID:     root.ChildText("groupId"),
+		ArtifactID:  root.ChildText("artifactId"),
+		Version:     root.ChildText("version"),
+		Packaging:   root.ChildText("packaging"),
+		Name:        root.ChildText("name"),
+		Description: root.ChildText("description"),
+		Properties:  map[string]string{},
+		Modules:     doc.Modules(),
+	}
+	if m.Packaging == "" {
+		m.Packaging = "jar"
+	}
+
+	if p := root.Child("parent"); p != nil {
+		m.Parent = &ParentRef{
+			GroupID:      p.ChildText("groupId"),
+			ArtifactID:   p.ChildText("artifactId"),
+			Version:      p.ChildText("version"),
+			RelativePath: "../pom.xml",
+		}
+		if rp := p.Child("relativePath"); rp != nil {
+			m.Parent.RelativePath = rp.Text()
+		}
+		if depth < maxParentDepth {
+			m.ParentModel = readParent(path, m.Parent, depth)
+		}
+
+		// Coordinates not declared are inherited from the parent
+		if m.GroupID == "" {
+			m.GroupID = m.Parent.GroupID
+		}
+		if m.Version == "" {
+			m.Version = m.Parent.Version
+		}
+	}
+
+	if m.ParentModel != nil {
+		for k, v := range m.ParentModel.Properties {
+			m.Properties[k] = v
+		}
+	}
+	for _, prop := range doc.Properties() {
+		m.Properties[prop[0]] = prop[1]
+	}
+
+	m.GroupID = m.Resolve(m.GroupID)
+	m.ArtifactID = m.Resolve(m.ArtifactID)
+	m.Version = m.Resolve(m.Version)
+	m.Name = m.Resolve(m.Name)
+	m.Description = m.Resolve(m.Description)
+
+	m.DependencyManagement = m.resolveDependencies(doc.ManagedDependencies())
+	m.Dependencies = m.resolveDependencies(doc.Dependencies())
+	if m.ParentModel != nil {
+		m.DependencyManagement = append(m.DependencyManagement, m.ParentModel.DependencyManagement...)
+	}
+
+	return m, nil
+}
+
+// readParent loads the parent POM from its relative path. It returns nil when
+// the file is missing or holds another artifact, as for a parent that only
+// lives in a repository.
+func readParent(path string, ref *ParentRef, depth int) *Model {
+	if ref.RelativePath == "" {
+		return nil
+	}
+	parentPath := filepath.Join(filepath.Dir(path), filepath.FromSlash(ref.RelativePath))
+	if utils.DirExists(parentPath) {
+		parentPath = filepath.Join(parentPath, "pom.xml")
+	}
+	if !utils.FileExists(parentPath) {
+		return nil
+	}
+
+	parent, err := read(parentPath, depth+1)
+	if err != nil || parent.ArtifactID != ref.ArtifactID || (ref.GroupID != "" && parent.GroupID != ref.GroupID) {
+		return nil
+	}
+	return parent
+}
+
+func (m *Model) resolveDependencies(deps []Dependency) []Dependency {
+	for i := range deps {
+		deps[i].GroupID = m.Resolve(deps[i].GroupID)
+		deps[i].ArtifactID = m.Resolve(deps[i].ArtifactID)
+		deps[i].Version = m.Resolve(deps[i].Version)
+		deps[i].Scope = m.Resolve(deps[i].Scope)
+		deps[i].Type = m.Resolve(deps[i].Type)
+	}
+	return deps
+}
+
+// Resolve replaces ${...} expressions with project coordinates and
+// properties. Unknown expressions are left unchanged.
+func (m *Model) Resolve(value string) string {
+	return m.resolve(value, 0)
+}
+
+func (m *Model) resolve(value string, depth int) string {
+	if depth > 10 || !strings.Contains(value, "${") {
+		return value
+	}
+	return expressionRe.ReplaceAllStringFunc(value, func(expr string) string {
+		key := expr[2 : len(expr)-1]
+		if v, ok := m.lookup(key); ok {
+			return m.resolve(v, depth+1)
+		}
+		return expr
+	})
+}
+
+func (m *Model) lookup(key string) (string, bool) {
+	switch strings.TrimPrefix(strings.TrimPrefix(key, "project."), "pom.") {
+	case "groupId":
+		return m.GroupID, m.GroupID != ""
+	case "artifactId":
+		return m.ArtifactID, m.ArtifactID != ""
+	case "version":
+		return m.Version, m.Version != ""
+	case "name":
+		return m.Name, m.Name != ""
+	case "description":
+		return m.Description, m.Description != ""
+	case "basedir":
+		return filepath.Dir(m.Path), true
+	case "parent.groupId":
+		return m.parentField(func(p *ParentRef) string { return p.GroupID })
+	case "parent.artifactId":
+		return m.parentField(func(p *ParentRef) string { return p.ArtifactID })
+	case "parent.version":
+		return m.parentField(func(p *ParentRef) string { return p.Version })
+	}
+	v, ok := m.Properties[key]
+	return v, ok
+}
+
+func (m *Model) parentField(field func(*ParentRef) string) (string, bool) {
+	if m.Parent == nil {
+		return "", false
+	}
+	v := field(m.Parent)
+	return v, v != ""
+}
+
+// Property returns a resolved property value
+func (m *Model) Property(name string) (string, bool) {
+	v, ok := m.Properties[name]
+	if !ok {
+		return "", false
+	}
+	return m.Resolve(v), true
+}
+
+// HasModule reports whether the model aggregates the module in dir
+func (m *Model) HasModule(dir string) bool {
+	base := filepath.Dir(m.Path)
+	for _, module := range m.Modules {
+		if filepath.Clean(filepath.Join(base, filepath.FromSlash(module))) == filepath.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
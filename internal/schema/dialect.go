@@ -0,0 +1,489 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported database names
+const (
+	MySQL      = "mysql"
+	PostgreSQL = "postgres"
+	MariaDB    = "mariadb"
+	H2         = "h2"
+)
+
+// Dialect renders DDL for a database
+type Dialect interface {
+	Name() string
+	// CreateTable returns the statements creating the table with its indexes
+	// and comments
+	CreateTable(t Table) string
+	// Migrate returns the statements applying a change to an existing schema
+	Migrate(c Change) string
+	// Insert returns a statement inserting rows of values, see Literal
+	Insert(t Table, columns []string, rows [][]any) string
+	// DeleteRange returns a statement deleting the rows whose integer column
+	// lies between from and to
+	DeleteRange(t Table, column string, from, to int64) string
+	// DeleteKeys returns a statement deleting the rows whose key columns
+	// hold one of the given tuples of values
+	DeleteKeys(t Table, columns []string, keys [][]any) string
+	// RestartIdentity returns the statements making a generated column
+	// continue after explicitly inserted values up to next-1, or "" when the
+	// database does so by itself
+	RestartIdentity(t Table, column string, next int64) string
+}
+
+// Number is a numeric literal kept as written, e.g. a decimal 12.50
+type Number string
+
+// Literal renders a value as an SQL literal: nil as NULL, strings quoted,
+// integers, booleans and Number as written
+func Literal(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return quoteString(v)
+	case Number:
+		return string(v)
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// insert renders a multi-row INSERT with the given identifier quoting
+func insert(table string, columns []string, rows [][]any, quote func(string) string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES\n", quote(table), joinColumns(columns, quote))
+	for i, row := range rows {
+		values := make([]string, len(row))
+		for j, v := range row {
+			values[j] = Literal(v)
+		}
+		sep := ","
+		if i == len(rows)-1 {
+			sep = ";"
+		}
+		fmt.Fprintf(&b, "    (%s)%s\n", strings.Join(values, ", "), sep)
+	}
+	return b.String()
+}
+
+// deleteKeys renders a DELETE of the rows matching a list of keys, with a
+// row value constructor for keys of several columns
+func deleteKeys(table string, columns []string, keys [][]any, quote func(string) string) string {
+	column := quote(columns[0])
+	if len(columns) > 1 {
+		column = "(" + joinColumns(columns, quote) + ")"
+	}
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		literals := make([]string, len(key))
+		for j, v := range key {
+			literals[j] = Literal(v)
+		}
+		values[i] = strings.Join(literals, ", ")
+		if len(key) > 1 {
+			values[i] = "(" + values[i] + ")"
+		}
+	}
+	return fmt.Sprintf("DELETE FROM %s WHERE %s IN (\n    %s\n);\n", quote(table), column, strings.Join(values, ",\n    "))
+}
+
+var dialects = []Dialect{
+	mysqlDialect{name: MySQL},
+	standardDialect{name: PostgreSQL, tinyInt: "SMALLINT", text: "TEXT", decimal: "NUMERIC"},
+	mysqlDialect{name: MariaDB},
+	standardDialect{name: H2, tinyInt: "TINYINT", text: "CLOB", decimal: "DECIMAL", onUpdate: true, restartIdentity: true},
+}
+
+// Lookup returns the dialect of a database name
+func Lookup(name string) (Dialect, bool) {
+	for _, d := range dialects {
+		if d.Name() == name {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// Names returns the supported database names
+func Names() []string {
+	var names []string
+	for _, d := range dialects {
+		names = append(names, d.Name())
+	}
+	return names
+}
+
+// quoteString renders an SQL string literal
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func joinColumns(columns []string, quote func(string) string) string {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = quote(c)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// mysqlDialect renders MySQL and MariaDB DDL: quoted identifiers, inline
+// keys and comments, InnoDB with utf8mb4
+type mysqlDialect struct {
+	name string
+}
+
+func (d mysqlDialect) Name() string { return d.name }
+
+func (d mysqlDialect) quote(identifier string) string {
+	return "`" + identifier + "`"
+}
+
+func (d mysqlDialect) columnType(c Column) string {
+	switch c.Type {
+	case BigInt:
+		return "BIGINT"
+	case Int:
+		return "INT"
+	case TinyInt:
+		return "TINYINT"
+	case Varchar:
+		return fmt.Sprintf("VARCHAR(%d)", c.Length)
+	case Text:
+		return "TEXT"
+	case Decimal:
+		return fmt.Sprintf("DECIMAL(%d,%d)", c.Length, c.Scale)
+	case Boolean:
+		return "BOOLEAN"
+	case Date:
+		return "DATE"
+	default:
+		return "DATETIME"
+	}
+}
+
+func (d mysqlDialect) columnDef(c Column) string {
+	def := d.quote(c.Name) + " " + d.columnType(c)
+	if c.NotNull {
+		def += " NOT NULL"
+	}
+	if c.AutoIncrement {
+		def += " AUTO_INCREMENT"
+	}
+	if c.Default != "" {
+		def += " DEFAULT " + c.Default
+	}
+	if c.OnUpdateCurrentTimestamp {
+		def += " ON UPDATE " + CurrentTimestamp
+	}
+	if c.Comment != "" {
+		def += " COMMENT " + quoteString(c.Comment)
+	}
+	return def
+}
+
+func (d mysqlDialect) indexDef(idx Index) string {
+	kind := "KEY"
+	if idx.Unique {
+		kind = "UNIQUE KEY"
+	}
+	return fmt.Sprintf("%s %s (%s)", kind, d.quote(idx.Name), joinColumns(idx.Columns, d.quote))
+}
+
+func (d mysqlDialect) CreateTable(t Table) string {
+	var lines []string
+	for _, c := range t.Columns {
+		lines = append(lines, d.columnDef(c))
+	}
+	if len(t.PrimaryKey) > 0 {
+		lines = append(lines, fmt.Sprintf("PRIMARY KEY (%s)", joinColumns(t.PrimaryKey, d.quote)))
+	}
+	for _, idx := range t.Indexes {
+		lines = append(lines, d.indexDef(idx))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE IF NOT EXISTS %s (\n    %s\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4", d.quote(t.Name), strings.Join(lines, ",\n    "))
+	if t.Comment != "" {
+		b.WriteString(" COMMENT=" + quoteString(t.Comment))
+	}
+	b.WriteString(";\n")
+	return b.String()
+}
+
+func (d mysqlDialect) Migrate(c Change) string {
+	table := d.quote(c.Table.Name)
+	switch c.Kind {
+	case CreateTable:
+		return d.CreateTable(c.Table)
+	case DropTable:
+		return fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", table)
+	case AddColumn:
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;\n", table, d.columnDef(c.Column))
+	case DropColumn:
+		return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;\n", table, d.quote(c.Column.Name))
+	case ModifyColumn:
+		return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;\n", table, d.columnDef(c.Column))
+	case AddIndex:
+		return fmt.Sprintf("ALTER TABLE %s ADD %s;\n", table, d.indexDef(c.Index))
+	default:
+		return fmt.Sprintf("ALTER TABLE %s DROP INDEX %s;\n", table, d.quote(c.Index.Name))
+	}
+}
+
+func (d mysqlDialect) Insert(t Table, columns []string, rows [][]any) string {
+	return insert(t.Name, columns, rows, d.quote)
+}
+
+func (d mysqlDialect) DeleteRange(t Table, column string, from, to int64) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE %s BETWEEN %d AND %d;\n", d.quote(t.Name), d.quote(column), from, to)
+}
+
+func (d mysqlDialect) DeleteKeys(t Table, columns []string, keys [][]any) string {
+	return deleteKeys(t.Name, columns, keys, d.quote)
+}
+
+// RestartIdentity is not needed: AUTO_INCREMENT moves past inserted values
+func (d mysqlDialect) RestartIdentity(t Table, column string, next int64) string {
+	return ""
+}
+
+// standardDialect renders DDL close to the SQL standard, as used by
+// PostgreSQL and H2: identity columns, separate index statements and
+// COMMENT ON statements
+type standardDialect struct {
+	name    string
+	tinyInt string
+	text    string
+	decimal string
+	// onUpdate tells whether column definitions support ON UPDATE. Without
+	// it, update times are maintained by a trigger.
+	onUpdate bool
+	// restartIdentity tells whether identity columns are restarted with
+	// ALTER COLUMN ... RESTART WITH. Without it, their sequence is set.
+	restartIdentity bool
+}
+
+func (d standardDialect) Name() string { return d.name }
+
+func (d standardDialect) columnType(c Column) string {
+	switch c.Type {
+	case BigInt:
+		return "BIGINT"
+	case Int:
+		return "INTEGER"
+	case TinyInt:
+		return d.tinyInt
+	case Varchar:
+		return fmt.Sprintf("VARCHAR(%d)", c.Length)
+	case Text:
+		return d.text
+	case Decimal:
+		return fmt.Sprintf("%s(%d,%d)", d.decimal, c.Length, c.Scale)
+	case Boolean:
+		return "BOOLEAN"
+	case Date:
+		return "DATE"
+	default:
+		return "TIMESTAMP"
+	}
+}
+
+// columnDef renders a column definition. Without ON UPDATE support, the
+// caller adds the update trigger.
+func (d standardDialect) columnDef(c Column) string {
+	def := c.Name + " " + d.columnType(c)
+	if c.AutoIncrement {
+		def += " GENERATED BY DEFAULT AS IDENTITY"
+	} else if c.Default != "" {
+		def += " DEFAULT " + c.Default
+	}
+	if c.OnUpdateCurrentTimestamp && d.onUpdate {
+		def += " ON UPDATE " + CurrentTimestamp
+	}
+	if c.NotNull {
+		def += " NOT NULL"
+	}
+	return def
+}
+
+func (d standardDialect) indexDef(table string, idx Index) string {
+	kind := "INDEX"
+	if idx.Unique {
+		kind = "UNIQUE INDEX"
+	}
+	return fmt.Sprintf("CREATE %s IF NOT EXISTS %s ON %s (%s);\n", kind, idx.Name, table, strings.Join(idx.Columns, ", "))
+}
+
+func (d standardDialect) CreateTable(t Table) string {
+	var lines, triggers []string
+	for _, c := range t.Columns {
+		if c.OnUpdateCurrentTimestamp && !d.onUpdate {
+			triggers = append(triggers, d.updateTrigger(t.Name, c.Name))
+		}
+		lines = append(lines, d.columnDef(c))
+	}
+	if len(t.PrimaryKey) > 0 {
+		lines = append(lines, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(t.PrimaryKey, ", ")))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE IF NOT EXISTS %s (\n    %s\n);\n", t.Name, strings.Join(lines, ",\n    "))
+
+	if len(t.Indexes) > 0 {
+		b.WriteString("\n")
+	}
+	for _, idx := range t.Indexes {
+		b.WriteString(d.indexDef(t.Name, idx))
+	}
+
+	var comments []string
+	if t.Comment != "" {
+		comments = append(comments, fmt.Sprintf("COMMENT ON TABLE %s IS %s;", t.Name, quoteString(t.Comment)))
+	}
+	for _, c := range t.Columns {
+		if c.Comment != "" {
+			comments = append(comments, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", t.Name, c.Name, quoteString(c.Comment)))
+		}
+	}
+	if len(comments) > 0 {
+		b.WriteString("\n" + strings.Join(comments, "\n") + "\n")
+	}
+
+	for _, trigger := range triggers {
+		b.WriteString("\n" + trigger)
+	}
+	return b.String()
+}
+
+func (d standardDialect) Migrate(c Change) string {
+	table := c.Table.Name
+	switch c.Kind {
+	case CreateTable:
+		return d.CreateTable(c.Table)
+	case DropTable:
+		return fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", table)
+	case AddColumn:
+		s := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;\n", table, d.columnDef(c.Column))
+		if c.Column.Comment != "" {
+			s += d.columnComment(table, c.Column)
+		}
+		if c.Column.OnUpdateCurrentTimestamp && !d.onUpdate {
+			s += "\n" + d.updateTrigger(table, c.Column.Name)
+		}
+		return s
+	case DropColumn:
+		s := ""
+		if c.Column.OnUpdateCurrentTimestamp && !d.onUpdate {
+			s += d.dropUpdateTrigger(table, c.Column.Name)
+		}
+		return s + fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;\n", table, c.Column.Name)
+	case ModifyColumn:
+		return d.modifyColumn(table, c.OldColumn, c.Column)
+	case AddIndex:
+		return d.indexDef(table, c.Index)
+	default:
+		return fmt.Sprintf("DROP INDEX IF EXISTS %s;\n", c.Index.Name)
+	}
+}
+
+func (d standardDialect) Insert(t Table, columns []string, rows [][]any) string {
+	return insert(t.Name, columns, rows, func(s string) string { return s })
+}
+
+func (d standardDialect) DeleteRange(t Table, column string, from, to int64) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE %s BETWEEN %d AND %d;\n", t.Name, column, from, to)
+}
+
+func (d standardDialect) DeleteKeys(t Table, columns []string, keys [][]any) string {
+	return deleteKeys(t.Name, columns, keys, func(s string) string { return s })
+}
+
+func (d standardDialect) RestartIdentity(t Table, column string, next int64) string {
+	if d.restartIdentity {
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s RESTART WITH %d;\n", t.Name, column, next)
+	}
+	return fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', '%s'), GREATEST(%d, (SELECT COALESCE(MAX(%s), 0) FROM %s)));\n",
+		t.Name, column, next-1, column, t.Name)
+}
+
+// modifyColumn renders one ALTER COLUMN statement per changed attribute.
+// Identity columns are left as they are.
+func (d standardDialect) modifyColumn(table string, from, to Column) string {
+	var b strings.Builder
+	alter := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s", table, to.Name)
+	if d.columnType(from) != d.columnType(to) {
+		fmt.Fprintf(&b, "%s SET DATA TYPE %s;\n", alter, d.columnType(to))
+	}
+	if from.Default != to.Default && !to.AutoIncrement {
+		if to.Default == "" {
+			fmt.Fprintf(&b, "%s DROP DEFAULT;\n", alter)
+		} else {
+			fmt.Fprintf(&b, "%s SET DEFAULT %s;\n", alter, to.Default)
+		}
+	}
+	if from.NotNull != to.NotNull {
+		if to.NotNull {
+			fmt.Fprintf(&b, "%s SET NOT NULL;\n", alter)
+		} else {
+			fmt.Fprintf(&b, "%s DROP NOT NULL;\n", alter)
+		}
+	}
+	if from.Comment != to.Comment {
+		b.WriteString(d.columnComment(table, to))
+	}
+	if from.OnUpdateCurrentTimestamp != to.OnUpdateCurrentTimestamp {
+		switch {
+		case d.onUpdate && to.OnUpdateCurrentTimestamp:
+			fmt.Fprintf(&b, "%s SET ON UPDATE %s;\n", alter, CurrentTimestamp)
+		case d.onUpdate:
+			fmt.Fprintf(&b, "%s DROP ON UPDATE;\n", alter)
+		case to.OnUpdateCurrentTimestamp:
+			b.WriteString("\n" + d.updateTrigger(table, to.Name))
+		default:
+			b.WriteString(d.dropUpdateTrigger(table, to.Name))
+		}
+	}
+	return b.String()
+}
+
+// columnComment renders the COMMENT ON statement of a column, removing the
+// comment when it is empty
+func (d standardDialect) columnComment(table string, c Column) string {
+	comment := "NULL"
+	if c.Comment != "" {
+		comment = quoteString(c.Comment)
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;\n", table, c.Name, comment)
+}
+
+// updateTrigger renders a PostgreSQL trigger setting a column to the current
+// time on every update, the equivalent of MySQL's ON UPDATE CURRENT_TIMESTAMP
+func (d standardDialect) updateTrigger(table, column string) string {
+	function := fmt.Sprintf("%s_set_%s", table, column)
+	return fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.%s = %s;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER trg_%s BEFORE UPDATE ON %s
+    FOR EACH ROW EXECUTE FUNCTION %s();
+`, function, column, CurrentTimestamp, function, table, function)
+}
+
+// dropUpdateTrigger removes the trigger and function created by updateTrigger
+func (d standardDialect) dropUpdateTrigger(table, column string) string {
+	function := fmt.Sprintf("%s_set_%s", table, column)
+	return fmt.Sprintf("DROP TRIGGER IF EXISTS trg_%s ON %s;\nDROP FUNCTION IF EXISTS %s();\n", function, table, function)
+}
@@ -0,0 +1,276 @@
+package schema
+
+import (
+	"testing"
+)
+
+const pgUpdateTrigger = `CREATE OR REPLACE FUNCTION t_order_set_update_time() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.update_time = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER trg_t_order_set_update_time BEFORE UPDATE ON t_order
+    FOR EACH ROW EXECUTE FUNCTION t_order_set_update_time();
+`
+
+const pgDropUpdateTrigger = `DROP TRIGGER IF EXISTS trg_t_order_set_update_time ON t_order;
+DROP FUNCTION IF EXISTS t_order_set_update_time();
+`
+
+func TestMigrateColumns(t *testing.T) {
+	amount := Column{Name: "amount", Type: Decimal, Length: 12, Scale: 2, NotNull: true, Default: "0", Comment: "金额"}
+	status := Column{Name: "status", Type: TinyInt, NotNull: true, Default: "1"}
+	updateTime := Column{Name: "update_time", Type: DateTime, NotNull: true, Default: CurrentTimestamp, OnUpdateCurrentTimestamp: true}
+	remark := Column{Name: "remark", Type: Varchar, Length: 200}
+	requiredRemark := Column{Name: "remark", Type: Varchar, Length: 500, NotNull: true, Comment: "备注"}
+	noDefaultStatus := status
+	noDefaultStatus.Default = ""
+
+	tests := []struct {
+		name   string
+		change Change
+		want   map[string]string
+	}{
+		{
+			name:   "add column",
+			change: Change{Kind: AddColumn, Table: orderTable, Column: amount},
+			want: map[string]string{
+				MySQL:      "ALTER TABLE `t_order` ADD COLUMN `amount` DECIMAL(12,2) NOT NULL DEFAULT 0 COMMENT '金额';\n",
+				MariaDB:    "ALTER TABLE `t_order` ADD COLUMN `amount` DECIMAL(12,2) NOT NULL DEFAULT 0 COMMENT '金额';\n",
+				PostgreSQL: "ALTER TABLE t_order ADD COLUMN amount NUMERIC(12,2) DEFAULT 0 NOT NULL;\nCOMMENT ON COLUMN t_order.amount IS '金额';\n",
+				H2:         "ALTER TABLE t_order ADD COLUMN amount DECIMAL(12,2) DEFAULT 0 NOT NULL;\nCOMMENT ON COLUMN t_order.amount IS '金额';\n",
+			},
+		},
+		{
+			name:   "add tinyint column",
+			change: Change{Kind: AddColumn, Table: orderTable, Column: status},
+			want: map[string]string{
+				MySQL:      "ALTER TABLE `t_order` ADD COLUMN `status` TINYINT NOT NULL DEFAULT 1;\n",
+				MariaDB:    "ALTER TABLE `t_order` ADD COLUMN `status` TINYINT NOT NULL DEFAULT 1;\n",
+				PostgreSQL: "ALTER TABLE t_order ADD COLUMN status SMALLINT DEFAULT 1 NOT NULL;\n",
+				H2:         "ALTER TABLE t_order ADD COLUMN status TINYINT DEFAULT 1 NOT NULL;\n",
+			},
+		},
+		{
+			name:   "add update time",
+			change: Change{Kind: AddColumn, Table: orderTable, Column: updateTime},
+			want: map[string]string{
+				MySQL:      "ALTER TABLE `t_order` ADD COLUMN `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;\n",
+				MariaDB:    "ALTER TABLE `t_order` ADD COLUMN `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;\n",
+				PostgreSQL: "ALTER TABLE t_order ADD COLUMN update_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL;\n\n" + pgUpdateTrigger,
+				H2:         "ALTER TABLE t_order ADD COLUMN update_time TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP NOT NULL;\n",
+			},
+		},
+		{
+			name:   "drop column",
+			change: Change{Kind: DropColumn, Table: orderTable, Column: remark},
+			want: map[string]string{
+				MySQL:      "ALTER TABLE `t_order` DROP COLUMN `remark`;\n",
+				MariaDB:    "ALTER TABLE `t_order` DROP COLUMN `remark`;\n",
+				PostgreSQL: "ALTER TABLE t_order DROP COLUMN remark;\n",
+				H2:         "ALTER TABLE t_order DROP COLUMN remark;\n",
+			},
+		},
+		{
+			name:   "drop update time",
+			change: Change{Kind: DropColumn, Table: orderTable, Column: updateTime},
+			want: map[string]string{
+				MySQL:      "ALTER TABLE `t_order` DROP COLUMN `update_time`;\n",
+				MariaDB:    "ALTER TABLE `t_order` DROP COLUMN `update_time`;\n",
+				PostgreSQL: pgDropUpdateTrigger + "ALTER TABLE t_order DROP COLUMN update_time;\n",
+				H2:         "ALTER TABLE t_order DROP COLUMN update_time;\n",
+			},
+		},
+		{
+			name:   "alter type, nullability and comment",
+			change: Change{Kind: ModifyColumn, Table: orderTable, Column: requiredRemark, OldColumn: remark},
+			want: map[string]string{
+				MySQL:   "ALTER TABLE `t_order` MODIFY COLUMN `remark` VARCHAR(500) NOT NULL COMMENT '备注';\n",
+				MariaDB: "ALTER TABLE `t_order` MODIFY COLUMN `remark` VARCHAR(500) NOT NULL COMMENT '备注';\n",
+				PostgreSQL: "ALTER TABLE t_order ALTER COLUMN remark SET DATA TYPE VARCHAR(500);\n" +
+					"ALTER TABLE t_order ALTER COLUMN remark SET NOT NULL;\n" +
+					"COMMENT ON COLUMN t_order.remark IS '备注';\n",
+				H2: "ALTER TABLE t_order ALTER COLUMN remark SET DATA TYPE VARCHAR(500);\n" +
+					"ALTER TABLE t_order ALTER COLUMN remark SET NOT NULL;\n" +
+					"COMMENT ON COLUMN t_order.remark IS '备注';\n",
+			},
+		},
+		{
+			name:   "alter back",
+			change: Change{Kind: ModifyColumn, Table: orderTable, Column: remark, OldColumn: requiredRemark},
+			want: map[string]string{
+				MySQL:   "ALTER TABLE `t_order` MODIFY COLUMN `remark` VARCHAR(200);\n",
+				MariaDB: "ALTER TABLE `t_order` MODIFY COLUMN `remark` VARCHAR(200);\n",
+				PostgreSQL: "ALTER TABLE t_order ALTER COLUMN remark SET DATA TYPE VARCHAR(200);\n" +
+					"ALTER TABLE t_order ALTER COLUMN remark DROP NOT NULL;\n" +
+					"COMMENT ON COLUMN t_order.remark IS NULL;\n",
+				H2: "ALTER TABLE t_order ALTER COLUMN remark SET DATA TYPE VARCHAR(200);\n" +
+					"ALTER TABLE t_order ALTER COLUMN remark DROP NOT NULL;\n" +
+					"COMMENT ON COLUMN t_order.remark IS NULL;\n",
+			},
+		},
+		{
+			name:   "drop default",
+			change: Change{Kind: ModifyColumn, Table: orderTable, Column: noDefaultStatus, OldColumn: status},
+			want: map[string]string{
+				MySQL:      "ALTER TABLE `t_order` MODIFY COLUMN `status` TINYINT NOT NULL;\n",
+				MariaDB:    "ALTER TABLE `t_order` MODIFY COLUMN `status` TINYINT NOT NULL;\n",
+				PostgreSQL: "ALTER TABLE t_order ALTER COLUMN status DROP DEFAULT;\n",
+				H2:         "ALTER TABLE t_order ALTER COLUMN status DROP DEFAULT;\n",
+			},
+		},
+		{
+			name:   "add on update",
+			change: Change{Kind: ModifyColumn, Table: orderTable, Column: updateTime, OldColumn: Column{Name: "update_time", Type: DateTime, NotNull: true, Default: CurrentTimestamp}},
+			want: map[string]string{
+				MySQL:      "ALTER TABLE `t_order` MODIFY COLUMN `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;\n",
+				MariaDB:    "ALTER TABLE `t_order` MODIFY COLUMN `update_time` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;\n",
+				PostgreSQL: "\n" + pgUpdateTrigger,
+				H2:         "ALTER TABLE t_order ALTER COLUMN update_time SET ON UPDATE CURRENT_TIMESTAMP;\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		for _, name := range Names() {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				d, _ := Lookup(name)
+				if got := d.Migrate(tt.change); got != tt.want[name] {
+					t.Errorf("Migrate\n--- got ---\n%s\n--- want ---\n%s", got, tt.want[name])
+				}
+			})
+		}
+	}
+}
+
+func TestMigrateIndexes(t *testing.T) {
+	idx := Index{Name: "uk_order_no", Columns: []string{"order_no", "user_id"}, Unique: true}
+	tests := []struct {
+		name   string
+		change Change
+		want   map[string]string
+	}{
+		{
+			name:   "add index",
+			change: Change{Kind: AddIndex, Table: orderTable, Index: idx},
+			want: map[string]string{
+				MySQL:      "ALTER TABLE `t_order` ADD UNIQUE KEY `uk_order_no` (`order_no`, `user_id`);\n",
+				MariaDB:    "ALTER TABLE `t_order` ADD UNIQUE KEY `uk_order_no` (`order_no`, `user_id`);\n",
+				PostgreSQL: "CREATE UNIQUE INDEX IF NOT EXISTS uk_order_no ON t_order (order_no, user_id);\n",
+				H2:         "CREATE UNIQUE INDEX IF NOT EXISTS uk_order_no ON t_order (order_no, user_id);\n",
+			},
+		},
+		{
+			name:   "drop index",
+			change: Change{Kind: DropIndex, Table: orderTable, Index: idx},
+			want: map[string]string{
+				MySQL:      "ALTER TABLE `t_order` DROP INDEX `uk_order_no`;\n",
+				MariaDB:    "ALTER TABLE `t_order` DROP INDEX `uk_order_no`;\n",
+				PostgreSQL: "DROP INDEX IF EXISTS uk_order_no;\n",
+				H2:         "DROP INDEX IF EXISTS uk_order_no;\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		for _, name := range Names() {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				d, _ := Lookup(name)
+				if got := d.Migrate(tt.change); got != tt.want[name] {
+					t.Errorf("Migrate\n--- got ---\n%s\n--- want ---\n%s", got, tt.want[name])
+				}
+			})
+		}
+	}
+}
+
+func TestDeleteKeys(t *testing.T) {
+	single := [][]any{{"0190163d-8694-739b-aea5-966c26f8ad91"}, {"0190163d-8694-7c4a-9f3e-0d2a4b6c8e10"}}
+	composite := [][]any{{int64(1), "admin"}, {int64(2), "user"}}
+	tests := []struct {
+		name    string
+		columns []string
+		keys    [][]any
+		want    map[string]string
+	}{
+		{
+			name:    "single column",
+			columns: []string{"id"},
+			keys:    single,
+			want: map[string]string{
+				MySQL:      "DELETE FROM `t_order` WHERE `id` IN (\n    '0190163d-8694-739b-aea5-966c26f8ad91',\n    '0190163d-8694-7c4a-9f3e-0d2a4b6c8e10'\n);\n",
+				PostgreSQL: "DELETE FROM t_order WHERE id IN (\n    '0190163d-8694-739b-aea5-966c26f8ad91',\n    '0190163d-8694-7c4a-9f3e-0d2a4b6c8e10'\n);\n",
+			},
+		},
+		{
+			name:    "several columns",
+			columns: []string{"user_id", "role"},
+			keys:    composite,
+			want: map[string]string{
+				MySQL:      "DELETE FROM `t_order` WHERE (`user_id`, `role`) IN (\n    (1, 'admin'),\n    (2, 'user')\n);\n",
+				PostgreSQL: "DELETE FROM t_order WHERE (user_id, role) IN (\n    (1, 'admin'),\n    (2, 'user')\n);\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.want[MariaDB] = tt.want[MySQL]
+		tt.want[H2] = tt.want[PostgreSQL]
+		for _, name := range Names() {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				d, _ := Lookup(name)
+				if got := d.DeleteKeys(orderTable, tt.columns, tt.keys); got != tt.want[name] {
+					t.Errorf("DeleteKeys\n--- got ---\n%s\n--- want ---\n%s", got, tt.want[name])
+				}
+			})
+		}
+	}
+}
+
+func TestCreateTable(t *testing.T) {
+	want := map[string]string{
+		MySQL: "CREATE TABLE IF NOT EXISTS `t_order` (\n" +
+			"    `id` BIGINT NOT NULL AUTO_INCREMENT,\n" +
+			"    `user_id` BIGINT NOT NULL,\n" +
+			"    `remark` VARCHAR(200),\n" +
+			"    PRIMARY KEY (`id`),\n" +
+			"    KEY `idx_user_id` (`user_id`)\n" +
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n",
+		PostgreSQL: "CREATE TABLE IF NOT EXISTS t_order (\n" +
+			"    id BIGINT GENERATED BY DEFAULT AS IDENTITY NOT NULL,\n" +
+			"    user_id BIGINT NOT NULL,\n" +
+			"    remark VARCHAR(200),\n" +
+			"    PRIMARY KEY (id)\n" +
+			");\n" +
+			"\n" +
+			"CREATE INDEX IF NOT EXISTS idx_user_id ON t_order (user_id);\n",
+	}
+	want[MariaDB] = want[MySQL]
+	want[H2] = want[PostgreSQL]
+
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			d, _ := Lookup(name)
+			change := Change{Kind: CreateTable, Table: orderTable}
+			if got := d.Migrate(change); got != want[name] {
+				t.Errorf("CreateTable\n--- got ---\n%s\n--- want ---\n%s", got, want[name])
+			}
+		})
+	}
+}
+
+func TestColumnTypeText(t *testing.T) {
+	for typ := BigInt; typ <= DateTime; typ++ {
+		text, err := typ.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded ColumnType
+		if err := decoded.UnmarshalText(text); err != nil || decoded != typ {
+			t.Errorf("UnmarshalText(%s) = %v, %v, want %v", text, decoded, err, typ)
+		}
+	}
+	var decoded ColumnType
+	if err := decoded.UnmarshalText([]byte("uuid")); err == nil {
+		t.Error("unknown type name accepted")
+	}
+}
@@ -0,0 +1,148 @@
+package schema
+
+import "slices"
+
+// ChangeKind is the kind of a schema change
+type ChangeKind int
+
+const (
+	CreateTable ChangeKind = iota
+	DropTable
+	AddColumn
+	DropColumn
+	// ModifyColumn changes the type, nullability, default or comment of a column
+	ModifyColumn
+	AddIndex
+	DropIndex
+)
+
+// Change is a single change between two versions of a schema
+type Change struct {
+	Kind ChangeKind
+	// Table is the table after the change, or before it for DropTable
+	Table Table
+	// Column is the added or dropped column, or the new definition of a
+	// modified one
+	Column Column
+	// OldColumn is the previous definition of a modified column
+	OldColumn Column
+	Index     Index
+}
+
+// Destructive reports whether applying the change may lose data: dropping a
+// table or column, or changing a column to a type that cannot hold all of
+// its current values
+func (c Change) Destructive() bool {
+	switch c.Kind {
+	case DropTable, DropColumn:
+		return true
+	case ModifyColumn:
+		return !widens(c.OldColumn, c.Column)
+	}
+	return false
+}
+
+// widens reports whether every value of the old column fits the new one
+func widens(from, to Column) bool {
+	if from.Type == to.Type {
+		switch from.Type {
+		case Varchar:
+			return to.Length >= from.Length
+		case Decimal:
+			return to.Scale >= from.Scale && to.Length-to.Scale >= from.Length-from.Scale
+		}
+		return true
+	}
+	switch to.Type {
+	case Int:
+		return from.Type == TinyInt
+	case BigInt:
+		return from.Type == TinyInt || from.Type == Int
+	case Text:
+		return from.Type == Varchar
+	case DateTime:
+		return from.Type == Date
+	}
+	return false
+}
+
+// Diff returns the changes turning the tables from into the tables to.
+// Tables, columns and indexes are matched by name, so a rename shows up as
+// a drop and an add. Within a table, indexes are dropped before columns
+// change and added after, so that they never refer to a missing column.
+func Diff(from, to []Table) []Change {
+	var changes []Change
+	for _, t := range to {
+		old, ok := findTable(from, t.Name)
+		if !ok {
+			changes = append(changes, Change{Kind: CreateTable, Table: t})
+			continue
+		}
+		changes = append(changes, diffTable(old, t)...)
+	}
+	for _, t := range from {
+		if _, ok := findTable(to, t.Name); !ok {
+			changes = append(changes, Change{Kind: DropTable, Table: t})
+		}
+	}
+	return changes
+}
+
+func diffTable(from, to Table) []Change {
+	var changes []Change
+	for _, idx := range from.Indexes {
+		if n, ok := findIndex(to.Indexes, idx.Name); !ok || !sameIndex(idx, n) {
+			changes = append(changes, Change{Kind: DropIndex, Table: to, Index: idx})
+		}
+	}
+	for _, c := range from.Columns {
+		if _, ok := findColumn(to.Columns, c.Name); !ok {
+			changes = append(changes, Change{Kind: DropColumn, Table: to, Column: c})
+		}
+	}
+	for _, c := range to.Columns {
+		old, ok := findColumn(from.Columns, c.Name)
+		if !ok {
+			changes = append(changes, Change{Kind: AddColumn, Table: to, Column: c})
+		} else if !sameDefinition(old, c) {
+			changes = append(changes, Change{Kind: ModifyColumn, Table: to, Column: c, OldColumn: old})
+		}
+	}
+	for _, idx := range to.Indexes {
+		if old, ok := findIndex(from.Indexes, idx.Name); !ok || !sameIndex(old, idx) {
+			changes = append(changes, Change{Kind: AddIndex, Table: to, Index: idx})
+		}
+	}
+	return changes
+}
+
+func sameIndex(a, b Index) bool {
+	return a.Unique == b.Unique && slices.Equal(a.Columns, b.Columns)
+}
+
+func findTable(tables []Table, name string) (Table, bool) {
+	for _, t := range tables {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Table{}, false
+}
+
+func findColumn(columns []Column, name string) (Column, bool) {
+	for _, c := range columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+func findIndex(indexes []Index, name string) (Index, bool) {
+	for _, idx := range indexes {
+		if idx.Name == name {
+			return idx, true
+		}
+	}
+	return Index{}, false
+}
@@ -0,0 +1,160 @@
+package schema
+
+import (
+	"testing"
+)
+
+var orderTable = Table{
+	Name: "t_order",
+	Columns: []Column{
+		{Name: "id", Type: BigInt, NotNull: true, AutoIncrement: true},
+		{Name: "user_id", Type: BigInt, NotNull: true, References: "t_user"},
+		{Name: "remark", Type: Varchar, Length: 200},
+	},
+	PrimaryKey: []string{"id"},
+	Indexes:    []Index{{Name: "idx_user_id", Columns: []string{"user_id"}}},
+}
+
+// withColumns returns a copy of orderTable with the given columns
+func withColumns(columns ...Column) Table {
+	t := orderTable
+	t.Columns = columns
+	return t
+}
+
+func TestDiff(t *testing.T) {
+	id, userID, remark := orderTable.Columns[0], orderTable.Columns[1], orderTable.Columns[2]
+	amount := Column{Name: "amount", Type: Decimal, Length: 12, Scale: 2, NotNull: true, Default: "0"}
+	longRemark := remark
+	longRemark.Length = 500
+
+	uniqueUser := orderTable
+	uniqueUser.Indexes = []Index{{Name: "idx_user_id", Columns: []string{"user_id"}, Unique: true}}
+
+	otherReference := withColumns(id, userID, remark)
+	otherReference.Columns[1].References = "t_account.id"
+
+	tests := []struct {
+		name string
+		from []Table
+		to   []Table
+		want []Change
+	}{
+		{name: "unchanged", from: []Table{orderTable}, to: []Table{orderTable}},
+		{
+			name: "create table",
+			to:   []Table{orderTable},
+			want: []Change{{Kind: CreateTable, Table: orderTable}},
+		},
+		{
+			name: "drop table",
+			from: []Table{orderTable},
+			want: []Change{{Kind: DropTable, Table: orderTable}},
+		},
+		{
+			name: "add column",
+			from: []Table{orderTable},
+			to:   []Table{withColumns(id, userID, remark, amount)},
+			want: []Change{{Kind: AddColumn, Table: withColumns(id, userID, remark, amount), Column: amount}},
+		},
+		{
+			name: "drop column",
+			from: []Table{orderTable},
+			to:   []Table{withColumns(id, userID)},
+			want: []Change{{Kind: DropColumn, Table: withColumns(id, userID), Column: remark}},
+		},
+		{
+			name: "modify column",
+			from: []Table{orderTable},
+			to:   []Table{withColumns(id, userID, longRemark)},
+			want: []Change{{Kind: ModifyColumn, Table: withColumns(id, userID, longRemark), Column: longRemark, OldColumn: remark}},
+		},
+		{
+			// References are logical and render no DDL
+			name: "reference only",
+			from: []Table{orderTable},
+			to:   []Table{otherReference},
+		},
+		{
+			name: "index changed",
+			from: []Table{orderTable},
+			to:   []Table{uniqueUser},
+			want: []Change{
+				{Kind: DropIndex, Table: uniqueUser, Index: orderTable.Indexes[0]},
+				{Kind: AddIndex, Table: uniqueUser, Index: uniqueUser.Indexes[0]},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Diff returned %d changes, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if !sameChange(got[i], tt.want[i]) {
+					t.Errorf("change %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// sameChange compares the fields of two changes that identify them
+func sameChange(a, b Change) bool {
+	return a.Kind == b.Kind && a.Table.Name == b.Table.Name && a.Column == b.Column &&
+		a.OldColumn == b.OldColumn && a.Index.Name == b.Index.Name && sameIndex(a.Index, b.Index)
+}
+
+func TestDiffDropsIndexesBeforeColumns(t *testing.T) {
+	to := withColumns(orderTable.Columns[0], orderTable.Columns[2])
+	to.Indexes = nil
+	got := Diff([]Table{orderTable}, []Table{to})
+	if len(got) != 2 || got[0].Kind != DropIndex || got[1].Kind != DropColumn {
+		t.Errorf("Diff = %+v, want DropIndex then DropColumn", got)
+	}
+}
+
+func TestDestructive(t *testing.T) {
+	column := func(typ ColumnType, length, scale int) Column {
+		return Column{Name: "c", Type: typ, Length: length, Scale: scale}
+	}
+	nullable := column(Varchar, 50, 0)
+	notNull := nullable
+	notNull.NotNull = true
+
+	tests := []struct {
+		name   string
+		change Change
+		want   bool
+	}{
+		{"create table", Change{Kind: CreateTable}, false},
+		{"drop table", Change{Kind: DropTable}, true},
+		{"add column", Change{Kind: AddColumn}, false},
+		{"drop column", Change{Kind: DropColumn}, true},
+		{"add index", Change{Kind: AddIndex}, false},
+		{"drop index", Change{Kind: DropIndex}, false},
+		{"longer varchar", Change{Kind: ModifyColumn, OldColumn: column(Varchar, 50, 0), Column: column(Varchar, 100, 0)}, false},
+		{"shorter varchar", Change{Kind: ModifyColumn, OldColumn: column(Varchar, 100, 0), Column: column(Varchar, 50, 0)}, true},
+		{"varchar to text", Change{Kind: ModifyColumn, OldColumn: column(Varchar, 100, 0), Column: column(Text, 0, 0)}, false},
+		{"text to varchar", Change{Kind: ModifyColumn, OldColumn: column(Text, 0, 0), Column: column(Varchar, 100, 0)}, true},
+		{"int to bigint", Change{Kind: ModifyColumn, OldColumn: column(Int, 0, 0), Column: column(BigInt, 0, 0)}, false},
+		{"tinyint to int", Change{Kind: ModifyColumn, OldColumn: column(TinyInt, 0, 0), Column: column(Int, 0, 0)}, false},
+		{"bigint to int", Change{Kind: ModifyColumn, OldColumn: column(BigInt, 0, 0), Column: column(Int, 0, 0)}, true},
+		{"date to datetime", Change{Kind: ModifyColumn, OldColumn: column(Date, 0, 0), Column: column(DateTime, 0, 0)}, false},
+		{"datetime to date", Change{Kind: ModifyColumn, OldColumn: column(DateTime, 0, 0), Column: column(Date, 0, 0)}, true},
+		{"wider decimal", Change{Kind: ModifyColumn, OldColumn: column(Decimal, 10, 2), Column: column(Decimal, 12, 2)}, false},
+		{"more decimal places", Change{Kind: ModifyColumn, OldColumn: column(Decimal, 10, 2), Column: column(Decimal, 12, 4)}, false},
+		{"fewer integer digits", Change{Kind: ModifyColumn, OldColumn: column(Decimal, 10, 2), Column: column(Decimal, 10, 4)}, true},
+		{"fewer decimal places", Change{Kind: ModifyColumn, OldColumn: column(Decimal, 10, 4), Column: column(Decimal, 10, 2)}, true},
+		{"int to varchar", Change{Kind: ModifyColumn, OldColumn: column(Int, 0, 0), Column: column(Varchar, 20, 0)}, true},
+		{"not null only", Change{Kind: ModifyColumn, OldColumn: nullable, Column: notNull}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.change.Destructive(); got != tt.want {
+				t.Errorf("Destructive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
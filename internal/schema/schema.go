@@ -0,0 +1,92 @@
+// Package schema describes database tables independently of a database and
+// renders them as DDL for the supported dialects.
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ColumnType is a portable column type, mapped to a native type by each dialect
+type ColumnType int
+
+const (
+	BigInt ColumnType = iota
+	Int
+	// TinyInt holds small codes such as status flags
+	TinyInt
+	// Varchar uses Column.Length
+	Varchar
+	Text
+	// Decimal uses Column.Length as precision and Column.Scale
+	Decimal
+	Boolean
+	Date
+	DateTime
+)
+
+var columnTypeNames = []string{"bigint", "int", "tinyint", "varchar", "text", "decimal", "boolean", "date", "datetime"}
+
+// MarshalText encodes the type by its name, e.g. varchar
+func (t ColumnType) MarshalText() ([]byte, error) {
+	if int(t) < 0 || int(t) >= len(columnTypeNames) {
+		return nil, fmt.Errorf("unknown column type %d", int(t))
+	}
+	return []byte(columnTypeNames[t]), nil
+}
+
+// UnmarshalText decodes a type name written by MarshalText
+func (t *ColumnType) UnmarshalText(text []byte) error {
+	for i, name := range columnTypeNames {
+		if name == string(text) {
+			*t = ColumnType(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown column type %q, expected one of %s", text, strings.Join(columnTypeNames, ", "))
+}
+
+// CurrentTimestamp is the default expression for creation and update times
+const CurrentTimestamp = "CURRENT_TIMESTAMP"
+
+// Column is a table column
+type Column struct {
+	Name    string     `json:"name"`
+	Type    ColumnType `json:"type"`
+	Length  int        `json:"length,omitempty"`
+	Scale   int        `json:"scale,omitempty"`
+	NotNull bool       `json:"notNull,omitempty"`
+	// AutoIncrement makes the database generate the value
+	AutoIncrement bool `json:"autoIncrement,omitempty"`
+	// Default is an SQL expression, e.g. "0", "'draft'" or CurrentTimestamp
+	Default string `json:"default,omitempty"`
+	// OnUpdateCurrentTimestamp sets the column to the current time on every update
+	OnUpdateCurrentTimestamp bool   `json:"onUpdateCurrentTimestamp,omitempty"`
+	Comment                  string `json:"comment,omitempty"`
+	// References is the column this one refers to, e.g. t_user.id, or just
+	// the table to refer to its primary key. It is a logical foreign key:
+	// no constraint is created, references are kept by the application.
+	References string `json:"references,omitempty"`
+}
+
+// sameDefinition reports whether two columns render the same DDL
+func sameDefinition(a, b Column) bool {
+	a.References, b.References = "", ""
+	return a == b
+}
+
+// Index is a secondary index of a table
+type Index struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique,omitempty"`
+}
+
+// Table is a database table
+type Table struct {
+	Name       string   `json:"name"`
+	Comment    string   `json:"comment,omitempty"`
+	Columns    []Column `json:"columns"`
+	PrimaryKey []string `json:"primaryKey,omitempty"`
+	Indexes    []Index  `json:"indexes,omitempty"`
+}
@@ -0,0 +1,310 @@
+// Package seed generates deterministic fake rows for database tables, with
+// values derived from column names and types. The same tables, count and
+// seed always produce the same rows.
+package seed
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/phixia/phjvgen/internal/schema"
+)
+
+// Rows are the generated rows of a table
+type Rows struct {
+	Table   schema.Table
+	Columns []string
+	Values  [][]any
+}
+
+// Reference is a column referring to a column of another table
+type Reference struct {
+	Table  string
+	Column string
+}
+
+// References returns the columns of a table referring to other tables: the
+// columns with References set and, by convention, the columns named
+// <table>_id where a table of that name has a single-column primary key
+func References(tables []schema.Table, t schema.Table) (map[string]Reference, error) {
+	refs := map[string]Reference{}
+	for _, c := range t.Columns {
+		if c.References != "" {
+			table, column, _ := strings.Cut(c.References, ".")
+			target, ok := findTable(tables, table)
+			if !ok {
+				return nil, fmt.Errorf("column %s.%s references unknown table %s", t.Name, c.Name, table)
+			}
+			if column == "" {
+				if len(target.PrimaryKey) != 1 {
+					return nil, fmt.Errorf("column %s.%s references table %s without a single-column primary key", t.Name, c.Name, table)
+				}
+				column = target.PrimaryKey[0]
+			} else if !hasColumn(target, column) {
+				return nil, fmt.Errorf("column %s.%s references unknown column %s.%s", t.Name, c.Name, table, column)
+			}
+			refs[c.Name] = Reference{Table: target.Name, Column: column}
+			continue
+		}
+
+		prefix, ok := strings.CutSuffix(c.Name, "_id")
+		if !ok || isPrimaryKey(t, c.Name) {
+			continue
+		}
+		for _, name := range []string{prefix, "t_" + prefix, prefix + "s"} {
+			if target, ok := findTable(tables, name); ok && len(target.PrimaryKey) == 1 {
+				refs[c.Name] = Reference{Table: target.Name, Column: target.PrimaryKey[0]}
+				break
+			}
+		}
+	}
+	return refs, nil
+}
+
+// Generate returns count rows of the target table and of every table it
+// references, directly or not, ordered so that referenced tables come first
+func Generate(tables []schema.Table, target string, count int, seed int64) ([]Rows, error) {
+	order, err := dependencyOrder(tables, target)
+	if err != nil {
+		return nil, err
+	}
+
+	r := newRand(seed)
+	generated := map[string]*Rows{}
+	var result []Rows
+	for _, t := range order {
+		refs, err := References(tables, t)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := generateRows(r, t, refs, generated, count)
+		if err != nil {
+			return nil, err
+		}
+		generated[t.Name] = rows
+		result = append(result, *rows)
+	}
+	return result, nil
+}
+
+// dependencyOrder returns the target table after the tables it references
+func dependencyOrder(tables []schema.Table, target string) ([]schema.Table, error) {
+	var order []schema.Table
+	state := map[string]int{} // 1: visiting, 2: done
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("tables reference each other: %s", strings.Join(append(path, name), " -> "))
+		case 2:
+			return nil
+		}
+		t, ok := findTable(tables, name)
+		if !ok {
+			return fmt.Errorf("unknown table %s", name)
+		}
+		state[name] = 1
+		refs, err := References(tables, t)
+		if err != nil {
+			return err
+		}
+		for _, c := range t.Columns {
+			// A table referring to itself is filled row by row
+			if ref, ok := refs[c.Name]; ok && ref.Table != name {
+				if err := visit(ref.Table, append(path, name)); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = 2
+		order = append(order, t)
+		return nil
+	}
+	return order, visit(target, nil)
+}
+
+// maxUniqueAttempts bounds the retries for a row violating a unique key
+const maxUniqueAttempts = 20
+
+func generateRows(r *rand, t schema.Table, refs map[string]Reference, generated map[string]*Rows, count int) (*Rows, error) {
+	rows := &Rows{Table: t}
+	for _, c := range t.Columns {
+		rows.Columns = append(rows.Columns, c.Name)
+	}
+
+	keys := uniqueKeys(t)
+	seen := make([]map[string]bool, len(keys))
+	for i := range seen {
+		seen[i] = map[string]bool{}
+	}
+
+	for i := 0; i < count; i++ {
+		var row []any
+		for attempt := 0; ; attempt++ {
+			row = generateRow(r, t, refs, generated, rows, i, attempt)
+			if !violates(t, keys, seen, row) {
+				break
+			}
+			if attempt == maxUniqueAttempts {
+				return nil, fmt.Errorf("cannot generate %d rows of %s with distinct unique keys", count, t.Name)
+			}
+		}
+		for k, key := range keys {
+			seen[k][keyValue(t, key, row)] = true
+		}
+		rows.Values = append(rows.Values, row)
+	}
+	return rows, nil
+}
+
+func generateRow(r *rand, t schema.Table, refs map[string]Reference, generated map[string]*Rows, rows *Rows, i, attempt int) []any {
+	row := make([]any, len(t.Columns))
+	values := map[string]any{}
+	for j, c := range t.Columns {
+		var v any
+		if ref, ok := refs[c.Name]; ok {
+			v = referenceValue(r, c, ref, t, generated, rows, values)
+		} else if isSerialKey(t, c) {
+			v = int64(i + 1)
+		} else {
+			v = fakeValue(r, t, c, values)
+			if attempt > 0 && isUnique(t, c.Name) {
+				v = distinguish(c, v, i, attempt)
+			}
+		}
+		row[j] = v
+		values[c.Name] = v
+	}
+	return row
+}
+
+// referenceValue picks a value of the referenced column among the generated
+// rows. A table referring to itself refers to an earlier row, or to none.
+func referenceValue(r *rand, c schema.Column, ref Reference, t schema.Table, generated map[string]*Rows, rows *Rows, values map[string]any) any {
+	target := generated[ref.Table]
+	if ref.Table == t.Name {
+		target = rows
+	}
+	var candidates []any
+	if target != nil {
+		index := columnIndex(target.Table, ref.Column)
+		for _, row := range target.Values {
+			candidates = append(candidates, row[index])
+		}
+	}
+	if len(candidates) == 0 || (!c.NotNull && ref.Table == t.Name && r.intn(3) == 0) {
+		if !c.NotNull {
+			return nil
+		}
+		// A required reference of the first row points to the row itself
+		return values[ref.Column]
+	}
+	return candidates[r.intn(len(candidates))]
+}
+
+// uniqueKeys returns the column lists that must be unique: the primary key
+// and the unique indexes
+func uniqueKeys(t schema.Table) [][]string {
+	var keys [][]string
+	if len(t.PrimaryKey) > 0 {
+		keys = append(keys, t.PrimaryKey)
+	}
+	for _, idx := range t.Indexes {
+		if idx.Unique {
+			keys = append(keys, idx.Columns)
+		}
+	}
+	return keys
+}
+
+func violates(t schema.Table, keys [][]string, seen []map[string]bool, row []any) bool {
+	for k, key := range keys {
+		if seen[k][keyValue(t, key, row)] {
+			return true
+		}
+	}
+	return false
+}
+
+func keyValue(t schema.Table, key []string, row []any) string {
+	parts := make([]string, len(key))
+	for i, column := range key {
+		parts[i] = schema.Literal(row[columnIndex(t, column)])
+	}
+	return strings.Join(parts, "\x00")
+}
+
+func isUnique(t schema.Table, column string) bool {
+	for _, key := range uniqueKeys(t) {
+		for _, c := range key {
+			if c == column {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isSerialKey reports whether a column is a single-column integer primary
+// key, numbered from 1
+func isSerialKey(t schema.Table, c schema.Column) bool {
+	return len(t.PrimaryKey) == 1 && t.PrimaryKey[0] == c.Name && (c.Type == schema.BigInt || c.Type == schema.Int)
+}
+
+// SerialKey returns the column of a table numbered from 1 by Generate
+func SerialKey(t schema.Table) (schema.Column, bool) {
+	for _, c := range t.Columns {
+		if isSerialKey(t, c) {
+			return c, true
+		}
+	}
+	return schema.Column{}, false
+}
+
+// PrimaryKeys returns the primary key values of the generated rows, or nil
+// when the table has no primary key
+func (r Rows) PrimaryKeys() [][]any {
+	if len(r.Table.PrimaryKey) == 0 {
+		return nil
+	}
+	keys := make([][]any, len(r.Values))
+	for i, row := range r.Values {
+		key := make([]any, len(r.Table.PrimaryKey))
+		for j, column := range r.Table.PrimaryKey {
+			key[j] = row[columnIndex(r.Table, column)]
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+func isPrimaryKey(t schema.Table, column string) bool {
+	for _, c := range t.PrimaryKey {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+func findTable(tables []schema.Table, name string) (schema.Table, bool) {
+	for _, t := range tables {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return schema.Table{}, false
+}
+
+func hasColumn(t schema.Table, name string) bool {
+	return columnIndex(t, name) >= 0
+}
+
+func columnIndex(t schema.Table, name string) int {
+	for i, c := range t.Columns {
+		if c.Name == name {
+			return i
+		}
+	}
+	return -1
+}
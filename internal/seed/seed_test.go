@@ -0,0 +1,266 @@
+package seed
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phixia/phjvgen/internal/schema"
+)
+
+// manifestEntities are the entities of a phjvgen.json manifest
+const manifestEntities = `[
+  {
+    "name": "t_user",
+    "columns": [
+      {"name": "id", "type": "bigint", "notNull": true, "autoIncrement": true},
+      {"name": "username", "type": "varchar", "length": 6, "notNull": true},
+      {"name": "email", "type": "varchar", "length": 100},
+      {"name": "status", "type": "tinyint", "notNull": true, "comment": "状态：0-禁用，1-启用"},
+      {"name": "create_time", "type": "datetime", "notNull": true, "default": "CURRENT_TIMESTAMP"},
+      {"name": "update_time", "type": "datetime", "notNull": true, "default": "CURRENT_TIMESTAMP", "onUpdateCurrentTimestamp": true}
+    ],
+    "primaryKey": ["id"],
+    "indexes": [
+      {"name": "uk_username", "columns": ["username"], "unique": true},
+      {"name": "uk_email", "columns": ["email"], "unique": true}
+    ]
+  },
+  {
+    "name": "t_order",
+    "columns": [
+      {"name": "id", "type": "bigint", "notNull": true, "autoIncrement": true},
+      {"name": "order_no", "type": "varchar", "length": 32, "notNull": true},
+      {"name": "user_id", "type": "bigint", "notNull": true},
+      {"name": "amount", "type": "decimal", "length": 10, "scale": 2, "notNull": true},
+      {"name": "remark", "type": "varchar", "length": 200}
+    ],
+    "primaryKey": ["id"],
+    "indexes": [{"name": "uk_order_no", "columns": ["order_no"], "unique": true}]
+  }
+]`
+
+func loadManifest(t *testing.T, manifest string) []schema.Table {
+	t.Helper()
+	var tables []schema.Table
+	if err := json.Unmarshal([]byte(manifest), &tables); err != nil {
+		t.Fatal(err)
+	}
+	return tables
+}
+
+// render returns the rows as the INSERT statements of a seed script
+func render(rows []Rows) string {
+	d, _ := schema.Lookup(schema.MySQL)
+	var b strings.Builder
+	for _, r := range rows {
+		b.WriteString(d.Insert(r.Table, r.Columns, r.Values))
+	}
+	return b.String()
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	first, err := Generate(loadManifest(t, manifestEntities), "t_order", 50, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Generate(loadManifest(t, manifestEntities), "t_order", 50, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Error("the same manifest and seed generated different rows")
+	}
+	if render(first) != render(second) {
+		t.Error("the same manifest and seed rendered different scripts")
+	}
+
+	other, err := Generate(loadManifest(t, manifestEntities), "t_order", 50, 43)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if render(first) == render(other) {
+		t.Error("different seeds generated the same rows")
+	}
+}
+
+func TestGenerateOrder(t *testing.T) {
+	rows, err := Generate(loadManifest(t, manifestEntities), "t_order", 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].Table.Name != "t_user" || rows[1].Table.Name != "t_order" {
+		t.Fatalf("Generate returned %d tables, want t_user then t_order", len(rows))
+	}
+
+	userIDs := map[any]bool{}
+	for i, row := range rows[0].Values {
+		// Serial keys are numbered from 1
+		if row[0] != int64(i+1) {
+			t.Errorf("t_user row %d id = %v, want %d", i, row[0], i+1)
+		}
+		userIDs[row[0]] = true
+	}
+	userID := columnIndex(rows[1].Table, "user_id")
+	for _, row := range rows[1].Values {
+		if !userIDs[row[userID]] {
+			t.Errorf("t_order.user_id %v refers to no generated user", row[userID])
+		}
+	}
+}
+
+func TestGenerateKeepsUniqueKeysDistinct(t *testing.T) {
+	tables := loadManifest(t, manifestEntities)
+	tables = append(tables, schema.Table{
+		Name: "t_tenant_code",
+		Columns: []schema.Column{
+			{Name: "tenant_id", Type: schema.Varchar, Length: 64, NotNull: true},
+			{Name: "code", Type: schema.Varchar, Length: 12, NotNull: true},
+			{Name: "label", Type: schema.Varchar, Length: 4},
+		},
+		PrimaryKey: []string{"tenant_id", "code"},
+		Indexes:    []schema.Index{{Name: "uk_label", Columns: []string{"label"}, Unique: true}},
+	})
+
+	// 300 usernames of at most six characters collide without distinguishing
+	for _, target := range []string{"t_order", "t_tenant_code"} {
+		rows, err := Generate(tables, target, 300, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range rows {
+			for _, key := range uniqueKeys(r.Table) {
+				seen := map[string]bool{}
+				for _, row := range r.Values {
+					v := keyValue(r.Table, key, row)
+					if seen[v] {
+						t.Errorf("%s: duplicate %s %q", r.Table.Name, strings.Join(key, ", "), v)
+					}
+					seen[v] = true
+				}
+			}
+			for _, row := range r.Values {
+				for i, c := range r.Table.Columns {
+					if s, ok := row[i].(string); ok && c.Length > 0 && len([]rune(s)) > c.Length {
+						t.Errorf("%s.%s value %q exceeds length %d", r.Table.Name, c.Name, s, c.Length)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateFailsWhenUniqueValuesRunOut(t *testing.T) {
+	tables := []schema.Table{{
+		Name: "t_flag",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.BigInt, NotNull: true},
+			{Name: "enabled", Type: schema.Boolean, NotNull: true},
+		},
+		PrimaryKey: []string{"id"},
+		Indexes:    []schema.Index{{Name: "uk_enabled", Columns: []string{"enabled"}, Unique: true}},
+	}}
+	if _, err := Generate(tables, "t_flag", 3, 1); err == nil {
+		t.Error("generated three distinct booleans")
+	}
+}
+
+var uuidV7Re = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// TestGenerateUUIDv7Keys covers the tables of --id-strategy uuidv7, whose
+// primary keys are varchar(36) assigned by the application
+func TestGenerateUUIDv7Keys(t *testing.T) {
+	tables := loadManifest(t, manifestEntities)
+	for i := range tables {
+		tables[i].Columns[0] = schema.Column{Name: "id", Type: schema.Varchar, Length: 36, NotNull: true}
+	}
+	userID := columnIndex(tables[1], "user_id")
+	tables[1].Columns[userID] = schema.Column{Name: "user_id", Type: schema.Varchar, Length: 36, NotNull: true}
+
+	if _, ok := SerialKey(tables[0]); ok {
+		t.Error("a varchar primary key is reported as a serial key")
+	}
+
+	rows, err := Generate(tables, "t_order", 100, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[any]bool{}
+	for _, r := range rows {
+		for _, row := range r.Values {
+			id, ok := row[0].(string)
+			if !ok || !uuidV7Re.MatchString(id) {
+				t.Fatalf("%s.id = %v, want a UUIDv7", r.Table.Name, row[0])
+			}
+			millis, _ := strconv.ParseInt(strings.ReplaceAll(id[:13], "-", ""), 16, 64)
+			if at := time.UnixMilli(millis).UTC(); at.Before(baseTime) || !at.Before(baseTime.AddDate(1, 0, 0)) {
+				t.Errorf("%s.id %s has time %v, want within a year after %v", r.Table.Name, id, at, baseTime)
+			}
+			if ids[id] {
+				t.Errorf("duplicate id %s", id)
+			}
+			ids[id] = true
+		}
+	}
+	for _, row := range rows[1].Values {
+		if !ids[row[userID]] {
+			t.Errorf("t_order.user_id %v refers to no generated user", row[userID])
+		}
+	}
+}
+
+// TestPrimaryKeysOfUUIDv7Table covers the keys a seed script deletes before
+// inserting: with --id-strategy uuidv7 they are not numbered from 1, so the
+// script deletes them by value
+func TestPrimaryKeysOfUUIDv7Table(t *testing.T) {
+	tables := loadManifest(t, manifestEntities)[:1]
+	tables[0].Columns[0] = schema.Column{Name: "id", Type: schema.Varchar, Length: 36, NotNull: true}
+
+	three, err := Generate(tables, "t_user", 3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := three[0].PrimaryKeys()
+	if len(keys) != 3 {
+		t.Fatalf("PrimaryKeys returned %d keys, want 3", len(keys))
+	}
+	for i, key := range keys {
+		if len(key) != 1 || key[0] != three[0].Values[i][0] {
+			t.Errorf("key %d = %v, want [%v]", i, key, three[0].Values[i][0])
+		}
+	}
+
+	// A larger count with the same seed starts with the same keys, which
+	// the re-run script must delete before inserting them again
+	four, err := Generate(tables, "t_user", 4, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := four[0].PrimaryKeys(); !reflect.DeepEqual(got[:3], keys) {
+		t.Errorf("keys of --count 4 = %v, want to start with %v", got, keys)
+	}
+}
+
+func TestPrimaryKeys(t *testing.T) {
+	composite := Rows{
+		Table: schema.Table{
+			Name:       "t_user_role",
+			Columns:    []schema.Column{{Name: "note"}, {Name: "user_id"}, {Name: "role_id"}},
+			PrimaryKey: []string{"user_id", "role_id"},
+		},
+		Values: [][]any{{"a", int64(1), int64(2)}, {"b", int64(1), int64(3)}},
+	}
+	want := [][]any{{int64(1), int64(2)}, {int64(1), int64(3)}}
+	if got := composite.PrimaryKeys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("PrimaryKeys = %v, want %v", got, want)
+	}
+
+	composite.Table.PrimaryKey = nil
+	if got := composite.PrimaryKeys(); got != nil {
+		t.Errorf("PrimaryKeys without a primary key = %v, want nil", got)
+	}
+}
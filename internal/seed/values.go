@@ -0,0 +1,371 @@
+package seed
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phixia/phjvgen/internal/schema"
+)
+
+// rand is a splitmix64 generator. Its sequence is fixed for a seed, unlike
+// math/rand's, which may change between Go releases.
+type rand struct {
+	state uint64
+}
+
+func newRand(seed int64) *rand {
+	return &rand{state: uint64(seed)}
+}
+
+func (r *rand) next() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// intn returns a number in [0, n)
+func (r *rand) intn(n int) int {
+	return int(r.next() % uint64(n))
+}
+
+// between returns a number in [lo, hi]
+func (r *rand) between(lo, hi int64) int64 {
+	return lo + int64(r.next()%uint64(hi-lo+1))
+}
+
+func (r *rand) pick(values []string) string {
+	return values[r.intn(len(values))]
+}
+
+func (r *rand) digits(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('0' + r.intn(10))
+	}
+	return string(b)
+}
+
+// person is a Chinese name with its pinyin
+type person struct {
+	name, pinyin string
+}
+
+var (
+	surnames = []person{
+		{"王", "wang"}, {"李", "li"}, {"张", "zhang"}, {"刘", "liu"}, {"陈", "chen"},
+		{"杨", "yang"}, {"黄", "huang"}, {"赵", "zhao"}, {"吴", "wu"}, {"周", "zhou"},
+		{"徐", "xu"}, {"孙", "sun"}, {"马", "ma"}, {"朱", "zhu"}, {"胡", "hu"},
+		{"郭", "guo"}, {"何", "he"}, {"林", "lin"}, {"高", "gao"}, {"罗", "luo"},
+	}
+	givenNames = []person{
+		{"伟", "wei"}, {"芳", "fang"}, {"娜", "na"}, {"敏", "min"}, {"静", "jing"},
+		{"强", "qiang"}, {"磊", "lei"}, {"军", "jun"}, {"洋", "yang"}, {"勇", "yong"},
+		{"艳", "yan"}, {"杰", "jie"}, {"涛", "tao"}, {"明", "ming"}, {"超", "chao"},
+		{"秀英", "xiuying"}, {"丽", "li"}, {"华", "hua"}, {"欣怡", "xinyi"}, {"子轩", "zixuan"},
+		{"浩然", "haoran"}, {"雨桐", "yutong"}, {"思远", "siyuan"}, {"嘉怡", "jiayi"},
+	}
+	emailDomains  = []string{"example.com", "example.org", "example.net"}
+	phonePrefixes = []string{"130", "135", "138", "150", "158", "177", "186", "189", "199"}
+	cities        = []string{"北京市", "上海市", "广州市", "深圳市", "杭州市", "成都市", "南京市", "武汉市", "西安市", "苏州市"}
+	districts     = []string{"朝阳区", "海淀区", "浦东新区", "天河区", "南山区", "西湖区", "武侯区", "鼓楼区", "江汉区", "雁塔区"}
+	roads         = []string{"人民路", "解放路", "中山路", "建设路", "和平路", "长江路", "学府路", "科技路"}
+	adjectives    = []string{"经典", "精选", "新款", "高级", "简约", "智能", "便携", "定制"}
+	nouns         = []string{"方案", "服务", "套餐", "产品", "课程", "项目", "活动", "计划"}
+	sentences     = []string{
+		"这是一条用于开发和测试的示例数据。",
+		"内容由 phjvgen seed 根据字段名称自动生成。",
+		"请勿在生产环境中使用这些数据。",
+		"数据仅用于演示列表、分页和搜索等功能。",
+		"如需调整数据量，请修改 --count 参数后重新生成。",
+	}
+)
+
+// baseTime is the earliest generated time; times spread over the year after it
+var baseTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const (
+	timeLayout = "2006-01-02 15:04:05"
+	dateLayout = "2006-01-02"
+)
+
+// enumCodeRe matches a code of an enumeration in a column comment, e.g. the
+// 1 of "状态：0-禁用，1-启用"
+var enumCodeRe = regexp.MustCompile(`^([A-Za-z0-9_]+)\s*[-=:：]\s*\S`)
+
+// enumValues returns the codes listed in a column comment, if any
+func enumValues(comment string) []string {
+	_, list, ok := strings.Cut(strings.Replace(comment, "：", ":", 1), ":")
+	if !ok {
+		return nil
+	}
+	var codes []string
+	for _, part := range strings.FieldsFunc(list, func(r rune) bool {
+		return strings.ContainsRune("，,；;、 ", r)
+	}) {
+		if m := enumCodeRe.FindStringSubmatch(part); m != nil {
+			codes = append(codes, m[1])
+		}
+	}
+	if len(codes) < 2 {
+		return nil
+	}
+	return codes
+}
+
+// fakeValue returns a value for column c, based on its name and
+// type. values holds the columns of the row generated so far.
+func fakeValue(r *rand, t schema.Table, c schema.Column, values map[string]any) any {
+	name := strings.ToLower(c.Name)
+	if name == "deleted" || name == "is_deleted" || name == "del_flag" {
+		if c.Type == schema.Boolean {
+			return false
+		}
+		return int64(0)
+	}
+	if codes := enumValues(c.Comment); codes != nil {
+		code := r.pick(codes)
+		switch c.Type {
+		case schema.Varchar, schema.Text:
+			return truncate(code, c.Length)
+		case schema.BigInt, schema.Int, schema.TinyInt:
+			if n, err := strconv.ParseInt(code, 10, 64); err == nil {
+				return n
+			}
+		}
+	}
+
+	switch c.Type {
+	case schema.Varchar, schema.Text:
+		return truncate(stringValue(r, t, c, name, values), c.Length)
+	case schema.BigInt, schema.Int, schema.TinyInt:
+		return intValue(r, c, name)
+	case schema.Decimal:
+		return decimalValue(r, c, name)
+	case schema.Boolean:
+		return r.intn(2) == 1
+	case schema.Date:
+		if has(name, "birth") {
+			return baseTime.AddDate(-int(r.between(18, 60)), 0, -r.intn(365)).Format(dateLayout)
+		}
+		return baseTime.AddDate(0, 0, r.intn(365)).Format(dateLayout)
+	case schema.DateTime:
+		return timeValue(r, t, c, name, values)
+	}
+	return nil
+}
+
+func stringValue(r *rand, t schema.Table, c schema.Column, name string, values map[string]any) string {
+	switch {
+	case len(t.PrimaryKey) == 1 && t.PrimaryKey[0] == c.Name && c.Length >= 36:
+		return uuidV7(r)
+	case name == "tenant_id":
+		// A few tenants, so that each one gets a share of the rows
+		return fmt.Sprintf("tenant_%d", r.between(1, 3))
+	case has(name, "email", "mail"):
+		local, ok := values["username"].(string)
+		if !ok {
+			local = username(r)
+		}
+		return local + "@" + r.pick(emailDomains)
+	case has(name, "phone", "mobile") || hasWord(name, "tel"):
+		return r.pick(phonePrefixes) + r.digits(8)
+	case has(name, "username", "login", "account"):
+		return username(r)
+	case has(name, "password", "passwd"):
+		// A BCrypt hash of a random password, no one can log in with it
+		const alphabet = "./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+		b := make([]byte, 53)
+		for i := range b {
+			b[i] = alphabet[r.intn(len(alphabet))]
+		}
+		return "$2a$10$" + string(b)
+	case has(name, "avatar", "image", "logo", "picture", "photo", "cover"):
+		return fmt.Sprintf("https://example.com/images/%016x.png", r.next())
+	case has(name, "url", "link", "website", "homepage"):
+		return fmt.Sprintf("https://example.com/%s/%d", strings.TrimPrefix(t.Name, "t_"), r.between(1, 99999))
+	case has(name, "address", "addr"):
+		return fmt.Sprintf("%s%s%s%d号", r.pick(cities), r.pick(districts), r.pick(roads), r.between(1, 999))
+	case has(name, "city"):
+		return r.pick(cities)
+	case hasWord(name, "ip"):
+		return fmt.Sprintf("192.168.%d.%d", r.intn(256), r.between(1, 254))
+	case has(name, "nickname", "real_name", "full_name", "contact", "author", "owner") ||
+		name == "name" && has(t.Name, "user", "member", "customer", "employee", "person", "staff"):
+		return personName(r)
+	case has(name, "code", "serial") || hasWord(name, "no", "sn"):
+		return codePrefix(t) + r.digits(10)
+	case has(name, "name", "title", "subject", "label"):
+		return r.pick(adjectives) + r.pick(nouns) + strconv.Itoa(r.intn(1000))
+	case has(name, "desc", "remark", "content", "summary", "note", "comment", "intro", "memo") || c.Type == schema.Text:
+		n := 1 + r.intn(3)
+		var b strings.Builder
+		for range n {
+			b.WriteString(r.pick(sentences))
+		}
+		return b.String()
+	case has(name, "gender", "sex"):
+		return r.pick([]string{"男", "女"})
+	}
+	return fmt.Sprintf("%s_%d", name, r.between(1, 99999))
+}
+
+func intValue(r *rand, c schema.Column, name string) int64 {
+	switch {
+	case hasWord(name, "age"):
+		return r.between(18, 60)
+	case has(name, "version"):
+		return 0
+	case has(name, "year"):
+		return r.between(2000, int64(baseTime.Year()))
+	case has(name, "sort", "priority") || hasWord(name, "order", "seq", "rank"):
+		return r.between(1, 100)
+	case has(name, "score", "rating", "star") && c.Type == schema.TinyInt:
+		return r.between(1, 5)
+	case has(name, "quantity", "stock") || hasWord(name, "qty", "count", "num", "total"):
+		return r.between(0, 1000)
+	}
+	switch c.Type {
+	case schema.TinyInt:
+		return r.between(0, 1)
+	case schema.Int:
+		return r.between(1, 10000)
+	}
+	return r.between(1, 1000000)
+}
+
+// decimalValue returns a number fitting the precision and scale of c:
+// prices and amounts up to 9999, others up to 100
+func decimalValue(r *rand, c schema.Column, name string) schema.Number {
+	limit := int64(100)
+	if has(name, "price", "amount", "fee", "cost", "balance", "salary", "total") {
+		limit = 9999
+	}
+	if digits := c.Length - c.Scale; c.Length > 0 && digits < 4 {
+		if max := pow10(digits) - 1; max < limit {
+			limit = max
+		}
+	}
+	unit := pow10(c.Scale)
+	v := r.between(unit, limit*unit)
+	if c.Scale == 0 {
+		return schema.Number(strconv.FormatInt(v, 10))
+	}
+	return schema.Number(fmt.Sprintf("%d.%0*d", v/unit, c.Scale, v%unit))
+}
+
+// timeValue returns a time within the year after baseTime. An update time
+// follows the creation time of the row.
+func timeValue(r *rand, t schema.Table, c schema.Column, name string, values map[string]any) string {
+	if has(name, "update", "modif") {
+		for _, other := range t.Columns {
+			created, ok := values[other.Name].(string)
+			if !ok || !has(strings.ToLower(other.Name), "create") {
+				continue
+			}
+			if at, err := time.Parse(timeLayout, created); err == nil {
+				return at.Add(time.Duration(r.intn(30*24*3600)) * time.Second).Format(timeLayout)
+			}
+		}
+	}
+	return baseTime.Add(time.Duration(r.intn(365*24*3600)) * time.Second).Format(timeLayout)
+}
+
+// distinguish makes a string value of row i differ from the other rows'
+func distinguish(c schema.Column, v any, i, attempt int) any {
+	s, ok := v.(string)
+	if !ok || attempt < 3 {
+		// Other values are just generated again
+		return v
+	}
+	suffix := fmt.Sprintf("_%d", i+1)
+	if c.Length > 0 && c.Length <= len(suffix) {
+		// Too narrow to keep any of the value
+		return truncate(strconv.Itoa(i+1), c.Length)
+	}
+	local, domain, isEmail := strings.Cut(s, "@")
+	if isEmail && (c.Length == 0 || c.Length > len(suffix)+len(domain)+1) {
+		local = truncate(local, c.Length-len(suffix)-len(domain)-1)
+		return local + suffix + "@" + domain
+	}
+	return truncate(s, c.Length-len(suffix)) + suffix
+}
+
+// uuidV7 returns a UUIDv7 of a time in the year after baseTime, as
+// assigned by the generated UuidV7IdGenerator
+func uuidV7(r *rand) string {
+	millis := uint64(baseTime.UnixMilli()) + uint64(r.intn(365*24*3600*1000))
+	hi := millis<<16 | 0x7000 | r.next()&0x0fff
+	lo := r.next()&0x3fffffffffffffff | 0x8000000000000000
+	return fmt.Sprintf("%08x-%04x-%04x-%04x-%012x", hi>>32, hi>>16&0xffff, hi&0xffff, lo>>48, lo&0xffffffffffff)
+}
+
+func username(r *rand) string {
+	return surnames[r.intn(len(surnames))].pinyin + givenNames[r.intn(len(givenNames))].pinyin + r.digits(1+r.intn(3))
+}
+
+func personName(r *rand) string {
+	return surnames[r.intn(len(surnames))].name + givenNames[r.intn(len(givenNames))].name
+}
+
+// codePrefix returns the initials of a table, or its first letters for a
+// single word, e.g. ORD for t_order
+func codePrefix(t schema.Table) string {
+	words := strings.Split(strings.TrimPrefix(t.Name, "t_"), "_")
+	if len(words) == 1 {
+		return strings.ToUpper(truncate(words[0], 3))
+	}
+	var b strings.Builder
+	for _, w := range words {
+		if w != "" {
+			b.WriteByte(w[0])
+		}
+	}
+	return strings.ToUpper(b.String())
+}
+
+// truncate cuts a string to at most n characters, if n is positive
+func truncate(s string, n int) string {
+	if n <= 0 {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+func has(name string, parts ...string) bool {
+	for _, p := range parts {
+		if strings.Contains(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasWord reports whether one of the underscore-separated words of a name
+// is among words
+func hasWord(name string, words ...string) bool {
+	for _, w := range strings.Split(name, "_") {
+		for _, v := range words {
+			if w == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func pow10(n int) int64 {
+	v := int64(1)
+	for range n {
+		v *= 10
+	}
+	return v
+}
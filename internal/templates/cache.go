@@ -0,0 +1,289 @@
+package templates
+
+// TwoLevelCache is the template of the two-level cache shared by the caching
+// repositories
+const TwoLevelCache = `package {{PACKAGE_NAME}}.infrastructure.cache;
+
+{{TENANT_CACHE_IMPORT}}import com.github.benmanes.caffeine.cache.Cache;
+import com.github.benmanes.caffeine.cache.Caffeine;
+import io.micrometer.core.instrument.Counter;
+import io.micrometer.core.instrument.MeterRegistry;
+import io.micrometer.core.instrument.binder.cache.CaffeineCacheMetrics;
+import io.netty.buffer.ByteBuf;
+import lombok.extern.slf4j.Slf4j;
+import org.redisson.api.RBucket;
+import org.redisson.api.RTopic;
+import org.redisson.api.RedissonClient;
+import org.redisson.client.codec.Codec;
+import org.redisson.client.codec.StringCodec;
+import org.redisson.codec.Kryo5Codec;
+import org.springframework.transaction.support.TransactionSynchronization;
+import org.springframework.transaction.support.TransactionSynchronizationManager;
+
+import java.io.IOException;
+import java.time.Duration;
+import java.util.Collection;
+import java.util.List;
+import java.util.Optional;
+import java.util.concurrent.ThreadLocalRandom;
+import java.util.concurrent.TimeUnit;
+import java.util.function.Supplier;
+
+/**
+ * 两级缓存：本地 Caffeine 缓存在前，Redis 在后
+ *
+ * 键的格式为 <应用名>:<缓存名>:{{TENANT_CACHE_KEY_DOC}}<字段>:<值>，例如 demo-app:user:{{TENANT_CACHE_KEY_EXAMPLE}}id:1。
+ * - 读取：本地缓存 -> Redis -> 数据库，逐级回填；返回给调用方的是副本，修改它不会影响缓存。
+ *   读写事务中从数据库加载的值可能尚未提交，也可能被回滚，不回填缓存
+ * - 失效：删除两级缓存中的键，并通过 Redis 主题 <应用名>:<缓存名>:evict 通知其他实例
+ *   清除本地缓存；在事务中失效时，事务结束（提交或回滚）后再清除一次，避免并发读取把
+ *   提交前的旧值写回缓存
+ * - 本地缓存最多保留 LOCAL_TTL，限制错过失效通知时的不一致时间；Redis 中的键在有效期上
+ *   加最多 10% 的随机时间，避免同时过期
+ * - Redis 不可用时直接读写数据库，只记录告警
+ *
+ * 指标（Micrometer）：
+ * - cache.gets{cache=<缓存名>.local|<缓存名>.redis, result=hit|miss}，以及 Caffeine 的
+ *   cache.puts、cache.evictions、cache.size 等
+ * - cache.errors{cache=<缓存名>.redis}：Redis 读写失败的次数
+ */
+@Slf4j
+public class TwoLevelCache<V> {
+
+    private static final Duration LOCAL_TTL = Duration.ofMinutes(1);
+    private static final String CLEAR_ALL = "*";
+
+    private final String prefix;
+    private final Duration ttl;
+    private final Cache<String, V> local;
+    private final RedissonClient redisson;
+    private final Codec codec;
+    private final RTopic evictions;
+    private final Counter redisHits;
+    private final Counter redisMisses;
+    private final Counter redisErrors;
+
+    public TwoLevelCache(String application, String name, Duration ttl, long localMaximumSize,
+                         RedissonClient redisson, MeterRegistry meterRegistry) {
+        this.prefix = application + ":" + name + ":";
+        this.ttl = ttl;
+        this.redisson = redisson;
+        this.codec = new Kryo5Codec(TwoLevelCache.class.getClassLoader());
+        this.local = Caffeine.newBuilder()
+            .maximumSize(localMaximumSize)
+            .expireAfterWrite(ttl.compareTo(LOCAL_TTL) < 0 ? ttl : LOCAL_TTL)
+            .recordStats()
+            .build();
+        CaffeineCacheMetrics.monitor(meterRegistry, local, name + ".local");
+        this.redisHits = counter(meterRegistry, "cache.gets", name, "hit");
+        this.redisMisses = counter(meterRegistry, "cache.gets", name, "miss");
+        this.redisErrors = Counter.builder("cache.errors").tag("cache", name + ".redis").register(meterRegistry);
+
+        this.evictions = redisson.getTopic(prefix + "evict", StringCodec.INSTANCE);
+        try {
+            evictions.addListener(String.class, (channel, key) -> {
+                if (CLEAR_ALL.equals(key)) {
+                    local.invalidateAll();
+                } else {
+                    local.invalidate(key);
+                }
+            });
+        } catch (RuntimeException e) {
+            log.warn("Failed to subscribe to {}evict, local entries of {} expire after {}", prefix, name, LOCAL_TTL, e);
+        }
+    }
+
+    private static Counter counter(MeterRegistry registry, String meter, String name, String result) {
+        return Counter.builder(meter).tag("cache", name + ".redis").tag("result", result).register(registry);
+    }
+
+    /**
+     * 缓存键：<应用名>:<缓存名>:{{TENANT_CACHE_KEY_DOC}}<字段>:<值>
+     */
+    public String key(String field, Object value) {
+        return prefix + {{TENANT_CACHE_KEY}}field + ":" + value;
+    }
+
+    /**
+     * 按字段的值读取，两级缓存都未命中时由 loader 从数据库加载；不缓存不存在的结果，
+     * 也不缓存读写事务中加载的结果
+     */
+    public Optional<V> get(String field, Object value, Supplier<Optional<V>> loader) {
+        String key = key(field, value);
+        V cached = local.getIfPresent(key);
+        if (cached != null) {
+            return Optional.of(copy(cached));
+        }
+
+        V stored = readRedis(key);
+        if (stored != null) {
+            local.put(key, copy(stored));
+            return Optional.of(stored);
+        }
+
+        Optional<V> loaded = loader.get();
+        if (inWriteTransaction()) {
+            return loaded;
+        }
+        loaded.ifPresent(v -> {
+            local.put(key, copy(v));
+            writeRedis(key, v);
+        });
+        return loaded;
+    }
+
+    /**
+     * 清除缓存键
+     */
+    public void evict(Collection<String> keys) {
+        if (keys.isEmpty()) {
+            return;
+        }
+        List<String> evicted = List.copyOf(keys);
+        evictNow(evicted);
+        afterCompletion(() -> evictNow(evicted));
+    }
+
+    /**
+     * 清除整个缓存，用于无法确定受影响键的写操作
+     */
+    public void clear() {
+        clearNow();
+        afterCompletion(this::clearNow);
+    }
+
+    private void evictNow(List<String> keys) {
+        keys.forEach(local::invalidate);
+        try {
+            redisson.getKeys().delete(keys.toArray(String[]::new));
+            keys.forEach(evictions::publish);
+        } catch (RuntimeException e) {
+            redisErrors.increment();
+            log.warn("Failed to evict {} from Redis", keys, e);
+        }
+    }
+
+    private void clearNow() {
+        local.invalidateAll();
+        try {
+            redisson.getKeys().deleteByPattern(prefix + "*");
+            evictions.publish(CLEAR_ALL);
+        } catch (RuntimeException e) {
+            redisErrors.increment();
+            log.warn("Failed to clear {}* from Redis", prefix, e);
+        }
+    }
+
+    /**
+     * 在当前事务结束后执行，回滚时同样执行，清除事务期间可能写入缓存的未提交数据
+     */
+    private void afterCompletion(Runnable action) {
+        if (TransactionSynchronizationManager.isSynchronizationActive()) {
+            TransactionSynchronizationManager.registerSynchronization(new TransactionSynchronization() {
+                @Override
+                public void afterCompletion(int status) {
+                    action.run();
+                }
+            });
+        }
+    }
+
+    private static boolean inWriteTransaction() {
+        return TransactionSynchronizationManager.isActualTransactionActive()
+            && !TransactionSynchronizationManager.isCurrentTransactionReadOnly();
+    }
+
+    private V readRedis(String key) {
+        try {
+            RBucket<V> bucket = redisson.getBucket(key, codec);
+            V value = bucket.get();
+            (value != null ? redisHits : redisMisses).increment();
+            return value;
+        } catch (RuntimeException e) {
+            redisErrors.increment();
+            log.warn("Failed to read {} from Redis", key, e);
+            return null;
+        }
+    }
+
+    private void writeRedis(String key, V value) {
+        long millis = ttl.toMillis();
+        long jitter = ThreadLocalRandom.current().nextLong(millis / 10 + 1);
+        try {
+            RBucket<V> bucket = redisson.getBucket(key, codec);
+            bucket.set(value, millis + jitter, TimeUnit.MILLISECONDS);
+        } catch (RuntimeException e) {
+            redisErrors.increment();
+            log.warn("Failed to write {} to Redis", key, e);
+        }
+    }
+
+    /**
+     * 通过序列化复制，本地缓存中的对象不会被调用方修改
+     */
+    @SuppressWarnings("unchecked")
+    private V copy(V value) {
+        try {
+            ByteBuf buf = codec.getValueEncoder().encode(value);
+            try {
+                return (V) codec.getValueDecoder().decode(buf, null);
+            } finally {
+                buf.release();
+            }
+        } catch (IOException e) {
+            throw new IllegalStateException("Failed to copy cached value", e);
+        }
+    }
+}
+`
+
+// CachingRepository is the template of the caching decorator of an entity's
+// repository. The methods are rendered from the repository interface.
+const CachingRepository = `package {{PACKAGE_NAME}}.infrastructure.cache;
+
+{{CACHE_IMPORTS}}
+/**
+ * 带缓存的{{ENTITY_DESCRIPTION}}仓储
+ *
+ * 包装 {{ENTITY_CLASS}}RepositoryImpl 并标注 @Primary，注入 {{ENTITY_CLASS}}Repository 的地方都会经过缓存：
+ * - {{CACHE_LOOKUPS}} 的结果写入两级缓存（见 TwoLevelCache），键为 {{CACHE_KEYS}}
+ * - {{CACHE_EVICTIONS}} 清除该{{ENTITY_DESCRIPTION}}修改前后的全部缓存键
+ * - 其余查询直接委托；其余写操作委托后清除整个缓存
+ *
+ * 缓存有效期由 app.cache.{{CACHE_NAME}}.ttl 配置。由 phjvgen cache 生成，
+ * 仓储接口新增的方法需要在这里同样实现
+ */
+@Primary
+@Repository
+public class Caching{{ENTITY_CLASS}}Repository implements {{ENTITY_CLASS}}Repository {
+
+    private final {{ENTITY_CLASS}}Repository delegate;
+    private final TwoLevelCache<{{ENTITY_CLASS}}> cache;
+
+    public Caching{{ENTITY_CLASS}}Repository(@Qualifier("{{DELEGATE_BEAN}}") {{ENTITY_CLASS}}Repository delegate,
+            RedissonClient redisson, MeterRegistry meterRegistry,
+            @Value("${spring.application.name}") String application,
+            @Value("${app.cache.{{CACHE_NAME}}.ttl}") Duration ttl) {
+        this.delegate = delegate;
+        this.cache = new TwoLevelCache<>(application, "{{CACHE_NAME}}", ttl, 10_000, redisson, meterRegistry);
+    }
+{{CACHE_METHODS}}
+    /**
+     * {{ENTITY_DESCRIPTION}}的全部缓存键
+     */
+    private List<String> keysOf({{ENTITY_CLASS}} {{ENTITY_VAR}}) {
+        List<String> keys = new ArrayList<>();
+{{CACHE_KEYS_OF}}        return keys;
+    }
+
+    /**
+     * 数据库中该{{ENTITY_DESCRIPTION}}当前的缓存键，写入前读取，以便清除修改前的键
+     */
+    private List<String> storedKeys({{ID_TYPE}} id) {
+        if (id == null) {
+            return new ArrayList<>();
+        }
+        return delegate.findById(id).map(this::keysOf).orElseGet(ArrayList::new);
+    }
+}
+`
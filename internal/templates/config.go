@@ -5,8 +5,13 @@ const ApplicationYML = `spring:
   application:
     name: {{ARTIFACT_ID}}
   profiles:
-    active: dev
-
+    # 由构建时选择的 profile 填充（Maven: -P<profile>，Gradle: -Pprofile=<profile>），
+    # 运行时可通过 SPRING_PROFILES_ACTIVE 覆盖
+    active: '@spring.profiles.active@'
+    group:
+      # 本地开发时额外加载 application-local.yml（不提交到版本库），参考 application-local.yml.example
+      {{DEFAULT_PROFILE}}: local
+{{PERSISTENCE_SPRING_YML}}{{MIGRATION_SPRING_YML}}
 server:
   port: 8080
 
@@ -19,21 +24,15 @@ management:
     export:
       prometheus:
         enabled: true
-
-mybatis-plus:
-  configuration:
-    map-underscore-to-camel-case: true
-    log-impl: org.apache.ibatis.logging.stdout.StdOutImpl
-  mapper-locations: classpath*:/mapper/**/*Mapper.xml
-`
+{{PERSISTENCE_YML}}{{TENANT_YML}}{{ID_YML}}`
 
 // ApplicationDevYML is the dev profile application.yml template
 const ApplicationDevYML = `spring:
   datasource:
-    url: jdbc:mysql://localhost:3306/{{ARTIFACT_ID}}?useSSL=false&serverTimezone=Asia/Shanghai&characterEncoding=utf8
-    username: root
-    password: root
-    driver-class-name: com.mysql.cj.jdbc.Driver
+    url: ${DB_URL:{{DB_LOCAL_URL}}}
+    username: ${DB_USERNAME:{{DB_USERNAME}}}
+    password: ${DB_PASSWORD:}
+    driver-class-name: {{DB_DRIVER_CLASS}}
     hikari:
       maximum-pool-size: 20
       minimum-idle: 5
@@ -47,6 +46,86 @@ logging:
     console: "%d{yyyy-MM-dd HH:mm:ss} [%thread] %-5level %logger{36} - %msg%n"
 `
 
+// ApplicationTestYML is the test profile application.yml template
+const ApplicationTestYML = `spring:
+  datasource:
+    url: {{DB_ENV_URL}}
+    username: ${DB_USERNAME}
+    password: ${DB_PASSWORD}
+    driver-class-name: {{DB_DRIVER_CLASS}}
+    hikari:
+      maximum-pool-size: 20
+      minimum-idle: 5
+      connection-timeout: 30000
+{{PERSISTENCE_PROFILE_YML}}
+logging:
+  level:
+    root: INFO
+    {{PACKAGE_NAME}}: DEBUG
+`
+
+// ApplicationProdYML is the prod profile application.yml template. Every
+// connection setting comes from the environment.
+const ApplicationProdYML = `spring:
+  datasource:
+    url: ${DB_URL}
+    username: ${DB_USERNAME}
+    password: ${DB_PASSWORD}
+    driver-class-name: {{DB_DRIVER_CLASS}}
+    hikari:
+      maximum-pool-size: ${DB_POOL_SIZE:50}
+      minimum-idle: 10
+      connection-timeout: 30000
+{{PERSISTENCE_PROFILE_YML}}
+management:
+  endpoints:
+    web:
+      exposure:
+        include: health,info,prometheus
+
+logging:
+  level:
+    root: WARN
+    {{PACKAGE_NAME}}: INFO
+`
+
+// ApplicationProfileYML is the template for profiles other than dev, test and prod
+const ApplicationProfileYML = `# {{PROFILE}} 环境配置
+spring:
+  datasource:
+    url: ${DB_URL}
+    username: ${DB_USERNAME}
+    password: ${DB_PASSWORD}
+    driver-class-name: {{DB_DRIVER_CLASS}}
+{{PERSISTENCE_PROFILE_YML}}
+logging:
+  level:
+    root: INFO
+    {{PACKAGE_NAME}}: INFO
+`
+
+// ApplicationLocalYMLExample is the application-local.yml.example template
+const ApplicationLocalYMLExample = `# 本地覆盖配置示例
+# 复制为 application-local.yml 后按需修改，该文件已被 .gitignore 忽略。
+# 激活 {{DEFAULT_PROFILE}} profile 时一并加载，其中的配置会覆盖 application-{{DEFAULT_PROFILE}}.yml 的同名配置
+spring:
+  datasource:
+    url: {{DB_LOCAL_URL}}
+    username: {{DB_USERNAME}}
+    password: change-me
+
+logging:
+  level:
+    {{PACKAGE_NAME}}: DEBUG
+`
+
+// LiquibaseChangelog is the Liquibase master changelog template. phjvgen
+// migration new appends an include for every new script.
+const LiquibaseChangelog = `databaseChangeLog:
+  - include:
+      file: db/migration/V1__create_user_table.sql
+`
+
 // README is the README.md template
 const README = `# {{PROJECT_NAME}}
 
@@ -57,13 +136,13 @@ const README = `# {{PROJECT_NAME}}
 ### 构建项目
 
 ` + "```" + `bash
-mvn clean install
+{{BUILD_COMMAND}}
 ` + "```" + `
 
 ### 运行应用
 
 ` + "```" + `bash
-java --enable-preview -jar starter/target/starter-{{VERSION}}.jar
+java --enable-preview -jar {{STARTER_JAR}}
 ` + "```" + `
 
 ### 测试
@@ -99,9 +178,9 @@ phjvgen add payment
 
 - Java 25 LTS
 - Spring Boot 4.0.0-RC1
-- MyBatis Plus 3.5.8+
-- MySQL 8.0+
-`
+- {{PERSISTENCE_DISPLAY_NAME}}
+- {{DB_DISPLAY_NAME}}
+{{MIGRATION_TECH_STACK}}{{TENANT_TECH_STACK}}{{ID_TECH_STACK}}`
 
 // GitIgnore is the .gitignore template
 const GitIgnore = `# Maven
@@ -113,6 +192,11 @@ pom.xml.next
 release.properties
 dependency-reduced-pom.xml
 
+# Gradle
+.gradle/
+build/
+!gradle/wrapper/gradle-wrapper.jar
+
 # IntelliJ IDEA
 .idea/
 *.iml
@@ -143,23 +227,10 @@ logs/
 .DS_Store
 Thumbs.db
 
+# H2
+*.mv.db
+*.trace.db
+
 # Application
 application-local.yml
 `
-
-// UserTableSQL is the SQL for creating user table
-const UserTableSQL = `CREATE TABLE IF NOT EXISTS ` + "`t_user`" + ` (
-    ` + "`id`" + ` BIGINT NOT NULL AUTO_INCREMENT COMMENT '主键ID',
-    ` + "`username`" + ` VARCHAR(50) NOT NULL COMMENT '用户名',
-    ` + "`email`" + ` VARCHAR(100) COMMENT '邮箱',
-    ` + "`phone`" + ` VARCHAR(20) COMMENT '手机号',
-    ` + "`status`" + ` TINYINT NOT NULL DEFAULT 1 COMMENT '状态：0-禁用，1-启用',
-    ` + "`create_time`" + ` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
-    ` + "`update_time`" + ` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
-    ` + "`deleted`" + ` TINYINT NOT NULL DEFAULT 0 COMMENT '删除标记：0-未删除，1-已删除',
-    PRIMARY KEY (` + "`id`" + `),
-    UNIQUE KEY ` + "`uk_username`" + ` (` + "`username`" + `),
-    KEY ` + "`idx_email`" + ` (` + "`email`" + `),
-    KEY ` + "`idx_phone`" + ` (` + "`phone`" + `)
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户表';
-`
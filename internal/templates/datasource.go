@@ -0,0 +1,262 @@
+package templates
+
+// PrimaryDataSourceConfig is the primary datasource configuration template.
+// Once a project declares its own DataSource beans, Spring Boot no longer
+// creates the one of spring.datasource.
+const PrimaryDataSourceConfig = `package {{PACKAGE_NAME}}.infrastructure.config.datasource;
+
+import com.zaxxer.hikari.HikariDataSource;
+import org.springframework.beans.factory.annotation.Qualifier;
+import org.springframework.boot.context.properties.ConfigurationProperties;
+import org.springframework.boot.context.properties.EnableConfigurationProperties;
+import org.springframework.boot.jdbc.autoconfigure.DataSourceProperties;
+import org.springframework.context.annotation.Bean;
+import org.springframework.context.annotation.Configuration;
+import org.springframework.context.annotation.Primary;
+import org.springframework.jdbc.datasource.LazyConnectionDataSourceProxy;
+
+import javax.sql.DataSource;
+
+/**
+ * 主数据源配置
+ *
+ * 主库连接沿用 spring.datasource；app.datasource.replicas 下的只读副本由
+ * ReadWriteRoutingDataSource 统一路由：只读事务（@Transactional(readOnly = true)）
+ * 轮询访问副本，其余访问主库
+ */
+@Configuration
+@EnableConfigurationProperties(ReplicaDataSourceProperties.class)
+public class PrimaryDataSourceConfig {
+
+    /**
+     * 主库（写库）连接池
+     */
+    @Bean
+    @ConfigurationProperties("spring.datasource.hikari")
+    public HikariDataSource primaryDataSource(DataSourceProperties properties) {
+        return properties.initializeDataSourceBuilder().type(HikariDataSource.class).build();
+    }
+
+    /**
+     * 按事务是否只读在主库与副本之间路由
+     */
+    @Bean
+    public ReadWriteRoutingDataSource routingDataSource(@Qualifier("primaryDataSource") DataSource primaryDataSource,
+                                                        ReplicaDataSourceProperties replicas) {
+        return new ReadWriteRoutingDataSource(primaryDataSource, replicas.getReplicas());
+    }
+
+    /**
+     * 应用使用的数据源
+     *
+     * 事务开始时只读标志尚未生效，延迟到执行第一条语句时再获取连接，路由才能看到它
+     */
+    @Bean
+    @Primary
+    public DataSource dataSource(ReadWriteRoutingDataSource routingDataSource) {
+        return new LazyConnectionDataSourceProxy(routingDataSource);
+    }
+}
+`
+
+// ReplicaDataSourceProperties is the read replica properties template
+const ReplicaDataSourceProperties = `package {{PACKAGE_NAME}}.infrastructure.config.datasource;
+
+import com.zaxxer.hikari.HikariConfig;
+import lombok.Data;
+import org.springframework.boot.context.properties.ConfigurationProperties;
+
+import java.util.LinkedHashMap;
+import java.util.Map;
+
+/**
+ * 只读副本配置，键为副本名称，值为 HikariCP 连接池配置（jdbc-url、username 等）
+ */
+@Data
+@ConfigurationProperties("app.datasource")
+public class ReplicaDataSourceProperties {
+
+    private Map<String, HikariConfig> replicas = new LinkedHashMap<>();
+}
+`
+
+// ReadWriteRoutingDataSource is the read/write routing datasource template
+const ReadWriteRoutingDataSource = `package {{PACKAGE_NAME}}.infrastructure.config.datasource;
+
+import com.zaxxer.hikari.HikariConfig;
+import com.zaxxer.hikari.HikariDataSource;
+import org.springframework.jdbc.datasource.lookup.AbstractRoutingDataSource;
+import org.springframework.transaction.support.TransactionSynchronizationManager;
+
+import javax.sql.DataSource;
+import java.util.ArrayList;
+import java.util.HashMap;
+import java.util.List;
+import java.util.Map;
+import java.util.concurrent.atomic.AtomicInteger;
+
+/**
+ * 读写分离路由数据源
+ *
+ * 只读事务轮询访问只读副本，其余访问主库；没有配置副本时全部访问主库
+ */
+public class ReadWriteRoutingDataSource extends AbstractRoutingDataSource implements AutoCloseable {
+
+    private static final String PRIMARY = "primary";
+
+    private final List<String> replicaNames = new ArrayList<>();
+
+    private final List<HikariDataSource> replicas = new ArrayList<>();
+
+    private final AtomicInteger counter = new AtomicInteger();
+
+    public ReadWriteRoutingDataSource(DataSource primary, Map<String, HikariConfig> replicaConfigs) {
+        Map<Object, Object> targets = new HashMap<>();
+        targets.put(PRIMARY, primary);
+        replicaConfigs.forEach((name, config) -> {
+            config.setPoolName(name);
+            config.setReadOnly(true);
+            HikariDataSource replica = new HikariDataSource(config);
+            replicaNames.add(name);
+            replicas.add(replica);
+            targets.put(name, replica);
+        });
+        setTargetDataSources(targets);
+        setDefaultTargetDataSource(primary);
+    }
+
+    @Override
+    protected Object determineCurrentLookupKey() {
+        if (replicaNames.isEmpty() || !TransactionSynchronizationManager.isCurrentTransactionReadOnly()) {
+            return PRIMARY;
+        }
+        return replicaNames.get(Math.floorMod(counter.getAndIncrement(), replicaNames.size()));
+    }
+
+    /**
+     * 关闭副本连接池，主库连接池由 Spring 管理
+     */
+    @Override
+    public void close() {
+        replicas.forEach(HikariDataSource::close);
+    }
+}
+`
+
+// PrimaryMybatisConfig is the MyBatis-Plus configuration of the primary
+// datasource. A second SqlSessionFactory or transaction manager turns off
+// the auto-configured ones, so they are declared here.
+const PrimaryMybatisConfig = `package {{PACKAGE_NAME}}.infrastructure.config.datasource;
+
+import com.baomidou.mybatisplus.autoconfigure.MybatisPlusProperties;
+import com.baomidou.mybatisplus.core.MybatisConfiguration;
+import com.baomidou.mybatisplus.extension.plugins.MybatisPlusInterceptor;
+import com.baomidou.mybatisplus.extension.spring.MybatisSqlSessionFactoryBean;
+import org.apache.ibatis.session.SqlSessionFactory;
+import org.springframework.context.annotation.Bean;
+import org.springframework.context.annotation.Configuration;
+import org.springframework.context.annotation.Primary;
+import org.springframework.jdbc.support.JdbcTransactionManager;
+import org.springframework.transaction.PlatformTransactionManager;
+
+import javax.sql.DataSource;
+
+/**
+ * 主数据源的 MyBatis-Plus 配置
+ *
+ * 项目声明了其他数据源的 SqlSessionFactory 和事务管理器后，自动配置不再生效，
+ * 主数据源的这两个 Bean 在这里声明。Application 上的 @MapperScan 使用它们
+ */
+@Configuration
+public class PrimaryMybatisConfig {
+
+    @Bean
+    @Primary
+    public SqlSessionFactory sqlSessionFactory(DataSource dataSource, MybatisPlusProperties properties,
+                                               MybatisPlusInterceptor interceptor) throws Exception {
+        MybatisSqlSessionFactoryBean factory = new MybatisSqlSessionFactoryBean();
+        factory.setDataSource(dataSource);
+        MybatisConfiguration configuration = new MybatisConfiguration();
+        if (properties.getConfiguration() != null) {
+            properties.getConfiguration().applyTo(configuration);
+        }
+        factory.setConfiguration(configuration);
+        factory.setGlobalConfig(properties.getGlobalConfig());
+        factory.setMapperLocations(properties.resolveMapperLocations());
+        factory.setPlugins(interceptor);
+        return factory.getObject();
+    }
+
+    @Bean
+    @Primary
+    public PlatformTransactionManager transactionManager(DataSource dataSource) {
+        return new JdbcTransactionManager(dataSource);
+    }
+}
+`
+
+// SecondaryDataSourceConfig is the configuration template of a datasource
+// with its own mappers
+const SecondaryDataSourceConfig = `package {{PACKAGE_NAME}}.infrastructure.config.datasource;
+
+import com.baomidou.mybatisplus.autoconfigure.MybatisPlusProperties;
+import com.baomidou.mybatisplus.core.MybatisConfiguration;
+import com.baomidou.mybatisplus.extension.plugins.MybatisPlusInterceptor;
+import com.baomidou.mybatisplus.extension.spring.MybatisSqlSessionFactoryBean;
+import com.zaxxer.hikari.HikariDataSource;
+import org.apache.ibatis.session.SqlSessionFactory;
+import org.mybatis.spring.annotation.MapperScan;
+import org.springframework.beans.factory.annotation.Qualifier;
+import org.springframework.boot.context.properties.ConfigurationProperties;
+import org.springframework.context.annotation.Bean;
+import org.springframework.context.annotation.Configuration;
+import org.springframework.core.io.support.PathMatchingResourcePatternResolver;
+import org.springframework.jdbc.support.JdbcTransactionManager;
+import org.springframework.transaction.PlatformTransactionManager;
+
+import javax.sql.DataSource;
+
+/**
+ * {{DATASOURCE_NAME}} 数据源配置
+ *
+ * 连接配置在 app.datasource.{{DATASOURCE_NAME}} 下。该数据源的 Mapper 放在
+ * infrastructure.persistence.{{DATASOURCE_PACKAGE}}.mapper 包，XML 放在 resources/mapper-{{DATASOURCE_NAME}} 下；
+ * 其事务需要指定 @Transactional(transactionManager = "{{DATASOURCE_BEAN}}TransactionManager")
+ */
+@Configuration
+@MapperScan(basePackages = "{{PACKAGE_NAME}}.infrastructure.persistence.{{DATASOURCE_PACKAGE}}.mapper",
+        sqlSessionFactoryRef = "{{DATASOURCE_BEAN}}SqlSessionFactory")
+public class {{DATASOURCE_CLASS}}DataSourceConfig {
+
+    @Bean
+    @ConfigurationProperties("app.datasource.{{DATASOURCE_NAME}}")
+    public HikariDataSource {{DATASOURCE_BEAN}}DataSource() {
+        HikariDataSource dataSource = new HikariDataSource();
+        dataSource.setPoolName("{{DATASOURCE_NAME}}");
+        return dataSource;
+    }
+
+    @Bean
+    public SqlSessionFactory {{DATASOURCE_BEAN}}SqlSessionFactory(
+            @Qualifier("{{DATASOURCE_BEAN}}DataSource") DataSource dataSource,
+            MybatisPlusProperties properties, MybatisPlusInterceptor interceptor) throws Exception {
+        MybatisSqlSessionFactoryBean factory = new MybatisSqlSessionFactoryBean();
+        factory.setDataSource(dataSource);
+        MybatisConfiguration configuration = new MybatisConfiguration();
+        if (properties.getConfiguration() != null) {
+            properties.getConfiguration().applyTo(configuration);
+        }
+        factory.setConfiguration(configuration);
+        factory.setMapperLocations(new PathMatchingResourcePatternResolver()
+                .getResources("classpath*:/mapper-{{DATASOURCE_NAME}}/**/*Mapper.xml"));
+        factory.setPlugins(interceptor);
+        return factory.getObject();
+    }
+
+    @Bean
+    public PlatformTransactionManager {{DATASOURCE_BEAN}}TransactionManager(
+            @Qualifier("{{DATASOURCE_BEAN}}DataSource") DataSource dataSource) {
+        return new JdbcTransactionManager(dataSource);
+    }
+}
+`
@@ -0,0 +1,274 @@
+package templates
+
+// SettingsGradle is the settings.gradle.kts template, with the same module graph as ParentPOM.
+// Projects are named after their Maven artifactId; module() maps them to their directory.
+const SettingsGradle = `rootProject.name = "{{ARTIFACT_ID}}"
+
+fun module(name: String, path: String = name) {
+    include(name)
+    project(":$name").projectDir = file(path)
+}
+
+module("common")
+module("domain")
+module("infrastructure")
+module("adapter-rest", "adapter/adapter-rest")
+module("adapter-schedule", "adapter/adapter-schedule")
+module("application-user", "application/application-user")
+module("starter")
+`
+
+// LibsVersionsToml is the gradle/libs.versions.toml template, mirroring the properties of ParentPOM
+const LibsVersionsToml = `[versions]
+# Java 25 LTS
+java = "25"
+
+# Spring Boot
+spring-boot = "4.0.0-RC1"
+
+# 数据库
+{{PERSISTENCE_CATALOG_VERSION}}{{DB_DRIVER_VERSION_NAME}} = "{{DB_DRIVER_VERSION}}"
+hikaricp = "6.0.0"
+
+# 工具库
+lombok = "1.18.42"
+mapstruct = "1.6.0"
+hutool = "5.8.28"
+guava = "33.3.0-jre"
+commons-lang3 = "3.15.0"
+
+# Redis
+redisson = "3.30.0"
+caffeine = "3.1.8"
+
+# JSON
+jackson = "2.17.0"
+fastjson2 = "2.0.52"
+
+# 监控
+micrometer = "1.13.0"
+
+[libraries]
+# Spring Boot Dependencies（版本由 BOM 管理）
+spring-boot-dependencies = { module = "org.springframework.boot:spring-boot-dependencies", version.ref = "spring-boot" }
+spring-boot-starter = { module = "org.springframework.boot:spring-boot-starter" }
+spring-boot-starter-web = { module = "org.springframework.boot:spring-boot-starter-web" }
+spring-boot-starter-validation = { module = "org.springframework.boot:spring-boot-starter-validation" }
+spring-boot-starter-actuator = { module = "org.springframework.boot:spring-boot-starter-actuator" }
+spring-boot-starter-test = { module = "org.springframework.boot:spring-boot-starter-test" }
+spring-tx = { module = "org.springframework:spring-tx" }
+jackson-databind = { module = "com.fasterxml.jackson.core:jackson-databind" }
+micrometer-registry-prometheus = { module = "io.micrometer:micrometer-registry-prometheus" }
+
+{{PERSISTENCE_CATALOG_LIBRARY}}# JDBC 驱动
+{{DB_DRIVER_ARTIFACT_ID}} = { module = "{{DB_DRIVER_GROUP_ID}}:{{DB_DRIVER_ARTIFACT_ID}}", version.ref = "{{DB_DRIVER_VERSION_NAME}}" }
+
+{{MIGRATION_CATALOG_LIBRARIES}}# HikariCP
+hikaricp = { module = "com.zaxxer:HikariCP", version.ref = "hikaricp" }
+
+# Lombok
+lombok = { module = "org.projectlombok:lombok", version.ref = "lombok" }
+
+# MapStruct
+mapstruct = { module = "org.mapstruct:mapstruct", version.ref = "mapstruct" }
+mapstruct-processor = { module = "org.mapstruct:mapstruct-processor", version.ref = "mapstruct" }
+
+# 工具库
+hutool-all = { module = "cn.hutool:hutool-all", version.ref = "hutool" }
+guava = { module = "com.google.guava:guava", version.ref = "guava" }
+commons-lang3 = { module = "org.apache.commons:commons-lang3", version.ref = "commons-lang3" }
+
+# Redis
+redisson-spring-boot-starter = { module = "org.redisson:redisson-spring-boot-starter", version.ref = "redisson" }
+caffeine = { module = "com.github.ben-manes.caffeine:caffeine", version.ref = "caffeine" }
+
+# JSON
+fastjson2 = { module = "com.alibaba.fastjson2:fastjson2", version.ref = "fastjson2" }
+
+[plugins]
+spring-boot = { id = "org.springframework.boot", version.ref = "spring-boot" }
+`
+
+// RootBuildGradle is the root build.gradle.kts template, the Gradle counterpart of the
+// properties, dependencyManagement and pluginManagement of ParentPOM
+const RootBuildGradle = `plugins {
+    alias(libs.plugins.spring.boot) apply false
+}
+
+allprojects {
+    group = "{{GROUP_ID}}"
+    version = "{{VERSION}}"
+
+    repositories {
+        mavenCentral()
+    }
+}
+
+subprojects {
+    apply(plugin = "java-library")
+
+    configure<JavaPluginExtension> {
+        toolchain {
+            languageVersion = JavaLanguageVersion.of(rootProject.libs.versions.java.get().toInt())
+        }
+    }
+
+    dependencies {
+        // Spring Boot Dependencies
+        "implementation"(platform(rootProject.libs.spring.boot.dependencies))
+        "testImplementation"(platform(rootProject.libs.spring.boot.dependencies))
+
+        // Lombok 与 MapStruct 注解处理器
+        "compileOnly"(rootProject.libs.lombok)
+        "annotationProcessor"(rootProject.libs.lombok)
+        "annotationProcessor"(rootProject.libs.mapstruct.processor)
+        "testCompileOnly"(rootProject.libs.lombok)
+        "testAnnotationProcessor"(rootProject.libs.lombok)
+    }
+
+    tasks.withType<JavaCompile> {
+        options.encoding = "UTF-8"
+        options.compilerArgs.add("--enable-preview")
+    }
+
+    tasks.withType<Test> {
+        useJUnitPlatform()
+        jvmArgs("--enable-preview")
+    }
+}
+`
+
+// CommonBuildGradle is the common module build.gradle.kts template
+const CommonBuildGradle = `description = "公共模块"
+
+dependencies {
+    api(libs.spring.boot.starter)
+    api(libs.spring.boot.starter.validation)
+    api(libs.hutool.all)
+    api(libs.jackson.databind)
+}
+`
+
+// DomainBuildGradle is the domain module build.gradle.kts template
+const DomainBuildGradle = `description = "领域层"
+
+dependencies {
+    api(project(":common"))
+}
+`
+
+// InfrastructureBuildGradle is the infrastructure module build.gradle.kts template
+const InfrastructureBuildGradle = `description = "基础设施层"
+
+dependencies {
+    api(project(":domain"))
+    api(project(":common"))
+    api({{PERSISTENCE_ACCESSOR}})
+    api({{DB_DRIVER_ACCESSOR}})
+{{MIGRATION_GRADLE_DEPENDENCIES}}    implementation(libs.redisson.spring.boot.starter)
+    api(libs.caffeine)
+}
+`
+
+// AdapterRestBuildGradle is the adapter-rest module build.gradle.kts template
+const AdapterRestBuildGradle = `description = "REST适配器"
+
+dependencies {
+    api(project(":application-user"))
+    api(libs.spring.boot.starter.web)
+    api(libs.spring.boot.starter.validation)
+    api(libs.mapstruct)
+}
+`
+
+// AdapterScheduleBuildGradle is the adapter-schedule module build.gradle.kts template
+const AdapterScheduleBuildGradle = `description = "定时任务适配器"
+
+dependencies {
+    api(project(":application-user"))
+    api(libs.spring.boot.starter)
+}
+`
+
+// ApplicationUserBuildGradle is the application-user module build.gradle.kts template
+const ApplicationUserBuildGradle = `description = "用户业务应用层"
+
+dependencies {
+    api(project(":domain"))
+    api(project(":infrastructure"))
+    api(project(":common"))
+    api(libs.spring.boot.starter)
+    api(libs.mapstruct)
+}
+`
+
+// StarterBuildGradle is the starter module build.gradle.kts template
+const StarterBuildGradle = `plugins {
+    id("org.springframework.boot")
+}
+
+description = "启动模块"
+
+dependencies {
+    implementation(project(":adapter-rest"))
+    implementation(project(":adapter-schedule"))
+    implementation(libs.spring.boot.starter.actuator)
+    implementation(libs.micrometer.registry.prometheus)
+}
+
+tasks.named<org.springframework.boot.gradle.tasks.bundling.BootJar>("bootJar") {
+    archiveFileName = "starter-${project.version}.jar"
+}
+
+// application.yml 中的 @spring.profiles.active@ 由 -Pprofile=<profile> 填充
+val springProfile = providers.gradleProperty("profile").getOrElse("{{DEFAULT_PROFILE}}")
+
+tasks.processResources {
+    inputs.property("springProfile", springProfile)
+    filesMatching("application.yml") {
+        filter<org.apache.tools.ant.filters.ReplaceTokens>("tokens" to mapOf("spring.profiles.active" to springProfile))
+    }
+}
+`
+
+// ApplicationModuleBuildGradle is the build.gradle.kts template for new application modules
+const ApplicationModuleBuildGradle = `description = "{{MODULE_DESCRIPTION}}业务应用层"
+
+dependencies {
+    api(project(":domain"))
+    api(project(":infrastructure"))
+    api(project(":common"))
+    api(libs.spring.boot.starter)
+    api(libs.mapstruct)
+}
+`
+
+// DomainContextBuildGradle is the build.gradle.kts template for the domain module of a bounded context
+const DomainContextBuildGradle = `description = "{{CONTEXT_DESCRIPTION}}领域层"
+
+dependencies {
+    api(project(":common"))
+}
+`
+
+// InfrastructureContextBuildGradle is the build.gradle.kts template for the infrastructure module of a bounded context
+const InfrastructureContextBuildGradle = `description = "{{CONTEXT_DESCRIPTION}}基础设施层"
+
+dependencies {
+    api(project(":domain-{{CONTEXT_NAME}}"))
+    api(project(":common"))
+    api({{PERSISTENCE_ACCESSOR}})
+}
+`
+
+// ApplicationContextBuildGradle is the build.gradle.kts template for the application module of a bounded context
+const ApplicationContextBuildGradle = `description = "{{CONTEXT_DESCRIPTION}}业务应用层"
+
+dependencies {
+    api(project(":domain-{{CONTEXT_NAME}}"))
+    api(project(":common"))
+    api(libs.spring.boot.starter)
+    api(libs.spring.tx)
+    api(libs.mapstruct)
+}
+`
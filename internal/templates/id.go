@@ -0,0 +1,127 @@
+package templates
+
+// IdGenerator is the template of the domain's primary key generator
+const IdGenerator = `package {{PACKAGE_NAME}}.domain.service;
+
+/**
+ * 主键生成器
+ *
+ * 主键在插入前由应用分配，而不是由数据库自增生成：聚合创建时即有ID，
+ * 可以在同一事务中被其他对象引用，也便于分库分表。实现见
+ * infrastructure.id 包
+ */
+public interface IdGenerator {
+
+    /**
+     * 生成新的主键
+     */
+    {{USER_ID_TYPE}} nextId();
+}
+`
+
+// SnowflakeIdGenerator is the template of the Snowflake ID generator
+const SnowflakeIdGenerator = `package {{PACKAGE_NAME}}.infrastructure.id;
+
+import {{PACKAGE_NAME}}.domain.service.IdGenerator;
+import lombok.extern.slf4j.Slf4j;
+import org.springframework.beans.factory.annotation.Value;
+import org.springframework.stereotype.Component;
+
+/**
+ * 雪花算法主键生成器
+ *
+ * 64 位ID = 41 位毫秒时间戳（自 2025-01-01 起，约可用 69 年）+ 10 位机器号 + 12 位序号，
+ * 每个实例每毫秒最多生成 4096 个，整体按时间递增。
+ *
+ * 机器号由 app.id.worker-id 配置（0-1023），同时运行的每个实例必须不同，否则会生成
+ * 重复的ID。时钟回拨时沿用上次的时间戳继续分配序号，序号用尽后等待时钟追上。
+ *
+ * ID 超出 JavaScript 的安全整数范围（2^53），响应中以字符串返回，见 UserResponseVO
+ */
+@Slf4j
+@Component
+public class SnowflakeIdGenerator implements IdGenerator {
+
+    private static final long EPOCH = 1735689600000L;
+    private static final int WORKER_ID_BITS = 10;
+    private static final int SEQUENCE_BITS = 12;
+    private static final long MAX_WORKER_ID = (1L << WORKER_ID_BITS) - 1;
+    private static final long SEQUENCE_MASK = (1L << SEQUENCE_BITS) - 1;
+
+    private final long workerId;
+    private long lastTimestamp = -1L;
+    private long sequence = 0L;
+
+    public SnowflakeIdGenerator(@Value("${app.id.worker-id}") long workerId) {
+        if (workerId < 0 || workerId > MAX_WORKER_ID) {
+            throw new IllegalArgumentException("app.id.worker-id 必须在 0-" + MAX_WORKER_ID + " 之间: " + workerId);
+        }
+        this.workerId = workerId;
+        log.info("Snowflake worker id: {}", workerId);
+    }
+
+    @Override
+    public synchronized Long nextId() {
+        long timestamp = Math.max(System.currentTimeMillis(), lastTimestamp);
+        if (timestamp == lastTimestamp) {
+            sequence = (sequence + 1) & SEQUENCE_MASK;
+            if (sequence == 0) {
+                timestamp = waitUntilAfter(lastTimestamp);
+            }
+        } else {
+            sequence = 0L;
+        }
+        lastTimestamp = timestamp;
+        return ((timestamp - EPOCH) << (WORKER_ID_BITS + SEQUENCE_BITS))
+            | (workerId << SEQUENCE_BITS)
+            | sequence;
+    }
+
+    private long waitUntilAfter(long timestamp) {
+        long now = System.currentTimeMillis();
+        while (now <= timestamp) {
+            Thread.onSpinWait();
+            now = System.currentTimeMillis();
+        }
+        return now;
+    }
+}
+`
+
+// UuidV7IdGenerator is the template of the UUIDv7 ID generator
+const UuidV7IdGenerator = `package {{PACKAGE_NAME}}.infrastructure.id;
+
+import {{PACKAGE_NAME}}.domain.service.IdGenerator;
+import org.springframework.stereotype.Component;
+
+import java.nio.ByteBuffer;
+import java.security.SecureRandom;
+import java.util.UUID;
+
+/**
+ * UUIDv7 主键生成器（RFC 9562）
+ *
+ * 前 48 位为毫秒时间戳，其余为随机数：不需要协调机器号，整体按时间递增，
+ * 写入 B+ 树索引时不会像 UUIDv4 那样随机分裂页。以 36 位字符串保存
+ */
+@Component
+public class UuidV7IdGenerator implements IdGenerator {
+
+    private final SecureRandom random = new SecureRandom();
+
+    @Override
+    public String nextId() {
+        byte[] bytes = new byte[16];
+        random.nextBytes(bytes);
+        long timestamp = System.currentTimeMillis();
+        for (int i = 0; i < 6; i++) {
+            bytes[i] = (byte) (timestamp >>> (40 - 8 * i));
+        }
+        // 版本 7，变体 10
+        bytes[6] = (byte) ((bytes[6] & 0x0f) | 0x70);
+        bytes[8] = (byte) ((bytes[8] & 0x3f) | 0x80);
+        ByteBuffer buffer = ByteBuffer.wrap(bytes);
+        return new UUID(buffer.getLong(), buffer.getLong()).toString();
+    }
+}
+`
@@ -1,9 +1,9 @@
 package templates
 
-// ApplicationMain is the Spring Boot application main class template
+// ApplicationMain is the Spring Boot application main class template. The
+// @MapperScan placeholders are empty unless the project uses MyBatis-Plus.
 const ApplicationMain = `package {{PACKAGE_NAME}};
-
-import org.mybatis.spring.annotation.MapperScan;
+{{MAPPER_SCAN_IMPORT}}
 import org.springframework.boot.SpringApplication;
 import org.springframework.boot.autoconfigure.SpringBootApplication;
 import org.springframework.scheduling.annotation.EnableAsync;
@@ -12,8 +12,7 @@ import org.springframework.scheduling.annotation.EnableAsync;
  * 应用启动类
  */
 @EnableAsync
-@SpringBootApplication
-@MapperScan("{{PACKAGE_NAME}}.infrastructure.persistence.mapper")
+@SpringBootApplication{{MAPPER_SCAN}}
 public class Application {
     public static void main(String[] args) {
         SpringApplication.run(Application.class, args);
@@ -182,7 +181,7 @@ public class UserCreatedEvent {
     /**
      * 用户ID
      */
-    private final Long userId;
+    private final {{USER_ID_TYPE}} userId;
 
     /**
      * 用户名
@@ -199,7 +198,7 @@ public class UserCreatedEvent {
      */
     private final LocalDateTime occurredOn;
 
-    public UserCreatedEvent(Long userId, String username, String email) {
+    public UserCreatedEvent({{USER_ID_TYPE}} userId, String username, String email) {
         this.userId = userId;
         this.username = username;
         this.email = email;
@@ -230,7 +229,7 @@ public class UserDomainService {
 
     private final UserRepository userRepository;
     private final ApplicationEventPublisher eventPublisher;
-
+{{ID_GENERATOR_FIELD}}
     /**
      * 注册新用户（领域逻辑）
      *
@@ -244,13 +243,7 @@ public class UserDomainService {
             throw new IllegalArgumentException("用户名已存在: " + username);
         }
 
-        // 步骤2: 创建用户实体
-        User user = new User();
-        user.setUsername(username);
-        user.setEmail(email);
-        user.setPhone(phone);
-        user.enable(); // 使用领域方法设置状态
-
+{{USER_CREATION}}
         // 步骤3: 持久化到数据库
         user = userRepository.save(user);
 
@@ -288,7 +281,7 @@ public class UserDomainService {
     /**
      * 检查用户是否可以被删除
      */
-    public boolean canDelete(Long userId) {
+    public boolean canDelete({{USER_ID_TYPE}} userId) {
         // 这里可以添加复杂的业务规则
         // 例如：检查用户是否有未完成的订单、是否欠款等
         return true;
@@ -300,7 +293,7 @@ public class UserDomainService {
 const UserEventListener = `package {{PACKAGE_NAME}}.application.user.listener;
 
 import {{PACKAGE_NAME}}.domain.event.UserCreatedEvent;
-import lombok.extern.slf4j.Slf4j;
+{{TENANT_LISTENER_IMPORT}}import lombok.extern.slf4j.Slf4j;
 import org.springframework.context.event.EventListener;
 import org.springframework.scheduling.annotation.Async;
 import org.springframework.stereotype.Component;
@@ -363,7 +356,7 @@ public class UserEventListener {
         log.info("========== 开始处理用户创建事件 ==========");
         log.info("用户ID: {}, 用户名: {}, 邮箱: {}",
             event.getUserId(), event.getUsername(), event.getEmail());
-
+{{TENANT_LISTENER_LOG}}
         try {
             // 业务逻辑1: 发送欢迎邮件
             sendWelcomeEmail(event.getEmail(), event.getUsername());
@@ -407,7 +400,7 @@ public class UserEventListener {
      *
      * 实际项目中应该写入统计表或调用统计服务
      */
-    private void recordUserRegistration(Long userId) {
+    private void recordUserRegistration({{USER_ID_TYPE}} userId) {
         // 模拟记录统计
         log.info("→ 记录用户注册统计, userId: {}", userId);
 
@@ -432,7 +425,7 @@ public class User {
     /**
      * 用户ID
      */
-    private Long id;
+    private {{USER_ID_TYPE}} id;
 
     /**
      * 用户名
@@ -463,7 +456,7 @@ public class User {
      * 更新时间
      */
     private LocalDateTime updateTime;
-
+{{USER_FACTORY}}
     /**
      * 是否启用
      */
@@ -502,7 +495,7 @@ public interface UserRepository {
     /**
      * 根据ID查找用户
      */
-    Optional<User> findById(Long id);
+    Optional<User> findById({{USER_ID_TYPE}} id);
 
     /**
      * 根据用户名查找用户
@@ -527,7 +520,7 @@ public interface UserRepository {
     /**
      * 删除用户
      */
-    void deleteById(Long id);
+    void deleteById({{USER_ID_TYPE}} id);
 
     /**
      * 检查用户名是否存在
@@ -550,9 +543,9 @@ import java.time.LocalDateTime;
 @TableName("t_user")
 public class UserDO {
 
-    @TableId(value = "id", type = IdType.AUTO)
-    private Long id;
-
+    @TableId(value = "id", type = IdType.{{MP_ID_TYPE}})
+    private {{USER_ID_TYPE}} id;
+{{TENANT_DO_FIELD}}
     @TableField("username")
     private String username;
 
@@ -592,6 +585,48 @@ public interface UserMapper extends BaseMapper<UserDO> {
 }
 `
 
+// MapperXML is the MyBatis mapper XML template of an entity, holding the
+// result map and column list shared by its custom queries
+const MapperXML = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE mapper PUBLIC "-//mybatis.org//DTD Mapper 3.0//EN" "https://mybatis.org/dtd/mybatis-3-mapper.dtd">
+<mapper namespace="{{PACKAGE_NAME}}.infrastructure.persistence.mapper.{{ENTITY_CLASS}}Mapper">
+
+    <resultMap id="BaseResultMap" type="{{PACKAGE_NAME}}.infrastructure.persistence.dataobject.{{ENTITY_CLASS}}DO">
+{{RESULT_MAP_COLUMNS}}    </resultMap>
+
+    <sql id="Base_Column_List">
+        {{BASE_COLUMN_LIST}}
+    </sql>
+</mapper>
+`
+
+// MybatisPlusConfig is the MyBatis-Plus configuration template
+const MybatisPlusConfig = `package {{PACKAGE_NAME}}.infrastructure.config;
+
+import com.baomidou.mybatisplus.annotation.DbType;
+import com.baomidou.mybatisplus.extension.plugins.MybatisPlusInterceptor;
+import com.baomidou.mybatisplus.extension.plugins.inner.PaginationInnerInterceptor;
+{{TENANT_INTERCEPTOR_IMPORTS}}import org.springframework.context.annotation.Bean;
+import org.springframework.context.annotation.Configuration;
+
+/**
+ * MyBatis-Plus配置
+ */
+@Configuration
+public class MybatisPlusConfig {
+
+    /**
+     * 分页插件，按数据库方言生成分页SQL{{TENANT_INTERCEPTOR_DOC}}
+     */
+    @Bean
+    public MybatisPlusInterceptor mybatisPlusInterceptor({{TENANT_INTERCEPTOR_PARAM}}) {
+        MybatisPlusInterceptor interceptor = new MybatisPlusInterceptor();
+{{TENANT_INTERCEPTOR}}        interceptor.addInnerInterceptor(new PaginationInnerInterceptor(DbType.{{DB_TYPE}}));
+        return interceptor;
+    }
+}
+`
+
 // UserRepositoryImpl is the User repository implementation template
 const UserRepositoryImpl = `package {{PACKAGE_NAME}}.infrastructure.persistence.impl;
 
@@ -616,7 +651,7 @@ public class UserRepositoryImpl implements UserRepository {
     private final UserMapper userMapper;
 
     @Override
-    public Optional<User> findById(Long id) {
+    public Optional<User> findById({{USER_ID_TYPE}} id) {
         UserDO userDO = userMapper.selectById(id);
         return Optional.ofNullable(userDO).map(this::toEntity);
     }
@@ -651,7 +686,7 @@ public class UserRepositoryImpl implements UserRepository {
     }
 
     @Override
-    public void deleteById(Long id) {
+    public void deleteById({{USER_ID_TYPE}} id) {
         userMapper.deleteById(id);
     }
 
@@ -694,6 +729,377 @@ public class UserRepositoryImpl implements UserRepository {
 }
 `
 
+// JpaUserDO is the User data object template for Spring Data JPA
+const JpaUserDO = `package {{PACKAGE_NAME}}.infrastructure.persistence.dataobject;
+
+import jakarta.persistence.*;
+import lombok.Getter;
+import lombok.Setter;
+import org.hibernate.annotations.CreationTimestamp;
+import org.hibernate.annotations.SQLDelete;
+import org.hibernate.annotations.SQLRestriction;
+import org.hibernate.annotations.UpdateTimestamp;
+import java.time.LocalDateTime;
+
+/**
+ * 用户数据对象
+ * 删除为逻辑删除：deleteById 只将 deleted 置为 1，查询自动过滤已删除的行
+ */
+@Getter
+@Setter
+@Entity
+@Table(name = "t_user")
+@SQLDelete(sql = "UPDATE t_user SET deleted = 1 WHERE id = ?")
+@SQLRestriction("deleted = 0")
+public class UserDO {
+
+    @Id
+{{JPA_ID_GENERATION}}    private {{USER_ID_TYPE}} id;
+
+    @Column(name = "username")
+    private String username;
+
+    @Column(name = "email")
+    private String email;
+
+    @Column(name = "phone")
+    private String phone;
+
+    @Column(name = "status")
+    private Integer status;
+
+    @CreationTimestamp
+    @Column(name = "create_time", updatable = false)
+    private LocalDateTime createTime;
+
+    @UpdateTimestamp
+    @Column(name = "update_time")
+    private LocalDateTime updateTime;
+
+    @Column(name = "deleted")
+    private Integer deleted = 0;
+}
+`
+
+// UserJpaRepository is the User Spring Data JPA repository template
+const UserJpaRepository = `package {{PACKAGE_NAME}}.infrastructure.persistence.repository;
+
+import {{PACKAGE_NAME}}.infrastructure.persistence.dataobject.UserDO;
+import org.springframework.data.jpa.repository.JpaRepository;
+import java.util.Optional;
+
+/**
+ * 用户JPA仓库
+ */
+public interface UserJpaRepository extends JpaRepository<UserDO, {{USER_ID_TYPE}}> {
+
+    Optional<UserDO> findByUsername(String username);
+
+    boolean existsByUsername(String username);
+}
+`
+
+// JpaUserRepositoryImpl is the User repository implementation template for Spring Data JPA
+const JpaUserRepositoryImpl = `package {{PACKAGE_NAME}}.infrastructure.persistence.impl;
+
+import {{PACKAGE_NAME}}.domain.model.User;
+import {{PACKAGE_NAME}}.domain.repository.UserRepository;
+import {{PACKAGE_NAME}}.infrastructure.persistence.dataobject.UserDO;
+import {{PACKAGE_NAME}}.infrastructure.persistence.repository.UserJpaRepository;
+import lombok.RequiredArgsConstructor;
+import org.springframework.stereotype.Repository;
+import java.util.List;
+import java.util.Optional;
+import java.util.stream.Collectors;
+
+/**
+ * 用户仓储实现
+ */
+@Repository
+@RequiredArgsConstructor
+public class UserRepositoryImpl implements UserRepository {
+
+    private final UserJpaRepository userJpaRepository;
+
+    @Override
+    public Optional<User> findById({{USER_ID_TYPE}} id) {
+        return userJpaRepository.findById(id).map(this::toEntity);
+    }
+
+    @Override
+    public Optional<User> findByUsername(String username) {
+        return userJpaRepository.findByUsername(username).map(this::toEntity);
+    }
+
+    @Override
+    public List<User> findAll() {
+        return userJpaRepository.findAll().stream()
+                .map(this::toEntity)
+                .collect(Collectors.toList());
+    }
+
+    @Override
+    public User save(User user) {
+        return toEntity(userJpaRepository.save(toDO(user)));
+    }
+
+    @Override
+    public User update(User user) {
+        return toEntity(userJpaRepository.save(toDO(user)));
+    }
+
+    @Override
+    public void deleteById({{USER_ID_TYPE}} id) {
+        userJpaRepository.deleteById(id);
+    }
+
+    @Override
+    public boolean existsByUsername(String username) {
+        return userJpaRepository.existsByUsername(username);
+    }
+
+    private User toEntity(UserDO userDO) {
+        if (userDO == null) {
+            return null;
+        }
+        User user = new User();
+        user.setId(userDO.getId());
+        user.setUsername(userDO.getUsername());
+        user.setEmail(userDO.getEmail());
+        user.setPhone(userDO.getPhone());
+        user.setStatus(userDO.getStatus());
+        user.setCreateTime(userDO.getCreateTime());
+        user.setUpdateTime(userDO.getUpdateTime());
+        return user;
+    }
+
+    private UserDO toDO(User user) {
+        if (user == null) {
+            return null;
+        }
+        UserDO userDO = new UserDO();
+        userDO.setId(user.getId());
+        userDO.setUsername(user.getUsername());
+        userDO.setEmail(user.getEmail());
+        userDO.setPhone(user.getPhone());
+        userDO.setStatus(user.getStatus());
+        userDO.setCreateTime(user.getCreateTime());
+        userDO.setUpdateTime(user.getUpdateTime());
+        return userDO;
+    }
+}
+`
+
+// JdbcUserDO is the User data object template for Spring JDBC, with the row
+// mapper reading it from a result set
+const JdbcUserDO = `package {{PACKAGE_NAME}}.infrastructure.persistence.dataobject;
+
+import lombok.Data;
+import org.springframework.jdbc.core.RowMapper;
+import java.time.LocalDateTime;
+
+/**
+ * 用户数据对象
+ */
+@Data
+public class UserDO {
+
+    /**
+     * t_user 行映射
+     */
+    public static final RowMapper<UserDO> ROW_MAPPER = (rs, rowNum) -> {
+        UserDO userDO = new UserDO();
+        userDO.setId(rs.{{JDBC_ID_GETTER}}("id"));
+        userDO.setUsername(rs.getString("username"));
+        userDO.setEmail(rs.getString("email"));
+        userDO.setPhone(rs.getString("phone"));
+        userDO.setStatus(rs.getInt("status"));
+        userDO.setCreateTime(rs.getObject("create_time", LocalDateTime.class));
+        userDO.setUpdateTime(rs.getObject("update_time", LocalDateTime.class));
+        userDO.setDeleted(rs.getInt("deleted"));
+        return userDO;
+    };
+
+    private {{USER_ID_TYPE}} id;
+
+    private String username;
+
+    private String email;
+
+    private String phone;
+
+    private Integer status;
+
+    private LocalDateTime createTime;
+
+    private LocalDateTime updateTime;
+
+    private Integer deleted;
+}
+`
+
+// UserDao is the User data access object template using JdbcClient
+const UserDao = `package {{PACKAGE_NAME}}.infrastructure.persistence.dao;
+
+import {{PACKAGE_NAME}}.infrastructure.persistence.dataobject.UserDO;
+import lombok.RequiredArgsConstructor;
+import org.springframework.jdbc.core.simple.JdbcClient;
+{{JDBC_KEY_HOLDER_IMPORTS}}import org.springframework.stereotype.Repository;
+import java.util.List;
+import java.util.Optional;
+
+/**
+ * 用户DAO
+ * 删除为逻辑删除：deleteById 只将 deleted 置为 1，查询均过滤已删除的行
+ */
+@Repository
+@RequiredArgsConstructor
+public class UserDao {
+
+    private static final String COLUMNS = "id, username, email, phone, status, create_time, update_time, deleted";
+
+    private final JdbcClient jdbcClient;
+
+    public Optional<UserDO> findById({{USER_ID_TYPE}} id) {
+        return jdbcClient.sql("SELECT " + COLUMNS + " FROM t_user WHERE id = :id AND deleted = 0")
+                .param("id", id)
+                .query(UserDO.ROW_MAPPER)
+                .optional();
+    }
+
+    public Optional<UserDO> findByUsername(String username) {
+        return jdbcClient.sql("SELECT " + COLUMNS + " FROM t_user WHERE username = :username AND deleted = 0")
+                .param("username", username)
+                .query(UserDO.ROW_MAPPER)
+                .optional();
+    }
+
+    public List<UserDO> findAll() {
+        return jdbcClient.sql("SELECT " + COLUMNS + " FROM t_user WHERE deleted = 0")
+                .query(UserDO.ROW_MAPPER)
+                .list();
+    }
+
+{{JDBC_USER_INSERT}}    public int update(UserDO userDO) {
+        return jdbcClient.sql("UPDATE t_user SET username = :username, email = :email, phone = :phone, status = :status, "
+                        + "update_time = CURRENT_TIMESTAMP WHERE id = :id AND deleted = 0")
+                .param("username", userDO.getUsername())
+                .param("email", userDO.getEmail())
+                .param("phone", userDO.getPhone())
+                .param("status", userDO.getStatus())
+                .param("id", userDO.getId())
+                .update();
+    }
+
+    public int deleteById({{USER_ID_TYPE}} id) {
+        return jdbcClient.sql("UPDATE t_user SET deleted = 1 WHERE id = :id")
+                .param("id", id)
+                .update();
+    }
+
+    public boolean existsByUsername(String username) {
+        return jdbcClient.sql("SELECT COUNT(*) FROM t_user WHERE username = :username AND deleted = 0")
+                .param("username", username)
+                .query(Long.class)
+                .single() > 0;
+    }
+}
+`
+
+// JdbcUserRepositoryImpl is the User repository implementation template for Spring JDBC
+const JdbcUserRepositoryImpl = `package {{PACKAGE_NAME}}.infrastructure.persistence.impl;
+
+import {{PACKAGE_NAME}}.domain.model.User;
+import {{PACKAGE_NAME}}.domain.repository.UserRepository;
+import {{PACKAGE_NAME}}.infrastructure.persistence.dao.UserDao;
+import {{PACKAGE_NAME}}.infrastructure.persistence.dataobject.UserDO;
+import lombok.RequiredArgsConstructor;
+import org.springframework.stereotype.Repository;
+import java.util.List;
+import java.util.Optional;
+import java.util.stream.Collectors;
+
+/**
+ * 用户仓储实现
+ */
+@Repository
+@RequiredArgsConstructor
+public class UserRepositoryImpl implements UserRepository {
+
+    private final UserDao userDao;
+
+    @Override
+    public Optional<User> findById({{USER_ID_TYPE}} id) {
+        return userDao.findById(id).map(this::toEntity);
+    }
+
+    @Override
+    public Optional<User> findByUsername(String username) {
+        return userDao.findByUsername(username).map(this::toEntity);
+    }
+
+    @Override
+    public List<User> findAll() {
+        return userDao.findAll().stream()
+                .map(this::toEntity)
+                .collect(Collectors.toList());
+    }
+
+    @Override
+    public User save(User user) {
+        {{USER_ID_TYPE}} id = userDao.insert(toDO(user));
+        // 重新读取，带回数据库生成的时间字段
+        return findById(id).orElseThrow();
+    }
+
+    @Override
+    public User update(User user) {
+        userDao.update(toDO(user));
+        return findById(user.getId()).orElseThrow();
+    }
+
+    @Override
+    public void deleteById({{USER_ID_TYPE}} id) {
+        userDao.deleteById(id);
+    }
+
+    @Override
+    public boolean existsByUsername(String username) {
+        return userDao.existsByUsername(username);
+    }
+
+    private User toEntity(UserDO userDO) {
+        if (userDO == null) {
+            return null;
+        }
+        User user = new User();
+        user.setId(userDO.getId());
+        user.setUsername(userDO.getUsername());
+        user.setEmail(userDO.getEmail());
+        user.setPhone(userDO.getPhone());
+        user.setStatus(userDO.getStatus());
+        user.setCreateTime(userDO.getCreateTime());
+        user.setUpdateTime(userDO.getUpdateTime());
+        return user;
+    }
+
+    private UserDO toDO(User user) {
+        if (user == null) {
+            return null;
+        }
+        UserDO userDO = new UserDO();
+        userDO.setId(user.getId());
+        userDO.setUsername(user.getUsername());
+        userDO.setEmail(user.getEmail());
+        userDO.setPhone(user.getPhone());
+        userDO.setStatus(user.getStatus());
+        userDO.setCreateTime(user.getCreateTime());
+        userDO.setUpdateTime(user.getUpdateTime());
+        return userDO;
+    }
+}
+`
+
 // UserDTO is the User DTO template
 const UserDTO = `package {{PACKAGE_NAME}}.application.user.dto;
 
@@ -702,7 +1108,7 @@ import java.time.LocalDateTime;
 
 @Data
 public class UserDTO {
-    private Long id;
+    private {{USER_ID_TYPE}} id;
     private String username;
     private String email;
     private String phone;
@@ -732,7 +1138,7 @@ import lombok.Data;
 
 @Data
 public class UpdateUserCommand {
-    private Long id;
+    private {{USER_ID_TYPE}} id;
     private String email;
     private String phone;
     private Integer status;
@@ -980,7 +1386,7 @@ public class UserService {
      *
      * 简单的查询操作，直接在 Service 中完成
      */
-    public UserDTO getUserById(Long id) {
+    public UserDTO getUserById({{USER_ID_TYPE}} id) {
         log.info("Getting user by id: {}", id);
 
         User user = userRepository.findById(id)
@@ -1010,7 +1416,7 @@ public class UserService {
      * 则应该创建一个 DeleteUserExecutor 来编排这些流程
      */
     @Transactional(rollbackFor = Exception.class)
-    public void deleteUser(Long id) {
+    public void deleteUser({{USER_ID_TYPE}} id) {
         log.info("Deleting user: {}", id);
 
         if (!userRepository.findById(id).isPresent()) {
@@ -1137,7 +1543,7 @@ public interface UserControllerAssembler {
     @Mapping(source = "request.email", target = "email")
     @Mapping(source = "request.phone", target = "phone")
     @Mapping(source = "request.status", target = "status")
-    UpdateUserCommand toUpdateCommand(Long id, UpdateUserRequest request);
+    UpdateUserCommand toUpdateCommand({{USER_ID_TYPE}} id, UpdateUserRequest request);
 }
 `
 
@@ -1154,8 +1560,8 @@ import java.time.LocalDateTime;
  */
 @Data
 public class UserResponseVO {
-
-    private Long id;
+{{ID_JSON_FORMAT}}
+    private {{USER_ID_TYPE}} id;
 
     private String username;
 
@@ -1375,7 +1781,7 @@ public class UserController {
      * 更新用户
      */
     @PutMapping("/{id}")
-    public Result<UserResponseVO> updateUser(@PathVariable Long id,
+    public Result<UserResponseVO> updateUser(@PathVariable {{USER_ID_TYPE}} id,
                                              @Validated @RequestBody UpdateUserRequest request) {
         UpdateUserCommand command = assembler.toUpdateCommand(id, request);
         UserDTO dto = userService.updateUser(command);
@@ -1387,7 +1793,7 @@ public class UserController {
      * 查询用户
      */
     @GetMapping("/{id}")
-    public Result<UserResponseVO> getUser(@PathVariable Long id) {
+    public Result<UserResponseVO> getUser(@PathVariable {{USER_ID_TYPE}} id) {
         UserDTO dto = userService.getUserById(id);
         UserResponseVO vo = responseAssembler.toUserResponseVO(dto);
         return Result.success(vo);
@@ -1409,7 +1815,7 @@ public class UserController {
      * 删除用户
      */
     @DeleteMapping("/{id}")
-    public Result<Void> deleteUser(@PathVariable Long id) {
+    public Result<Void> deleteUser(@PathVariable {{USER_ID_TYPE}} id) {
         userService.deleteUser(id);
         return Result.success();
     }
@@ -0,0 +1,140 @@
+package templates
+
+// ApplicationModuleService is the sample service template for new application modules
+const ApplicationModuleService = `package {{PACKAGE_NAME}}.application.{{MODULE_PACKAGE}}.service;
+
+import lombok.extern.slf4j.Slf4j;
+import org.springframework.stereotype.Service;
+
+/**
+ * {{MODULE_CLASS}}业务服务
+ */
+@Slf4j
+@Service
+public class {{MODULE_CLASS}}Service {
+
+    /**
+     * 示例方法
+     */
+    public String execute() {
+        log.info("Executing {{MODULE_CLASS}}Service");
+        return "{{MODULE_CLASS}} service executed successfully";
+    }
+}
+`
+
+// ApplicationModuleServiceWithExecutor is the sample service template that delegates to an executor
+const ApplicationModuleServiceWithExecutor = `package {{PACKAGE_NAME}}.application.{{MODULE_PACKAGE}}.service;
+
+import {{PACKAGE_NAME}}.application.{{MODULE_PACKAGE}}.executor.{{MODULE_CLASS}}Executor;
+import lombok.RequiredArgsConstructor;
+import lombok.extern.slf4j.Slf4j;
+import org.springframework.stereotype.Service;
+
+/**
+ * {{MODULE_CLASS}}业务服务
+ *
+ * 简单 CRUD 直接在 Service 中完成，复杂用例委托给 Executor
+ */
+@Slf4j
+@Service
+@RequiredArgsConstructor
+public class {{MODULE_CLASS}}Service {
+
+    private final {{MODULE_CLASS}}Executor {{MODULE_FIELD}}Executor;
+
+    /**
+     * 示例方法，委托给 {{MODULE_CLASS}}Executor 执行
+     */
+    public String execute() {
+        log.info("Executing {{MODULE_CLASS}}Service");
+        return {{MODULE_FIELD}}Executor.execute();
+    }
+}
+`
+
+// ApplicationModuleExecutor is the sample executor template for new application modules
+const ApplicationModuleExecutor = `package {{PACKAGE_NAME}}.application.{{MODULE_PACKAGE}}.executor;
+
+import lombok.RequiredArgsConstructor;
+import lombok.extern.slf4j.Slf4j;
+import org.springframework.stereotype.Component;
+import org.springframework.transaction.annotation.Transactional;
+
+/**
+ * {{MODULE_CLASS}}用例执行器
+ *
+ * 用于编排复杂的业务流程，参考 RegisterUserExecutor
+ */
+@Slf4j
+@Component
+@RequiredArgsConstructor
+public class {{MODULE_CLASS}}Executor {
+
+    /**
+     * 执行示例用例
+     */
+    @Transactional(rollbackFor = Exception.class)
+    public String execute() {
+        log.info("Executing {{MODULE_CLASS}}Executor");
+
+        // TODO: 编排业务流程
+
+        return "{{MODULE_CLASS}} executor executed successfully";
+    }
+}
+`
+
+// ApplicationModuleListener is the sample event listener template for new application modules
+const ApplicationModuleListener = `package {{PACKAGE_NAME}}.application.{{MODULE_PACKAGE}}.listener;
+
+import lombok.extern.slf4j.Slf4j;
+import org.springframework.stereotype.Component;
+
+/**
+ * {{MODULE_CLASS}}事件监听器
+ *
+ * 监听 Domain 层发布的领域事件，参考 UserEventListener
+ *
+ * 使用示例：
+ *    @Async
+ *    @EventListener
+ *    public void handle{{MODULE_CLASS}}Created({{MODULE_CLASS}}CreatedEvent event) {
+ *        // 处理逻辑：发通知、同步数据等
+ *    }
+ */
+@Slf4j
+@Component
+public class {{MODULE_CLASS}}EventListener {
+}
+`
+
+// ApplicationModuleController is the sample controller template for new application modules
+const ApplicationModuleController = `package {{PACKAGE_NAME}}.adapter.rest.controller;
+
+import {{PACKAGE_NAME}}.application.{{MODULE_PACKAGE}}.service.{{MODULE_CLASS}}Service;
+import {{PACKAGE_NAME}}.common.response.Result;
+import lombok.RequiredArgsConstructor;
+import org.springframework.web.bind.annotation.GetMapping;
+import org.springframework.web.bind.annotation.RequestMapping;
+import org.springframework.web.bind.annotation.RestController;
+
+/**
+ * {{MODULE_CLASS}}控制器
+ */
+@RestController
+@RequestMapping("/api/{{MODULE_NAME}}")
+@RequiredArgsConstructor
+public class {{MODULE_CLASS}}Controller {
+
+    private final {{MODULE_CLASS}}Service {{MODULE_FIELD}}Service;
+
+    /**
+     * 示例接口
+     */
+    @GetMapping
+    public Result<String> execute() {
+        return Result.success({{MODULE_FIELD}}Service.execute());
+    }
+}
+`
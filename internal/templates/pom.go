@@ -39,8 +39,7 @@ const ParentPOM = `<?xml version="1.0" encoding="UTF-8"?>
         <spring-boot.version>4.0.0-RC1</spring-boot.version>
 
         <!-- 数据库 -->
-        <mybatis-plus.version>3.5.8</mybatis-plus.version>
-        <mysql.version>8.0.33</mysql.version>
+{{PERSISTENCE_VERSION_PROPERTY}}        <{{DB_DRIVER_VERSION_PROPERTY}}>{{DB_DRIVER_VERSION}}</{{DB_DRIVER_VERSION_PROPERTY}}>
         <hikaricp.version>6.0.0</hikaricp.version>
 
         <!-- 工具库 -->
@@ -109,18 +108,11 @@ const ParentPOM = `<?xml version="1.0" encoding="UTF-8"?>
                 <version>${project.version}</version>
             </dependency>
 
-            <!-- MyBatis Plus -->
+{{PERSISTENCE_MANAGED_DEPENDENCY}}            <!-- JDBC 驱动 -->
             <dependency>
-                <groupId>com.baomidou</groupId>
-                <artifactId>mybatis-plus-spring-boot3-starter</artifactId>
-                <version>${mybatis-plus.version}</version>
-            </dependency>
-
-            <!-- MySQL -->
-            <dependency>
-                <groupId>com.mysql</groupId>
-                <artifactId>mysql-connector-j</artifactId>
-                <version>${mysql.version}</version>
+                <groupId>{{DB_DRIVER_GROUP_ID}}</groupId>
+                <artifactId>{{DB_DRIVER_ARTIFACT_ID}}</artifactId>
+                <version>${{{DB_DRIVER_VERSION_PROPERTY}}}</version>
             </dependency>
 
             <!-- HikariCP -->
@@ -358,14 +350,14 @@ const InfrastructurePOM = `<?xml version="1.0" encoding="UTF-8"?>
             <artifactId>common</artifactId>
         </dependency>
         <dependency>
-            <groupId>com.baomidou</groupId>
-            <artifactId>mybatis-plus-spring-boot3-starter</artifactId>
+            <groupId>{{PERSISTENCE_GROUP_ID}}</groupId>
+            <artifactId>{{PERSISTENCE_ARTIFACT_ID}}</artifactId>
         </dependency>
         <dependency>
-            <groupId>com.mysql</groupId>
-            <artifactId>mysql-connector-j</artifactId>
+            <groupId>{{DB_DRIVER_GROUP_ID}}</groupId>
+            <artifactId>{{DB_DRIVER_ARTIFACT_ID}}</artifactId>
         </dependency>
-        <dependency>
+{{MIGRATION_DEPENDENCIES}}        <dependency>
             <groupId>org.redisson</groupId>
             <artifactId>redisson-spring-boot-starter</artifactId>
             <optional>true</optional>
@@ -574,7 +566,35 @@ const StarterPOM = `<?xml version="1.0" encoding="UTF-8"?>
     </dependencies>
 
     <build>
+        <resources>
+            <!-- application*.yml 中的 @spring.profiles.active@ 由 Maven profile 填充 -->
+            <resource>
+                <directory>src/main/resources</directory>
+                <filtering>true</filtering>
+                <includes>
+                    <include>application*.yml</include>
+                </includes>
+            </resource>
+            <resource>
+                <directory>src/main/resources</directory>
+                <filtering>false</filtering>
+                <excludes>
+                    <exclude>application*.yml</exclude>
+                </excludes>
+            </resource>
+        </resources>
         <plugins>
+            <plugin>
+                <groupId>org.apache.maven.plugins</groupId>
+                <artifactId>maven-resources-plugin</artifactId>
+                <configuration>
+                    <!-- 只替换 @...@，保留 Spring 的 ${...} 占位符 -->
+                    <delimiters>
+                        <delimiter>@</delimiter>
+                    </delimiters>
+                    <useDefaultDelimiters>false</useDefaultDelimiters>
+                </configuration>
+            </plugin>
             <plugin>
                 <groupId>org.springframework.boot</groupId>
                 <artifactId>spring-boot-maven-plugin</artifactId>
@@ -640,3 +660,129 @@ const ApplicationModulePOM = `<?xml version="1.0" encoding="UTF-8"?>
     </dependencies>
 </project>
 `
+
+// DomainContextPOM is the template for the domain module of a bounded context
+const DomainContextPOM = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0"
+         xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0
+         http://maven.apache.org/xsd/maven-4.0.0.xsd">
+    <modelVersion>4.0.0</modelVersion>
+
+    <parent>
+        <groupId>{{GROUP_ID}}</groupId>
+        <artifactId>{{ARTIFACT_ID}}</artifactId>
+        <version>{{VERSION}}</version>
+    </parent>
+
+    <artifactId>domain-{{CONTEXT_NAME}}</artifactId>
+    <packaging>jar</packaging>
+    <name>domain-{{CONTEXT_NAME}}</name>
+    <description>{{CONTEXT_DESCRIPTION}}领域层</description>
+
+    <dependencies>
+        <dependency>
+            <groupId>{{GROUP_ID}}</groupId>
+            <artifactId>common</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>org.projectlombok</groupId>
+            <artifactId>lombok</artifactId>
+            <scope>provided</scope>
+        </dependency>
+    </dependencies>
+</project>
+`
+
+// InfrastructureContextPOM is the template for the infrastructure module of a bounded context
+const InfrastructureContextPOM = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0"
+         xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0
+         http://maven.apache.org/xsd/maven-4.0.0.xsd">
+    <modelVersion>4.0.0</modelVersion>
+
+    <parent>
+        <groupId>{{GROUP_ID}}</groupId>
+        <artifactId>{{ARTIFACT_ID}}</artifactId>
+        <version>{{VERSION}}</version>
+    </parent>
+
+    <artifactId>infrastructure-{{CONTEXT_NAME}}</artifactId>
+    <packaging>jar</packaging>
+    <name>infrastructure-{{CONTEXT_NAME}}</name>
+    <description>{{CONTEXT_DESCRIPTION}}基础设施层</description>
+
+    <dependencies>
+        <dependency>
+            <groupId>{{GROUP_ID}}</groupId>
+            <artifactId>domain-{{CONTEXT_NAME}}</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>{{GROUP_ID}}</groupId>
+            <artifactId>common</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>{{PERSISTENCE_GROUP_ID}}</groupId>
+            <artifactId>{{PERSISTENCE_ARTIFACT_ID}}</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>org.projectlombok</groupId>
+            <artifactId>lombok</artifactId>
+            <scope>provided</scope>
+        </dependency>
+    </dependencies>
+</project>
+`
+
+// ApplicationContextPOM is the template for the application module of a bounded context.
+// Unlike ApplicationModulePOM it only sees its own domain module, so the
+// context boundary is enforced by the compiler.
+const ApplicationContextPOM = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0"
+         xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0
+         http://maven.apache.org/xsd/maven-4.0.0.xsd">
+    <modelVersion>4.0.0</modelVersion>
+
+    <parent>
+        <groupId>{{GROUP_ID}}</groupId>
+        <artifactId>{{ARTIFACT_ID}}</artifactId>
+        <version>{{VERSION}}</version>
+        <relativePath>../../pom.xml</relativePath>
+    </parent>
+
+    <artifactId>application-{{CONTEXT_NAME}}</artifactId>
+    <packaging>jar</packaging>
+    <name>application-{{CONTEXT_NAME}}</name>
+    <description>{{CONTEXT_DESCRIPTION}}业务应用层</description>
+
+    <dependencies>
+        <dependency>
+            <groupId>{{GROUP_ID}}</groupId>
+            <artifactId>domain-{{CONTEXT_NAME}}</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>{{GROUP_ID}}</groupId>
+            <artifactId>common</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>org.springframework.boot</groupId>
+            <artifactId>spring-boot-starter</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>org.springframework</groupId>
+            <artifactId>spring-tx</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>org.mapstruct</groupId>
+            <artifactId>mapstruct</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>org.projectlombok</groupId>
+            <artifactId>lombok</artifactId>
+            <scope>provided</scope>
+        </dependency>
+    </dependencies>
+</project>
+`
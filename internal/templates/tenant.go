@@ -0,0 +1,246 @@
+package templates
+
+// TenantContext is the template of the current tenant holder
+const TenantContext = `package {{PACKAGE_NAME}}.common.tenant;
+
+/**
+ * 当前租户上下文
+ *
+ * 请求线程中由 TenantFilter 设置，请求结束时清除；@Async 方法所在的线程由
+ * TenantTaskDecorator 从提交任务的线程传入。MyBatis-Plus 的多租户插件从这里
+ * 读取租户ID，为 SQL 追加 tenant_id 条件
+ */
+public final class TenantContext {
+
+    private static final ThreadLocal<String> TENANT_ID = new ThreadLocal<>();
+
+    private TenantContext() {
+    }
+
+    /**
+     * 当前租户ID，没有租户上下文时返回 null
+     */
+    public static String getTenantId() {
+        return TENANT_ID.get();
+    }
+
+    /**
+     * 当前租户ID，没有租户上下文时抛出异常，避免在不隔离租户的情况下访问数据
+     */
+    public static String requireTenantId() {
+        String tenantId = TENANT_ID.get();
+        if (tenantId == null) {
+            throw new IllegalStateException("当前线程没有租户上下文");
+        }
+        return tenantId;
+    }
+
+    public static void setTenantId(String tenantId) {
+        if (tenantId == null) {
+            TENANT_ID.remove();
+        } else {
+            TENANT_ID.set(tenantId);
+        }
+    }
+
+    public static void clear() {
+        TENANT_ID.remove();
+    }
+
+    /**
+     * 以指定租户执行，结束后恢复原来的租户，例如定时任务逐个处理各租户的数据
+     */
+    public static void runAs(String tenantId, Runnable action) {
+        String previous = TENANT_ID.get();
+        setTenantId(tenantId);
+        try {
+            action.run();
+        } finally {
+            setTenantId(previous);
+        }
+    }
+}
+`
+
+// TenantTaskDecorator is the template of the task decorator propagating
+// the tenant to async threads
+const TenantTaskDecorator = `package {{PACKAGE_NAME}}.common.tenant;
+
+import org.springframework.core.task.TaskDecorator;
+import org.springframework.stereotype.Component;
+
+/**
+ * 把租户上下文传入异步线程
+ *
+ * ThreadLocal 不会跟随任务进入线程池，@Async 监听器（例如 UserEventListener）
+ * 会丢失租户。Spring Boot 自动配置的任务执行器会使用这个 TaskDecorator：
+ * 提交任务时记下当前租户，在执行任务的线程中恢复，执行完再清除
+ */
+@Component
+public class TenantTaskDecorator implements TaskDecorator {
+
+    @Override
+    public Runnable decorate(Runnable runnable) {
+        String tenantId = TenantContext.getTenantId();
+        return () -> TenantContext.runAs(tenantId, runnable);
+    }
+}
+`
+
+// TenantFilter is the template of the filter resolving the tenant of a request
+const TenantFilter = `package {{PACKAGE_NAME}}.adapter.rest.filter;
+
+import {{PACKAGE_NAME}}.common.tenant.TenantContext;
+import jakarta.servlet.*;
+import jakarta.servlet.http.HttpServletRequest;
+import jakarta.servlet.http.HttpServletResponse;
+import lombok.extern.slf4j.Slf4j;
+import org.springframework.beans.factory.annotation.Value;
+import org.springframework.core.Ordered;
+import org.springframework.core.annotation.Order;
+import org.springframework.stereotype.Component;
+import java.io.IOException;
+import java.nio.charset.StandardCharsets;
+import java.util.Base64;
+import java.util.regex.Matcher;
+import java.util.regex.Pattern;
+
+/**
+ * 租户过滤器
+ *
+ * 从请求头（默认 X-Tenant-Id）解析租户，放入 TenantContext，请求结束时清除。
+ * /api/ 下的接口（健康检查除外）缺少租户时返回 400。
+ *
+ * 注意：客户端可以随意设置请求头，该请求头必须只由网关在认证之后设置，网关需要
+ * 剔除外部请求自带的该请求头；应用直接对外暴露时应改为从认证后的用户解析租户。
+ *
+ * 配置 app.tenant.claim 后改为从令牌（Authorization: Bearer <JWT>）的该声明读取
+ * 租户，不再读取请求头。这里不校验令牌签名，只有在网关或前置的认证过滤器已经
+ * 校验过令牌时才能启用，否则任何人都可以伪造令牌冒充其他租户
+ */
+@Slf4j
+@Component
+@Order(Ordered.HIGHEST_PRECEDENCE + 1)
+public class TenantFilter implements Filter {
+
+    private static final String BEARER_PREFIX = "Bearer ";
+
+    private final String header;
+    /**
+     * 令牌中租户声明的匹配规则，未配置 app.tenant.claim 时为 null
+     */
+    private final Pattern claimPattern;
+
+    public TenantFilter(@Value("${app.tenant.header:X-Tenant-Id}") String header,
+                        @Value("${app.tenant.claim:}") String claim) {
+        this.header = header;
+        this.claimPattern = claim.isBlank()
+                ? null
+                : Pattern.compile("\"" + Pattern.quote(claim.trim()) + "\"\\s*:\\s*\"?([\\w-]+)\"?");
+    }
+
+    @Override
+    public void doFilter(ServletRequest request, ServletResponse response, FilterChain chain)
+            throws IOException, ServletException {
+        HttpServletRequest httpRequest = (HttpServletRequest) request;
+        String tenantId = resolveTenant(httpRequest);
+        if (tenantId == null && requiresTenant(httpRequest)) {
+            log.warn("Request without tenant: {} {}", httpRequest.getMethod(), httpRequest.getRequestURI());
+            ((HttpServletResponse) response).sendError(HttpServletResponse.SC_BAD_REQUEST, "缺少租户信息");
+            return;
+        }
+
+        TenantContext.setTenantId(tenantId);
+        try {
+            chain.doFilter(request, response);
+        } finally {
+            TenantContext.clear();
+        }
+    }
+
+    private boolean requiresTenant(HttpServletRequest request) {
+        String uri = request.getRequestURI();
+        return uri.startsWith("/api/") && !uri.startsWith("/api/health");
+    }
+
+    private String resolveTenant(HttpServletRequest request) {
+        if (claimPattern != null) {
+            return tenantFromToken(request.getHeader("Authorization"));
+        }
+        String tenantId = request.getHeader(header);
+        return tenantId == null || tenantId.isBlank() ? null : tenantId.trim();
+    }
+
+    /**
+     * 读取 JWT 载荷中的租户声明。只解码载荷，不校验签名，令牌必须已由网关或认证过滤器校验
+     */
+    private String tenantFromToken(String authorization) {
+        if (authorization == null || !authorization.startsWith(BEARER_PREFIX)) {
+            return null;
+        }
+        String[] parts = authorization.substring(BEARER_PREFIX.length()).split("\\.");
+        if (parts.length != 3) {
+            return null;
+        }
+        try {
+            String payload = new String(Base64.getUrlDecoder().decode(parts[1]), StandardCharsets.UTF_8);
+            Matcher matcher = claimPattern.matcher(payload);
+            return matcher.find() ? matcher.group(1) : null;
+        } catch (IllegalArgumentException e) {
+            log.debug("Invalid token payload", e);
+            return null;
+        }
+    }
+}
+`
+
+// MybatisTenantLineHandler is the template of the MyBatis-Plus tenant line
+// handler
+const MybatisTenantLineHandler = `package {{PACKAGE_NAME}}.infrastructure.config;
+
+import {{PACKAGE_NAME}}.common.tenant.TenantContext;
+import com.baomidou.mybatisplus.extension.plugins.handler.TenantLineHandler;
+import net.sf.jsqlparser.expression.Expression;
+import net.sf.jsqlparser.expression.StringValue;
+import org.springframework.beans.factory.annotation.Value;
+import org.springframework.stereotype.Component;
+
+import java.util.List;
+import java.util.Set;
+import java.util.stream.Collectors;
+
+/**
+ * 多租户行级隔离
+ *
+ * 由 TenantLineInnerInterceptor 调用：查询、更新、删除语句追加 tenant_id 条件，
+ * 插入语句写入 tenant_id。app.tenant.ignore-tables 中的表（例如各租户共用的
+ * 字典表）不做隔离
+ */
+@Component
+public class MybatisTenantLineHandler implements TenantLineHandler {
+
+    private final Set<String> ignoreTables;
+
+    public MybatisTenantLineHandler(@Value("${app.tenant.ignore-tables:}") List<String> ignoreTables) {
+        this.ignoreTables = ignoreTables.stream()
+            .map(String::trim)
+            .map(String::toLowerCase)
+            .collect(Collectors.toSet());
+    }
+
+    @Override
+    public Expression getTenantId() {
+        return new StringValue(TenantContext.requireTenantId());
+    }
+
+    @Override
+    public String getTenantIdColumn() {
+        return "tenant_id";
+    }
+
+    @Override
+    public boolean ignoreTable(String tableName) {
+        return ignoreTables.contains(tableName.toLowerCase());
+    }
+}
+`
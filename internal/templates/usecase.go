@@ -0,0 +1,150 @@
+package templates
+
+// UseCaseCommand is the use case command template
+const UseCaseCommand = `package {{PACKAGE_NAME}}.application.{{MODULE_PACKAGE}}.dto;
+
+import lombok.Data;
+
+/**
+ * {{USECASE_CLASS}}用例命令
+ */
+@Data
+public class {{USECASE_CLASS}}Command {
+{{COMMAND_FIELDS}}}
+`
+
+// UseCaseExecutor is the use case executor template
+const UseCaseExecutor = `package {{PACKAGE_NAME}}.application.{{MODULE_PACKAGE}}.executor;
+
+import {{PACKAGE_NAME}}.application.{{MODULE_PACKAGE}}.dto.{{USECASE_CLASS}}Command;
+import lombok.RequiredArgsConstructor;
+import lombok.extern.slf4j.Slf4j;
+import org.springframework.stereotype.Component;
+import org.springframework.transaction.annotation.Transactional;
+
+/**
+ * {{USECASE_CLASS}}用例执行器
+ *
+ * 在这里编排完整的业务流程：
+ * 1. 调用 Domain Service 执行核心领域逻辑
+ * 2. 调用 Repository 持久化聚合根
+ * 3. 发布领域事件，由事件监听器完成后续的异步处理
+ */
+@Slf4j
+@Component
+@RequiredArgsConstructor
+public class {{USECASE_CLASS}}Executor {
+
+    /**
+     * 执行{{USECASE_CLASS}}用例
+     */
+    @Transactional(rollbackFor = Exception.class)
+    public void execute({{USECASE_CLASS}}Command command) {
+        log.info("执行{{USECASE_CLASS}}用例: {}", command);
+
+        // TODO: 编排业务流程
+    }
+}
+`
+
+// UseCaseServiceMethod is the delegating method inserted into the module service
+const UseCaseServiceMethod = `    /**
+     * {{USECASE_CLASS}}
+     *
+     * 复杂业务用例，委托给 {{USECASE_CLASS}}Executor 执行，事务由 Executor 控制
+     */
+    public void {{USECASE_METHOD}}({{USECASE_CLASS}}Command command) {
+        {{USECASE_METHOD}}Executor.execute(command);
+    }
+`
+
+// UseCaseService is the module service template used when the module has no service yet
+const UseCaseService = `package {{PACKAGE_NAME}}.application.{{MODULE_PACKAGE}}.service;
+
+import lombok.RequiredArgsConstructor;
+import lombok.extern.slf4j.Slf4j;
+import org.springframework.stereotype.Service;
+
+/**
+ * {{MODULE_CLASS}}业务服务
+ */
+@Slf4j
+@Service
+@RequiredArgsConstructor
+public class {{MODULE_CLASS}}Service {
+}
+`
+
+// UseCaseRequest is the use case request VO template
+const UseCaseRequest = `package {{PACKAGE_NAME}}.adapter.rest.request;
+
+import lombok.Data;
+
+/**
+ * {{USECASE_CLASS}}请求
+ */
+@Data
+public class {{USECASE_CLASS}}Request {
+}
+`
+
+// UseCaseController is the controller template used when the module has no controller yet
+const UseCaseController = `package {{PACKAGE_NAME}}.adapter.rest.controller;
+
+import {{PACKAGE_NAME}}.application.{{MODULE_PACKAGE}}.service.{{MODULE_CLASS}}Service;
+import lombok.RequiredArgsConstructor;
+import org.springframework.web.bind.annotation.RestController;
+
+/**
+ * {{MODULE_CLASS}}控制器
+ */
+@RestController
+@RequiredArgsConstructor
+public class {{MODULE_CLASS}}Controller {
+
+    private final {{MODULE_CLASS}}Service {{MODULE_FIELD}}Service;
+}
+`
+
+// UseCaseControllerMethod is the endpoint inserted into the module controller
+const UseCaseControllerMethod = `    /**
+     * {{USECASE_CLASS}}
+     */
+    {{HTTP_MAPPING}}
+    public Result<Void> {{USECASE_METHOD}}({{CONTROLLER_PARAMS}}) {
+        {{USECASE_CLASS}}Command command = new {{USECASE_CLASS}}Command();
+{{COMMAND_ASSIGNMENTS}}        // TODO: 将 request 中的字段映射到 command
+        {{MODULE_FIELD}}Service.{{USECASE_METHOD}}(command);
+        return Result.success();
+    }
+`
+
+// UseCaseExecutorTest is the executor unit test skeleton template
+const UseCaseExecutorTest = `package {{PACKAGE_NAME}}.application.{{MODULE_PACKAGE}}.executor;
+
+import {{PACKAGE_NAME}}.application.{{MODULE_PACKAGE}}.dto.{{USECASE_CLASS}}Command;
+import org.junit.jupiter.api.Test;
+import org.junit.jupiter.api.extension.ExtendWith;
+import org.mockito.InjectMocks;
+import org.mockito.junit.jupiter.MockitoExtension;
+
+import static org.junit.jupiter.api.Assertions.assertDoesNotThrow;
+
+/**
+ * {{USECASE_CLASS}}Executor 单元测试
+ */
+@ExtendWith(MockitoExtension.class)
+class {{USECASE_CLASS}}ExecutorTest {
+
+    @InjectMocks
+    private {{USECASE_CLASS}}Executor executor;
+
+    @Test
+    void execute() {
+        {{USECASE_CLASS}}Command command = new {{USECASE_CLASS}}Command();
+
+        // TODO: 准备测试数据并 mock 依赖
+        assertDoesNotThrow(() -> executor.execute(command));
+    }
+}
+`
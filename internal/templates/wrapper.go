@@ -0,0 +1,20 @@
+package templates
+
+import _ "embed"
+
+// MavenWrapperScript is the mvnw script for Unix shells. The wrapper scripts are
+// kept as files since they contain backquotes.
+//
+//go:embed wrapper/mvnw
+var MavenWrapperScript string
+
+// MavenWrapperCmd is the mvnw.cmd script for Windows, stored with LF line endings
+//
+//go:embed wrapper/mvnw.cmd
+var MavenWrapperCmd string
+
+// MavenWrapperProperties is the .mvn/wrapper/maven-wrapper.properties template
+const MavenWrapperProperties = `wrapperVersion=3.3.2
+distributionType=only-script
+distributionUrl=https://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/{{MAVEN_VERSION}}/apache-maven-{{MAVEN_VERSION}}-bin.zip
+`